@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/go-chi/chi/v5"
+)
+
+// testAPI serves the API routes the tests use against a real database.
+type testAPI struct {
+	cfg    *apiConfig
+	clock  *clock.Fake
+	conn   *sql.DB
+	router http.Handler
+}
+
+// newTestAPI returns an API backed by the database at TEST_DATABASE_URL,
+// migrating it first if it's empty, and skips the test if that isn't set.
+// Tests share the database, so each one works with users of its own.
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	conn, err := sql.Open("libsql", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	migrateTestDatabase(t, conn)
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	cfg := &apiConfig{
+		DB:            database.NewStore(database.NewContextDB(conn)),
+		Conn:          conn,
+		Events:        events.Nop{},
+		Audit:         audit.Nop{},
+		Clock:         fake,
+		reactionEmoji: parseReactionEmoji(defaultReactionEmoji),
+		quickCache:    newQuickCache(quickCacheTTL, quickCacheSize, fake),
+		noteViews:     newNoteViewCounts(fake),
+	}
+	cfg.defaultDB = cfg.DB
+
+	r := chi.NewRouter()
+	r.Use(cfg.middlewareDryRun)
+	r.Post("/v1/users", handle(cfg.handlerUsersCreate))
+	r.Get("/v1/notes", cfg.middlewareAuth(cfg.handlerNotesGet))
+	r.Post("/v1/notes", cfg.middlewareAuth(cfg.requireWritable(cfg.handlerNotesCreate)))
+	r.Post("/v1/notes/bulk", cfg.middlewareAuth(cfg.requireWritable(cfg.handlerNotesBulkCreate)))
+	r.Delete("/v1/notes/{noteID}", cfg.middlewareAuth(cfg.handlerNoteDelete))
+	r.Post("/v1/notes/{noteID}/restore", cfg.middlewareAuth(cfg.handlerNoteRestore))
+	r.Put("/v1/notes/{noteID}/tags", cfg.middlewareAuth(cfg.requireWritable(cfg.handlerNoteTagsSet)))
+	r.Post("/v1/notes/{noteID}/pin", cfg.middlewareAuth(cfg.requireWritable(cfg.handlerNotePin)))
+	r.Post("/v1/notes/{noteID}/unpin", cfg.middlewareAuth(cfg.requireWritable(cfg.handlerNoteUnpin)))
+	r.Get("/v1/quick", cfg.middlewareAuth(cfg.handlerQuick))
+	r.Get("/v1/keys", cfg.middlewareAuth(cfg.handlerKeysGet))
+	r.Post("/v1/keys/{keyID}/rotate", cfg.middlewareAuth(cfg.handlerKeysRotate))
+	r.Post("/v1/publish", cfg.middlewareAuth(cfg.requireWritable(cfg.handlerNotesPublish)))
+	return &testAPI{cfg: cfg, clock: fake, conn: conn, router: r}
+}
+
+// migrateTestDatabase applies the Up sections of the migrations in
+// sql/schema unless the database already has a notes table.
+func migrateTestDatabase(t *testing.T, conn *sql.DB) {
+	t.Helper()
+	var tables int
+	err := conn.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'notes'").Scan(&tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tables > 0 {
+		return
+	}
+	files, err := filepath.Glob("sql/schema/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, stmt := range upStatements(string(data)) {
+			if _, err := conn.Exec(stmt); err != nil {
+				t.Fatalf("%s: %v\n%s", file, err, stmt)
+			}
+		}
+	}
+}
+
+// upStatements splits the Up section of a goose migration into statements.
+func upStatements(migration string) []string {
+	var stmts []string
+	var stmt strings.Builder
+	block := false
+	for _, line := range strings.Split(migration, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "-- +goose Down"):
+			return stmts
+		case strings.HasPrefix(trimmed, "-- +goose StatementBegin"):
+			block = true
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose StatementEnd"):
+			block = false
+			stmts = append(stmts, stmt.String())
+			stmt.Reset()
+			continue
+		case strings.HasPrefix(trimmed, "--") && !block:
+			continue
+		}
+		stmt.WriteString(line + "\n")
+		if !block && strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, stmt.String())
+			stmt.Reset()
+		}
+	}
+	return stmts
+}
+
+// do sends a request with body encoded as JSON, authenticated with apiKey
+// unless it's "", and decodes the response into out unless it's nil.
+func (api *testAPI) do(t *testing.T, method, target, apiKey string, body, out any) int {
+	t.Helper()
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := httptest.NewRequest(method, target, &reqBody)
+	if apiKey != "" {
+		r.Header.Set("Authorization", "ApiKey "+apiKey)
+	}
+	w := httptest.NewRecorder()
+	api.router.ServeHTTP(w, r)
+	if out != nil && w.Code < 300 {
+		if err := json.NewDecoder(w.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: couldn't decode %d response: %v", method, target, w.Code, err)
+		}
+	}
+	return w.Code
+}
+
+// newUser creates a user and returns it with its API key.
+func (api *testAPI) newUser(t *testing.T) (User, string) {
+	t.Helper()
+	var user User
+	if code := api.do(t, http.MethodPost, "/v1/users", "", map[string]string{"name": "test"}, &user); code != http.StatusCreated {
+		t.Fatalf("creating user: status %d", code)
+	}
+	return user, user.ApiKey
+}
+
+// newNote creates a note for the user with apiKey, a second after the
+// previous one, so notes are in a known newest-first order.
+func (api *testAPI) newNote(t *testing.T, apiKey, body string) Note {
+	t.Helper()
+	api.clock.Advance(time.Second)
+	var note Note
+	if code := api.do(t, http.MethodPost, "/v1/notes", apiKey, map[string]string{"note": body}, &note); code != http.StatusCreated {
+		t.Fatalf("creating note: status %d", code)
+	}
+	return note
+}
+
+// noteBodies returns the bodies of notes, in order.
+func noteBodies(notes []Note) []string {
+	bodies := make([]string, len(notes))
+	for i, note := range notes {
+		bodies[i] = note.Note
+	}
+	return bodies
+}
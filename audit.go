@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/ctxkeys"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/tenancy"
+)
+
+// logSecurityEvent writes e to the security log, filling in its time and,
+// if r is set, where the request came from and the tenant it's for.
+// Successes in a dry run are skipped since nothing changed, but failures
+// are still real attempts.
+// Failures to write are only logged, like publishEvent's. Authentication
+// failures also count towards their alert.
+func (cfg *apiConfig) logSecurityEvent(r *http.Request, e audit.Event) {
+	if e.Type == audit.TypeAuthFailure {
+		cfg.Alerts.RecordAuthFailure()
+	}
+	if e.Outcome == "" {
+		e.Outcome = audit.OutcomeSuccess
+	}
+	if r != nil && e.Outcome == audit.OutcomeSuccess && isDryRun(r.Context()) {
+		return
+	}
+	e.Time = cfg.Clock.Now().UTC()
+	e.Schema = audit.Schema
+	if r != nil {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		e.Source = &audit.Source{IP: ip, UserAgent: r.UserAgent()}
+		e.Request = &audit.Request{
+			ID:     ctxkeys.RequestID(r.Context()),
+			Method: r.Method,
+			Path:   r.URL.Path,
+		}
+		if conn := tenancy.FromContext(r.Context()); conn != nil {
+			e.Tenant = &audit.Tenant{ID: conn.ID, Region: conn.Region}
+		}
+	}
+	if err := cfg.Audit.Log(e); err != nil {
+		log.Printf("Couldn't log %s security event: %v", e.Type, err)
+	}
+}
+
+// auditFailureReason is the client-facing message of err, which is what a
+// failed admin action is logged with.
+func auditFailureReason(err error) string {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Msg
+	}
+	return "Internal server error"
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// authCacheSize is how many API key resolutions are kept in memory.
+const authCacheSize = 10000
+
+// authCache remembers which user an API key belongs to for a short while, so
+// authenticated requests don't each cost a round trip to the database. A
+// user's entry is dropped whenever their row or keys change on this
+// instance, so the TTL only bounds how long changes made through other
+// instances can go unnoticed.
+//
+// Entries are tagged with the tenant whose database the user was found in,
+// so a key is only ever matched against its own tenant's users.
+//
+// Only a user's current key is cached. Rotated keys still in their grace
+// period expire on their own schedule, so they're looked up every time.
+// A nil *authCache caches nothing.
+type authCache struct {
+	mu      sync.Mutex
+	gen     uint64 // Bumped by forget, so lookups racing it aren't cached.
+	entries *ttlMap[string, authCacheEntry]
+	byUser  map[string]string // tenantUserKey to the user's cached key.
+}
+
+type authCacheEntry struct {
+	tenant  string
+	userKey string
+	user    database.User
+}
+
+// newAuthCache returns nil, disabling the cache, if ttl isn't positive.
+func newAuthCache(ttl time.Duration, size int, clock clock.Clock) *authCache {
+	if ttl <= 0 {
+		return nil
+	}
+	c := &authCache{byUser: make(map[string]string)}
+	c.entries = newTTLMap(ttl, size, clock, func(apiKey string, entry authCacheEntry) {
+		if c.byUser[entry.userKey] == apiKey {
+			delete(c.byUser, entry.userKey)
+		}
+	})
+	return c
+}
+
+// get returns the user apiKey belongs to in ctx's tenant, if cached.
+// Otherwise it returns the generation to pass to put along with the looked
+// up user.
+func (c *authCache) get(ctx context.Context, apiKey string) (database.User, uint64, bool) {
+	if c == nil {
+		return database.User{}, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries.get(apiKey)
+	if !ok || entry.tenant != tenantID(ctx) {
+		return database.User{}, c.gen, false
+	}
+	return entry.user, c.gen, true
+}
+
+// put caches user of ctx's tenant for apiKey unless the key isn't the
+// user's current one, or the user might have changed since generation gen
+// was handed out.
+func (c *authCache) put(ctx context.Context, apiKey string, user database.User, gen uint64) {
+	if c == nil || apiKey != user.ApiKey {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	userKey := tenantUserKey(ctx, user.ID)
+	c.forgetLocked(userKey)
+	c.entries.put(apiKey, authCacheEntry{tenant: tenantID(ctx), userKey: userKey, user: user})
+	c.byUser[userKey] = apiKey
+}
+
+// forget drops userID's cached key, e.g. after their profile changed or a
+// key of theirs was rotated.
+func (c *authCache) forget(ctx context.Context, userID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.forgetLocked(tenantUserKey(ctx, userID))
+}
+
+func (c *authCache) forgetLocked(userKey string) {
+	if apiKey, ok := c.byUser[userKey]; ok {
+		c.entries.delete(apiKey)
+		delete(c.byUser, userKey)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/stripe"
+)
+
+// maxWebhookSize bounds the body of a Stripe webhook request.
+const maxWebhookSize = 1 << 20
+
+// handlerStripeWebhook applies Stripe events to users' plans. Completed
+// checkouts link the paying user, named by the checkout's
+// client_reference_id, to their Stripe customer; subscription events then
+// put that user on the plan of the subscription's price, and back on free
+// when it ends. Events the API can't act on are acknowledged and logged,
+// while database failures get a 500 so Stripe delivers the event again.
+func (cfg *apiConfig) handlerStripeWebhook(w http.ResponseWriter, r *http.Request) error {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookSize))
+	if err != nil {
+		return errValidation("Couldn't read body", err)
+	}
+	event, err := cfg.Stripe.ParseEvent(payload, r.Header.Get("Stripe-Signature"))
+	if err != nil {
+		return errValidation("Couldn't verify event", err)
+	}
+
+	switch event.Type {
+	case stripe.EventCheckoutCompleted:
+		var session stripe.CheckoutSession
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return errValidation("Couldn't decode checkout session", err)
+		}
+		err = cfg.linkStripeCustomer(r.Context(), event, session)
+	case stripe.EventSubscriptionCreated, stripe.EventSubscriptionUpdated, stripe.EventSubscriptionDeleted:
+		var sub stripe.Subscription
+		if err := json.Unmarshal(event.Data.Object, &sub); err != nil {
+			return errValidation("Couldn't decode subscription", err)
+		}
+		err = cfg.applySubscription(r.Context(), event, sub)
+	}
+	if err != nil {
+		return errInternal("Couldn't apply Stripe event "+event.ID, err)
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// linkStripeCustomer records the customer a checkout made for its user,
+// and applies the subscription it started, which may have been announced
+// before the customer was known.
+func (cfg *apiConfig) linkStripeCustomer(ctx context.Context, event stripe.Event, session stripe.CheckoutSession) error {
+	if session.ClientReferenceID == "" || session.Customer == "" {
+		log.Printf("Ignoring Stripe checkout %s without a client_reference_id or customer", session.ID)
+		return nil
+	}
+	user, err := cfg.DB.GetUserByID(ctx, session.ClientReferenceID)
+	if errors.Is(err, database.ErrNotFound) {
+		log.Printf("Ignoring Stripe checkout %s for unknown user %q", session.ID, session.ClientReferenceID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = cfg.DB.SetStripeCustomer(ctx, database.SetStripeCustomerParams{
+		UserID:           user.ID,
+		StripeCustomerID: session.Customer,
+		UpdatedAt:        cfg.Clock.Now().UTC().Format(time.RFC3339),
+	})
+	if errors.Is(err, database.ErrConflict) {
+		log.Printf("Ignoring Stripe checkout %s: customer %s belongs to another user", session.ID, session.Customer)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if session.Subscription == "" {
+		return nil
+	}
+	sub, err := cfg.Stripe.GetSubscription(ctx, session.Subscription)
+	if err != nil {
+		return err
+	}
+	return cfg.applySubscription(ctx, event, sub)
+}
+
+// applySubscription puts the customer of sub on its plan, or on free if it
+// has ended, unless a later event has been applied already.
+func (cfg *apiConfig) applySubscription(ctx context.Context, event stripe.Event, sub stripe.Subscription) error {
+	customer, err := cfg.DB.GetBillingCustomerByStripeID(ctx, sub.Customer)
+	if errors.Is(err, database.ErrNotFound) {
+		log.Printf("Ignoring Stripe event %s for unknown customer %s", event.ID, sub.Customer)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if event.Created < customer.LastEventAt {
+		log.Printf("Ignoring out-of-date Stripe event %s for customer %s", event.ID, sub.Customer)
+		return nil
+	}
+
+	planID := freePlan
+	if event.Type != stripe.EventSubscriptionDeleted && sub.Entitled() {
+		var ok bool
+		if planID, ok = cfg.Stripe.Plan(sub); !ok {
+			log.Printf("Ignoring Stripe subscription %s: none of its prices are in STRIPE_PRICE_PLANS", sub.ID)
+			return nil
+		}
+	}
+	if _, err := cfg.DB.GetPlan(ctx, planID); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			log.Printf("Ignoring Stripe subscription %s: there's no plan %q", sub.ID, planID)
+			return nil
+		}
+		return err
+	}
+
+	tx, err := cfg.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	now := cfg.Clock.Now().UTC().Format(time.RFC3339)
+	err = tx.SetUserPlan(ctx, database.SetUserPlanParams{
+		UserID:    customer.UserID,
+		PlanID:    planID,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	err = tx.SetSubscriptionStatus(ctx, database.SetSubscriptionStatusParams{
+		SubscriptionStatus: sql.NullString{String: sub.Status, Valid: true},
+		LastEventAt:        event.Created,
+		UpdatedAt:          now,
+		UserID:             customer.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// handlerBillingPortal starts a Stripe customer portal session for the
+// user to change or cancel their subscription, and responds with its URL
+// for the client to open.
+func (cfg *apiConfig) handlerBillingPortal(w http.ResponseWriter, r *http.Request, user database.User) error {
+	customer, err := cfg.DB.GetBillingCustomer(r.Context(), user.ID)
+	if errors.Is(err, database.ErrNotFound) {
+		return errNotFound("You don't have a subscription to manage", nil)
+	}
+	if err != nil {
+		return errInternal("Couldn't get billing customer", err)
+	}
+	url, err := cfg.Stripe.CreatePortalSession(r.Context(), customer.StripeCustomerID, cfg.BillingReturnURL)
+	if err != nil {
+		return errInternal("Couldn't start billing portal session", err)
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"url": url})
+	return nil
+}
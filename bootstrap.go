@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/auth"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// bootstrapFile is the document read from BOOTSTRAP_FILE at startup, e.g.
+//
+//	{"users": [{"name": "ci", "api_key": "ntly_..."}]}
+type bootstrapFile struct {
+	Users []bootstrapUser `json:"users"`
+}
+
+type bootstrapUser struct {
+	Name   string `json:"name"`
+	APIKey string `json:"api_key"`
+}
+
+// applyBootstrap creates every user listed in the file whose API key doesn't
+// resolve to a user yet, so running it on every start is idempotent.
+func (cfg *apiConfig) applyBootstrap(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from operator configuration.
+	if err != nil {
+		return err
+	}
+	var file bootstrapFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for i, u := range file.Users {
+		if u.Name == "" || u.APIKey == "" {
+			return fmt.Errorf("users[%d]: name and api_key are required", i)
+		}
+		if err := auth.ValidateAPIKey(u.APIKey); err != nil {
+			return fmt.Errorf("users[%d]: %w", i, err)
+		}
+
+		now := cfg.Clock.Now().UTC().Format(time.RFC3339)
+		_, err := cfg.DB.GetUserByAPIKey(ctx, database.GetUserByAPIKeyParams{
+			ApiKey: u.APIKey,
+			Now:    sql.NullString{String: now, Valid: true},
+		})
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, database.ErrNotFound) {
+			return err
+		}
+
+		userID, keyID, err := cfg.createUserWithKey(ctx, u.Name, u.APIKey, now)
+		if err != nil {
+			return fmt.Errorf("users[%d]: %w", i, err)
+		}
+		cfg.logSecurityEvent(nil, audit.Event{
+			Type:   audit.TypeKeyCreated,
+			Reason: "bootstrap",
+			Actor:  audit.Actor{Type: audit.ActorSystem},
+			Target: &audit.Target{UserID: userID, KeyID: keyID},
+		})
+		log.Printf("Bootstrapped user %q", u.Name)
+	}
+	return nil
+}
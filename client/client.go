@@ -0,0 +1,443 @@
+// Package client is a typed Go client for the Notely API, so Go programs
+// don't have to build requests and decode responses by hand.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for new clients.
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 3
+	defaultRetryWait  = 500 * time.Millisecond
+)
+
+// maxRetryWait caps how long a single retry waits, whatever the server's
+// Retry-After says.
+const maxRetryWait = 30 * time.Second
+
+// Client calls the API at a base URL with an API key.
+//
+// Requests the server turned away without handling them are retried up to
+// MaxRetries times: 503s (the server is shedding load) and 429s that carry
+// a Retry-After. Network errors are only retried for GET, PUT and DELETE,
+// since a POST may have been applied before the connection failed.
+type Client struct {
+	// HTTPClient sends the requests.
+	HTTPClient *http.Client
+	// MaxRetries is how often a request is retried; 0 turns retries off.
+	MaxRetries int
+	// RetryWait is the wait before the first retry, doubling for each
+	// further one. A Retry-After from the server takes precedence.
+	RetryWait time.Duration
+
+	baseURL string
+	apiKey  string
+}
+
+// New creates a client for the server at baseURL, e.g.
+// "http://localhost:8080". apiKey is sent with every request; it may be
+// empty for CreateUser.
+func New(baseURL, apiKey string) *Client {
+	return &Client{
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+		MaxRetries: defaultMaxRetries,
+		RetryWait:  defaultRetryWait,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+	}
+}
+
+// Error is a response with an error status. Message is the server's
+// explanation, or the status text if it didn't send one.
+type Error struct {
+	StatusCode int
+	Code       string // Machine-readable reason, such as "UPGRADE_REQUIRED"; often empty.
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("notely: %d %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from the API.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsUpgradeRequired reports whether err is the API refusing an action the
+// user's plan doesn't allow, such as adding notes past its limit.
+func IsUpgradeRequired(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == "UPGRADE_REQUIRED"
+}
+
+// CreateUser creates a user called name. The returned user's APIKey
+// authenticates further requests through a client made with New.
+func (c *Client) CreateUser(ctx context.Context, name string) (User, error) {
+	var user User
+	err := c.do(ctx, http.MethodPost, "/v1/users", nil, map[string]string{"name": name}, &user)
+	return user, err
+}
+
+// GetUser returns the user the client's API key belongs to.
+func (c *Client) GetUser(ctx context.Context) (User, error) {
+	var user User
+	err := c.do(ctx, http.MethodGet, "/v1/users", nil, nil, &user)
+	return user, err
+}
+
+// CreateNoteParams are the fields of a new note.
+type CreateNoteParams struct {
+	Note       string     `json:"note"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	NotebookID *string    `json:"notebook_id,omitempty"`
+}
+
+// CreateNote saves a new note and returns it as stored.
+func (c *Client) CreateNote(ctx context.Context, params CreateNoteParams) (Note, error) {
+	var note Note
+	err := c.do(ctx, http.MethodPost, "/v1/notes", nil, params, &note)
+	return note, err
+}
+
+// CreateNotes saves many notes in one request, for imports. The results
+// match params item for item: each has the ID the note was created with, or
+// the reason it was skipped, which isn't an error. The notes that aren't
+// skipped are all saved or, if there's an error, none are.
+func (c *Client) CreateNotes(ctx context.Context, params []CreateNoteParams) ([]BulkNoteResult, error) {
+	var resp struct {
+		Results []BulkNoteResult `json:"results"`
+	}
+	err := c.do(ctx, http.MethodPost, "/v1/notes/bulk", nil, params, &resp)
+	return resp.Results, err
+}
+
+// ListNotes returns all of the user's notes. For accounts with many notes,
+// IterateNotes fetches them a page at a time.
+func (c *Client) ListNotes(ctx context.Context) ([]Note, error) {
+	var notes []Note
+	err := c.do(ctx, http.MethodGet, "/v1/notes", nil, nil, &notes)
+	return notes, err
+}
+
+// ListNotesPage returns up to limit of the user's notes, newest first,
+// skipping the first offset, along with how many notes there are in all.
+// A limit of 0 uses the server's default.
+func (c *Client) ListNotesPage(ctx context.Context, limit, offset int) (NotePage, error) {
+	params := url.Values{"offset": {strconv.Itoa(offset)}}
+	setLimit(params, limit)
+	var page NotePage
+	err := c.do(ctx, http.MethodGet, "/v1/notes", params, nil, &page)
+	return page, err
+}
+
+// IterateNotes walks the user's notes, newest first, fetching limit at a
+// time (0 for the server's default). Notes added while it runs don't make
+// it skip or repeat any, as paging with ListNotesPage's offsets can.
+func (c *Client) IterateNotes(limit int) *Iterator[Note] {
+	params := url.Values{"cursor": {""}}
+	setLimit(params, limit)
+	return newIterator[Note](c, "/v1/notes", params)
+}
+
+// GetNote returns one of the user's notes. Use IsNotFound to tell whether
+// it doesn't exist.
+func (c *Client) GetNote(ctx context.Context, id string) (Note, error) {
+	var note Note
+	err := c.do(ctx, http.MethodGet, "/v1/notes/"+url.PathEscape(id), nil, nil, &note)
+	return note, err
+}
+
+// DeleteNote moves one of the user's notes to the trash.
+func (c *Client) DeleteNote(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/notes/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListTrash returns the user's trashed notes, most recently deleted first.
+func (c *Client) ListTrash(ctx context.Context) ([]Note, error) {
+	var notes []Note
+	err := c.do(ctx, http.MethodGet, "/v1/notes/trash", nil, nil, &notes)
+	return notes, err
+}
+
+// RestoreNote takes a note out of the trash and returns it.
+func (c *Client) RestoreNote(ctx context.Context, id string) (Note, error) {
+	var note Note
+	err := c.do(ctx, http.MethodPost, "/v1/notes/"+url.PathEscape(id)+"/restore", nil, nil, &note)
+	return note, err
+}
+
+// PurgeNote deletes a trashed note for good.
+func (c *Client) PurgeNote(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/notes/trash/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ArchiveNote hides one of the user's notes from ListNotes and returns it.
+func (c *Client) ArchiveNote(ctx context.Context, id string) (Note, error) {
+	var note Note
+	err := c.do(ctx, http.MethodPost, "/v1/notes/"+url.PathEscape(id)+"/archive", nil, nil, &note)
+	return note, err
+}
+
+// UnarchiveNote puts an archived note back in ListNotes and returns it.
+func (c *Client) UnarchiveNote(ctx context.Context, id string) (Note, error) {
+	var note Note
+	err := c.do(ctx, http.MethodPost, "/v1/notes/"+url.PathEscape(id)+"/unarchive", nil, nil, &note)
+	return note, err
+}
+
+// ListArchivedNotes returns the user's archived notes, newest first.
+func (c *Client) ListArchivedNotes(ctx context.Context) ([]Note, error) {
+	var notes []Note
+	err := c.do(ctx, http.MethodGet, "/v1/notes", url.Values{"archived": {"true"}}, nil, &notes)
+	return notes, err
+}
+
+// PinNote puts one of the user's notes first in ListNotes and returns it.
+func (c *Client) PinNote(ctx context.Context, id string) (Note, error) {
+	var note Note
+	err := c.do(ctx, http.MethodPost, "/v1/notes/"+url.PathEscape(id)+"/pin", nil, nil, &note)
+	return note, err
+}
+
+// UnpinNote lists a pinned note among the others again and returns it.
+func (c *Client) UnpinNote(ctx context.Context, id string) (Note, error) {
+	var note Note
+	err := c.do(ctx, http.MethodPost, "/v1/notes/"+url.PathEscape(id)+"/unpin", nil, nil, &note)
+	return note, err
+}
+
+// SetNoteTags replaces the tags of one of the user's notes and returns the
+// note. Tags are lowercased and a leading # is dropped.
+func (c *Client) SetNoteTags(ctx context.Context, id string, tags []string) (Note, error) {
+	body := struct {
+		Tags []string `json:"tags"`
+	}{Tags: tags}
+	var note Note
+	err := c.do(ctx, http.MethodPut, "/v1/notes/"+url.PathEscape(id)+"/tags", nil, body, &note)
+	return note, err
+}
+
+// ListNotesWithTag returns all of the user's notes with tag.
+func (c *Client) ListNotesWithTag(ctx context.Context, tag string) ([]Note, error) {
+	var notes []Note
+	err := c.do(ctx, http.MethodGet, "/v1/notes", url.Values{"tag": {tag}}, nil, &notes)
+	return notes, err
+}
+
+// ListTags returns the user's tags in alphabetical order, with how many
+// notes have each.
+func (c *Client) ListTags(ctx context.Context) ([]TagCount, error) {
+	var tags []TagCount
+	err := c.do(ctx, http.MethodGet, "/v1/tags", nil, nil, &tags)
+	return tags, err
+}
+
+// BillingPortalURL starts a session of the billing portal, where the user
+// manages their subscription, and returns its URL to open in a browser.
+func (c *Client) BillingPortalURL(ctx context.Context) (string, error) {
+	var session struct {
+		URL string `json:"url"`
+	}
+	err := c.do(ctx, http.MethodGet, "/v1/billing/portal", nil, nil, &session)
+	return session.URL, err
+}
+
+// ListNotebooks returns all of the user's notebooks by name. Nested ones
+// have a ParentID.
+func (c *Client) ListNotebooks(ctx context.Context) ([]Notebook, error) {
+	var notebooks []Notebook
+	err := c.do(ctx, http.MethodGet, "/v1/notebooks", nil, nil, &notebooks)
+	return notebooks, err
+}
+
+// CreateNotebook adds a notebook named name, inside the notebook parentID
+// unless it's "".
+func (c *Client) CreateNotebook(ctx context.Context, name, parentID string) (Notebook, error) {
+	body := struct {
+		Name     string  `json:"name"`
+		ParentID *string `json:"parent_id,omitempty"`
+	}{Name: name}
+	if parentID != "" {
+		body.ParentID = &parentID
+	}
+	var notebook Notebook
+	err := c.do(ctx, http.MethodPost, "/v1/notebooks", nil, body, &notebook)
+	return notebook, err
+}
+
+// DeleteNotebook deletes a notebook and the notebooks inside it. Their
+// notes are kept, outside of any notebook.
+func (c *Client) DeleteNotebook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/notebooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListNotesInNotebook returns all of the user's notes in the notebook id.
+func (c *Client) ListNotesInNotebook(ctx context.Context, id string) ([]Note, error) {
+	var notes []Note
+	err := c.do(ctx, http.MethodGet, "/v1/notes", url.Values{"notebook_id": {id}}, nil, &notes)
+	return notes, err
+}
+
+// FindNotes returns the user's notes containing every word of query, best
+// match first, fetching limit at a time (0 for the server's default). A
+// word ending in * matches as a prefix.
+func (c *Client) FindNotes(query string, limit int) *Iterator[ScoredNote] {
+	params := url.Values{"q": {query}}
+	setLimit(params, limit)
+	return newIterator[ScoredNote](c, "/v1/notes/search", params)
+}
+
+// SearchNotes ranks the user's notes by semantic similarity to query, best
+// match first, fetching limit at a time (0 for the server's default). The
+// server must have embeddings enabled.
+func (c *Client) SearchNotes(query string, limit int) *Iterator[ScoredNote] {
+	params := url.Values{"q": {query}}
+	setLimit(params, limit)
+	return newIterator[ScoredNote](c, "/v1/notes/semantic-search", params)
+}
+
+// SuggestTitles returns the user's notes whose title starts with query, in
+// title order, or with fuzzy set, whose title resembles it, best match
+// first. Suggestions are fetched limit at a time (0 for the server's
+// default).
+func (c *Client) SuggestTitles(query string, fuzzy bool, limit int) *Iterator[TitleSuggestion] {
+	params := url.Values{"q": {query}}
+	if fuzzy {
+		params.Set("fuzzy", "true")
+	}
+	setLimit(params, limit)
+	return newIterator[TitleSuggestion](c, "/v1/notes/title-suggest", params)
+}
+
+func setLimit(params url.Values, limit int) {
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+}
+
+// do sends a request with body encoded as JSON, if not nil, and decodes the
+// response into out, retrying as described on Client.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, u, payload)
+		retry := attempt < c.MaxRetries && ctx.Err() == nil
+		wait := c.RetryWait << attempt
+		if err != nil {
+			if !retry || !idempotent(method) {
+				return err
+			}
+		} else {
+			var retryable bool
+			wait, retryable = retryAfter(resp, wait)
+			if !retry || !retryable {
+				return decodeResponse(resp, out)
+			}
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(min(wait, maxRetryWait))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, u string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+c.apiKey)
+	}
+	return c.HTTPClient.Do(req)
+}
+
+// retryAfter reports whether resp was turned away before being handled and
+// may be retried, and how long to wait first: the server's Retry-After if
+// it sent one, otherwise wait.
+func retryAfter(resp *http.Response, wait time.Duration) (time.Duration, bool) {
+	header := resp.Header.Get("Retry-After")
+	switch resp.StatusCode {
+	case http.StatusServiceUnavailable:
+	case http.StatusTooManyRequests:
+		// Without Retry-After, a 429 is a rule such as how often a
+		// username may change, which retrying won't get past.
+		if header == "" {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	return wait, true
+}
+
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+// decodeResponse decodes a successful response into out, or returns an
+// *Error for an error status.
+func decodeResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		var body struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &body) != nil || body.Error == "" {
+			body.Error = http.StatusText(resp.StatusCode)
+		}
+		return &Error{StatusCode: resp.StatusCode, Code: body.Code, Message: body.Error}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("notely: decoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCreateNote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/notes" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "ApiKey secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Couldn't validate API key"}`))
+			return
+		}
+		var params CreateNoteParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Note{ID: "n1", Note: params.Note, Title: params.Note})
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	note, err := New(srv.URL+"/", "secret").CreateNote(ctx, CreateNoteParams{Note: "hello"})
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+	if note.ID != "n1" || note.Note != "hello" {
+		t.Errorf("CreateNote() = %+v", note)
+	}
+
+	_, err = New(srv.URL, "wrong").CreateNote(ctx, CreateNoteParams{Note: "hello"})
+	apiErr, ok := err.(*Error)
+	if !ok || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "Couldn't validate API key" {
+		t.Errorf("CreateNote() with a wrong key error = %v, want a 401 *Error", err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		status     int
+		retryAfter string
+		failures   int32
+		wantCalls  int32
+		wantErr    bool
+	}{
+		{name: "success", method: http.MethodGet, status: http.StatusOK, wantCalls: 1},
+		{name: "shed requests are retried", method: http.MethodGet, status: http.StatusServiceUnavailable, retryAfter: "0", failures: 2, wantCalls: 3},
+		{name: "shed posts are retried", method: http.MethodPost, status: http.StatusServiceUnavailable, failures: 1, wantCalls: 2},
+		{name: "gives up after max retries", method: http.MethodGet, status: http.StatusServiceUnavailable, failures: 10, wantCalls: 3, wantErr: true},
+		{name: "rate limits with retry-after are retried", method: http.MethodGet, status: http.StatusTooManyRequests, retryAfter: "0", failures: 1, wantCalls: 2},
+		{name: "rate limits without retry-after aren't", method: http.MethodPut, status: http.StatusTooManyRequests, failures: 1, wantCalls: 1, wantErr: true},
+		{name: "client errors aren't retried", method: http.MethodGet, status: http.StatusBadRequest, failures: 1, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, "secret")
+			c.MaxRetries = 2
+			c.RetryWait = time.Millisecond
+			err := c.do(context.Background(), tt.method, "/v1/anything", nil, nil, &struct{}{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIterator(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"results":[{"id":"1","title":"a"},{"id":"2","title":"b"}],"meta":{"next_cursor":"c1"}}`,
+		"c1": `{"results":[{"id":"3","title":"c"}],"meta":{"next_cursor":"c2"}}`,
+		"c2": `{"results":[],"meta":{}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v1/notes/title-suggest" || q.Get("q") != "x" || q.Get("fuzzy") != "true" || q.Get("limit") != "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		page, ok := pages[q.Get("cursor")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Invalid cursor"}`))
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	it := New(srv.URL, "secret").SuggestTitles("x", true, 2)
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if len(got) != 3 || got[0] != "1" || got[1] != "2" || got[2] != "3" {
+		t.Errorf("IDs = %v, want [1 2 3]", got)
+	}
+	if it.Next(context.Background()) {
+		t.Error("Next() after the last page = true")
+	}
+}
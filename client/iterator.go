@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Iterator walks the results of a cursor-paginated endpoint, fetching the
+// next page when the current one runs out:
+//
+//	it := c.SearchNotes("trip", 0)
+//	for it.Next(ctx) {
+//		note := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	c      *Client
+	path   string
+	params url.Values
+
+	items  []T
+	cursor string
+	last   bool
+	item   T
+	err    error
+}
+
+type page[T any] struct {
+	Results []T `json:"results"`
+	Meta    struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"meta"`
+}
+
+func newIterator[T any](c *Client, path string, params url.Values) *Iterator[T] {
+	return &Iterator[T]{c: c, path: path, params: params}
+}
+
+// Next advances to the next result, fetching a page if needed. It returns
+// false when the results are exhausted or a request failed; Err tells which.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for len(it.items) == 0 {
+		if it.last || it.err != nil {
+			return false
+		}
+		params := url.Values{}
+		for k, v := range it.params {
+			params[k] = v
+		}
+		if it.cursor != "" {
+			params.Set("cursor", it.cursor)
+		}
+		var p page[T]
+		if err := it.c.do(ctx, http.MethodGet, it.path, params, nil, &p); err != nil {
+			it.err = err
+			return false
+		}
+		it.items, it.cursor = p.Results, p.Meta.NextCursor
+		it.last = it.cursor == ""
+	}
+	it.item, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Item returns the result Next advanced to.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
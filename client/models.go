@@ -0,0 +1,94 @@
+package client
+
+import "time"
+
+type User struct {
+	ID            string    `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Name          string    `json:"name"`
+	APIKey        string    `json:"api_key"`
+	Username      *string   `json:"username,omitempty"`
+	ProfilePublic bool      `json:"profile_public"`
+}
+
+type Note struct {
+	ID          string     `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Note        string     `json:"note"`
+	Title       string     `json:"title"`
+	UserID      string     `json:"user_id"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	SourceURL   *string    `json:"source_url,omitempty"`
+	SourceTitle *string    `json:"source_title,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Noindex     bool       `json:"noindex,omitempty"`
+	NotebookID  *string    `json:"notebook_id,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	Pinned      bool       `json:"pinned,omitempty"`
+
+	LinkPreviews []LinkPreview   `json:"link_previews,omitempty"`
+	Reactions    []ReactionCount `json:"reactions,omitempty"`
+	Views        int64           `json:"views,omitempty"`
+	LastViewedAt *time.Time      `json:"last_viewed_at,omitempty"`
+}
+
+// BulkNoteResult is the outcome of creating one note with CreateNotes.
+// Error is set if the note was skipped, and ID otherwise.
+type BulkNoteResult struct {
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// NotePage is a page of notes from ListNotesPage.
+type NotePage struct {
+	Results []Note `json:"results"`
+	Meta    struct {
+		Total  int64 `json:"total"`
+		Limit  int   `json:"limit"`
+		Offset int   `json:"offset"`
+	} `json:"meta"`
+}
+
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+type ReactionCount struct {
+	Emoji string `json:"emoji"`
+	Count int64  `json:"count"`
+}
+
+// ScoredNote is a semantic search result.
+type ScoredNote struct {
+	Note
+	Score float64 `json:"score"`
+}
+
+type TitleSuggestion struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// TagCount is one of the user's tags and how many notes have it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Notes int64  `json:"notes"`
+}
+
+// Notebook is a folder of notes, inside the notebook ParentID if it's set.
+type Notebook struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	ParentID  *string   `json:"parent_id,omitempty"`
+	Notes     int64     `json:"notes"`
+}
@@ -0,0 +1,109 @@
+// Command replay sends requests recorded with RECORD_FILE to a server,
+// typically one running locally against a scratch database, and reports
+// where the responses differ from the recorded ones:
+//
+//	go run ./cmd/replay -file requests.jsonl -target http://localhost:8080
+//
+// IDs and API keys in the recording are swapped for the ones the server
+// hands out during the replay, so a recording that starts by creating its
+// user replays as is. For requests made with keys created before the
+// recording started, pass a key of a user on the target with -key.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/recording"
+)
+
+func main() {
+	file := flag.String("file", "", "recording to replay (required)")
+	target := flag.String("target", "http://localhost:8080", "base URL of the server to replay against")
+	key := flag.String("key", "", "API key to use for recorded keys the replay hasn't seen created")
+	verbose := flag.Bool("v", false, "print every request, not only those whose status differs")
+	flag.Parse()
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	exchanges, err := recording.Read(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Couldn't read %s: %v", *file, err)
+	}
+
+	client := &http.Client{Timeout: time.Minute}
+	mapper := recording.NewMapper()
+	base := strings.TrimSuffix(*target, "/")
+	differed := 0
+	for i, e := range exchanges {
+		status, body, err := replay(client, base, e, mapper, *key)
+		if err != nil {
+			log.Fatalf("#%d %s %s: %v", i+1, e.Method, e.URL, err)
+		}
+		mapper.Learn(e.ResponseBody, body)
+		if status != e.Status {
+			differed++
+			fmt.Printf("#%d %s %s: status %d, recorded %d\n", i+1, e.Method, e.URL, status, e.Status)
+			fmt.Printf("  got:      %s\n  recorded: %s\n", oneLine(body), oneLine(e.ResponseBody))
+		} else if *verbose {
+			fmt.Printf("#%d %s %s: %d\n", i+1, e.Method, e.URL, status)
+		}
+	}
+	fmt.Printf("Replayed %d requests, %d with a different status\n", len(exchanges), differed)
+	if differed > 0 {
+		os.Exit(1)
+	}
+}
+
+// replay sends e to base with the IDs and keys it learned so far, and
+// returns the response status and body.
+func replay(client *http.Client, base string, e recording.Exchange, mapper *recording.Mapper, key string) (int, string, error) {
+	req, err := http.NewRequest(e.Method, base+mapper.Apply(e.URL), strings.NewReader(mapper.Apply(e.Body)))
+	if err != nil {
+		return 0, "", err
+	}
+	for name, values := range e.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if auth := e.Header.Get("Authorization"); auth != "" {
+		auth = mapper.Apply(auth)
+		if scheme, recorded, _ := strings.Cut(auth, " "); recording.IsPseudonym(recorded) && key != "" {
+			auth = scheme + " " + key
+		}
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", err
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+func oneLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 200 {
+		return s[:200] + "..."
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+// searchCursor marks where a page of search results ended: the rank of the
+// last result and its ID, which breaks ties. The next page starts strictly
+// after that position, so notes written in between can't push results
+// already seen onto it or make it skip any. Rank holds the numeric sort
+// keys, Key a textual one and Pinned whether the result was pinned, for
+// lists with pinned notes first; each endpoint uses what it sorts by.
+//
+// Clients get cursors as opaque strings (see SearchMeta) so the encoding
+// can change without breaking them.
+type searchCursor struct {
+	Query  string    `json:"q"`
+	Rank   []float64 `json:"r,omitempty"`
+	Key    string    `json:"k,omitempty"`
+	Pinned bool      `json:"p,omitempty"`
+	ID     string    `json:"id"`
+}
+
+// SearchMeta describes a page of search results, or of another list
+// paginated with cursors. NextCursor is an opaque token: pass it back
+// unchanged as ?cursor= along with the same query to get the following
+// page. It's empty on the last page.
+type SearchMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// SearchPage is a page of search results with its meta.
+type SearchPage struct {
+	Results interface{} `json:"results"`
+	Meta    SearchMeta  `json:"meta"`
+}
+
+// searchQueryHash identifies the search a cursor belongs to, so a cursor
+// reused with different parameters is rejected rather than giving an
+// arbitrary page.
+func searchQueryHash(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+func encodeSearchCursor(c searchCursor) string {
+	data, _ := json.Marshal(c) // Only plain strings and numbers; can't fail.
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// querySearchCursor reads the optional cursor parameter. It returns nil for
+// the first page and a validation error for cursors that are malformed or
+// were issued for a different query.
+func querySearchCursor(r *http.Request, queryHash string) (*searchCursor, error) {
+	value := r.URL.Query().Get("cursor")
+	if value == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errValidation("Invalid cursor", err)
+	}
+	var c searchCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, errValidation("Invalid cursor", err)
+	}
+	if c.Query != queryHash {
+		return nil, errValidation("Cursor belongs to a different search", nil)
+	}
+	return &c, nil
+}
+
+// rankedAfter reports whether a result ranked (rank, id) comes after the
+// cursor position, for results sorted by rank descending and then by ID.
+func (c *searchCursor) rankedAfter(rank []float64, id string) bool {
+	if c == nil {
+		return true
+	}
+	for i := range rank {
+		if i >= len(c.Rank) || rank[i] != c.Rank[i] {
+			return i < len(c.Rank) && rank[i] < c.Rank[i]
+		}
+	}
+	return id > c.ID
+}
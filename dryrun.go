@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// dryRunHeader asks for a mutating request to be checked and answered as
+// usual but not applied: "X-Dry-Run: true". Responses to dry runs carry it
+// too.
+const dryRunHeader = "X-Dry-Run"
+
+// middlewareDryRun runs mutating requests marked with dryRunHeader in a
+// database transaction that's rolled back once the response is written, so
+// validation, permission checks and the response are all real but nothing
+// is saved. Effects outside the database that wouldn't be undone, such as
+// events, pushes, embeddings and link preview fetches, are skipped; see
+// isDryRun.
+func (cfg *apiConfig) middlewareDryRun(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(dryRunHeader) != "true" || cfg.Conn == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tx, err := cfg.conn(r.Context()).BeginTx(r.Context(), nil)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't start transaction", err)
+			return
+		}
+		defer tx.Rollback()
+		w.Header().Set(dryRunHeader, "true")
+		next.ServeHTTP(w, r.WithContext(database.ContextWithTx(r.Context(), tx)))
+	})
+}
+
+// isDryRun reports whether ctx belongs to a dry run, whose effects outside
+// the database must be skipped.
+func isDryRun(ctx context.Context) bool {
+	_, ok := database.TxFromContext(ctx)
+	return ok
+}
+
+// dbTx is a transaction started by beginTx, with the queries run in it.
+type dbTx struct {
+	*database.Store
+	tx     *sql.Tx
+	nested bool // Part of a dry run's transaction, which ends with the request.
+}
+
+// beginTx starts a transaction. In a dry run, the request's transaction is
+// used instead and Commit and Rollback leave it to the dry run to end.
+func (cfg *apiConfig) beginTx(ctx context.Context) (*dbTx, error) {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return &dbTx{Store: cfg.DB.WithTx(tx), tx: tx, nested: true}, nil
+	}
+	tx, err := cfg.conn(ctx).BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &dbTx{Store: cfg.DB.WithTx(tx), tx: tx}, nil
+}
+
+func (t *dbTx) Commit() error {
+	if t.nested {
+		return nil
+	}
+	return t.tx.Commit()
+}
+
+func (t *dbTx) Rollback() error {
+	if t.nested {
+		return nil
+	}
+	return t.tx.Rollback()
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/mail"
+)
+
+// errEmailOff is returned by sendEmail when MAIL_PROVIDER isn't set.
+var errEmailOff = errors.New("email isn't configured")
+
+// sendEmail renders the email template name with data, in locale if it's
+// been translated, and sends it to to. All outgoing email goes through
+// here. Dry runs render the email but don't send it.
+func (cfg *apiConfig) sendEmail(ctx context.Context, to, name, locale string, data any) (mail.Message, error) {
+	if cfg.Mailer == nil {
+		return mail.Message{}, errEmailOff
+	}
+	msg, err := cfg.emailTemplates.Render(name, locale, data)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	msg.To = to
+	if isDryRun(ctx) {
+		return msg, nil
+	}
+	return msg, cfg.Mailer.Send(ctx, msg)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/embeddings"
+)
+
+// embedNote stores an embedding of the note for semantic search when an
+// embedding provider is configured. Failures are only logged: the note is
+// saved either way, it just won't appear in semantic search results. Dry
+// runs skip it rather than pay for an embedding that's thrown away.
+func (cfg *apiConfig) embedNote(ctx context.Context, note database.Note) {
+	if cfg.Embedder == nil || isDryRun(ctx) {
+		return
+	}
+	vector, err := cfg.Embedder.Embed(ctx, note.Note)
+	if err != nil {
+		log.Printf("Couldn't embed note %s: %v", note.ID, err)
+		return
+	}
+	err = cfg.DB.UpsertNoteEmbedding(ctx, database.UpsertNoteEmbeddingParams{
+		NoteID:    note.ID,
+		Model:     cfg.Embedder.Model(),
+		Embedding: embeddings.Encode(vector),
+		CreatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		log.Printf("Couldn't store embedding for note %s: %v", note.ID, err)
+	}
+}
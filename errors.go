@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// apiError is an error that carries the HTTP status and client-facing message
+// it should be reported with, and for errors clients handle specially, a
+// machine-readable Reason. The wrapped Err is only logged.
+type apiError struct {
+	Code   int
+	Reason string
+	Msg    string
+	Err    error
+}
+
+// reasonUpgradeRequired tells clients an action needs a higher plan.
+const reasonUpgradeRequired = "UPGRADE_REQUIRED"
+
+func (e *apiError) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *apiError) Unwrap() error {
+	return e.Err
+}
+
+func errValidation(msg string, err error) error {
+	return &apiError{Code: http.StatusBadRequest, Msg: msg, Err: err}
+}
+
+func errUnauthorized(msg string, err error) error {
+	return &apiError{Code: http.StatusUnauthorized, Msg: msg, Err: err}
+}
+
+func errNotFound(msg string, err error) error {
+	return &apiError{Code: http.StatusNotFound, Msg: msg, Err: err}
+}
+
+func errConflict(msg string, err error) error {
+	return &apiError{Code: http.StatusConflict, Msg: msg, Err: err}
+}
+
+func errTooManyRequests(msg string, err error) error {
+	return &apiError{Code: http.StatusTooManyRequests, Msg: msg, Err: err}
+}
+
+// errUpgradeRequired reports that the user's plan doesn't allow an action.
+func errUpgradeRequired(msg string) error {
+	return &apiError{Code: http.StatusPaymentRequired, Reason: reasonUpgradeRequired, Msg: msg}
+}
+
+func errInternal(msg string, err error) error {
+	return &apiError{Code: http.StatusInternalServerError, Msg: msg, Err: err}
+}
+
+// appHandler is a handler that reports failures by returning an error instead
+// of writing the error response itself.
+type appHandler func(http.ResponseWriter, *http.Request) error
+
+// handle adapts an appHandler to http.HandlerFunc, turning returned errors into responses.
+func handle(h appHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			respondWithAPIError(w, err)
+		}
+	}
+}
+
+// respondWithAPIError maps err to a status code and responds through respondWithError.
+// Missing rows reported by the database layer are always 404s and unique
+// constraint violations 409s naming the offending field; other errors that
+// weren't classified by a handler are reported as 500s.
+func respondWithAPIError(w http.ResponseWriter, err error) {
+	code, reason, msg, logErr := http.StatusInternalServerError, "", "Internal server error", err
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		code, reason, msg, logErr = apiErr.Code, apiErr.Reason, apiErr.Msg, apiErr.Err
+	}
+	if errors.Is(err, database.ErrNotFound) {
+		code = http.StatusNotFound
+	}
+	var conflict *database.ConflictError
+	if errors.As(err, &conflict) {
+		if apiErr == nil {
+			msg = "Conflict"
+		}
+		code, msg = http.StatusConflict, msg+": "+conflict.Column+" already exists"
+	}
+	respondWithErrorReason(w, code, reason, msg, logErr)
+}
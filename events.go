@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/google/uuid"
+)
+
+// publishEvent emits a note lifecycle event. Failures are only logged: the
+// change itself is already saved and shouldn't be reported as failed.
+func (cfg *apiConfig) publishEvent(ctx context.Context, eventType, userID, noteID string) {
+	cfg.notesChanged(ctx, userID)
+	cfg.publish(ctx, events.Event{Type: eventType, UserID: userID, NoteID: noteID})
+}
+
+// notesChanged is called after every change to userID's notes, through
+// publishEvent or directly for changes that aren't announced, such as new
+// tags. It's where cached palette results go stale and where a note list
+// already being read stops being worth waiting for.
+func (cfg *apiConfig) notesChanged(ctx context.Context, userID string) {
+	key := tenantUserKey(ctx, userID)
+	cfg.quickCache.forget(key)
+	cfg.noteLists.Forget(key)
+}
+
+// publishCommentEvent emits an event about a comment on a note, with the
+// same failure handling as publishEvent.
+func (cfg *apiConfig) publishCommentEvent(ctx context.Context, eventType, userID, noteID, commentID string) {
+	cfg.publish(ctx, events.Event{Type: eventType, UserID: userID, NoteID: noteID, CommentID: commentID})
+}
+
+func (cfg *apiConfig) publish(ctx context.Context, e events.Event) {
+	if isDryRun(ctx) {
+		return
+	}
+	e.ID = uuid.New().String()
+	e.OccurredAt = cfg.Clock.Now().UTC()
+	if err := cfg.Events.Publish(ctx, e); err != nil {
+		log.Printf("Couldn't publish %s event: %v", e.Type, err)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+)
+
+// runNotePurge deletes expired notes, in every tenant's database, every
+// interval until ctx is done.
+// When a note is within warning of expiring, a note.expiring event is
+// published and its owner notified, once per note; 0 disables warnings.
+func (cfg *apiConfig) runNotePurge(ctx context.Context, interval, warning time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cfg.forEachTenant(ctx, func(ctx context.Context) {
+			if warning > 0 {
+				cfg.warnExpiringNotes(ctx, warning)
+			}
+			cfg.purgeExpiredNotes(ctx)
+		})
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cfg *apiConfig) warnExpiringNotes(ctx context.Context, warning time.Duration) {
+	now := cfg.Clock.Now().UTC()
+	notes, err := cfg.DB.GetNotesExpiringBefore(ctx, sql.NullString{String: now.Add(warning).Format(time.RFC3339), Valid: true})
+	if err != nil {
+		log.Printf("Couldn't get expiring notes: %v", err)
+		return
+	}
+	for _, note := range notes {
+		cfg.publishEvent(ctx, events.TypeNoteExpiring, note.UserID, note.ID)
+		cfg.notify(ctx, note.UserID, events.TypeNoteExpiring, note.ID, "")
+		err := cfg.DB.MarkNoteExpiryWarned(ctx, database.MarkNoteExpiryWarnedParams{
+			ExpiryWarnedAt: sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+			ID:             note.ID,
+		})
+		if err != nil {
+			log.Printf("Couldn't mark expiry warning for note %s: %v", note.ID, err)
+		}
+	}
+}
+
+func (cfg *apiConfig) purgeExpiredNotes(ctx context.Context) {
+	deleted, err := cfg.DB.DeleteExpiredNotes(ctx, sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true})
+	if err != nil {
+		log.Printf("Couldn't purge expired notes: %v", err)
+		return
+	}
+	for _, note := range deleted {
+		cfg.publishEvent(ctx, events.TypeNoteExpired, note.UserID, note.ID)
+	}
+	if len(deleted) > 0 {
+		log.Printf("Purged %d expired notes", len(deleted))
+	}
+}
@@ -3,18 +3,31 @@ module github.com/DanielSiebert-dev/learn-cicd-starter
 go 1.22
 
 require (
+	github.com/alecthomas/chroma/v2 v2.14.0
 	github.com/bootdotdev/learn-cicd-starter v0.0.0-20250314180257-a759e37288a1
 	github.com/go-chi/chi/v5 v5.2.2
 	github.com/go-chi/cors v1.2.1
 	github.com/google/uuid v1.3.0
 	github.com/joho/godotenv v1.5.1
+	github.com/nats-io/nats.go v1.31.0
+	github.com/segmentio/kafka-go v0.4.47
 	github.com/tursodatabase/libsql-client-go v0.0.0-20240220085343-4ae0eb9d0898
+	golang.org/x/net v0.17.0
+	golang.org/x/sync v0.7.0
+	golang.org/x/text v0.13.0
+	golang.org/x/time v0.5.0
 )
 
 require (
 	github.com/antlr/antlr4/runtime/Go/antlr/v4 v4.0.0-20230512164433-5d1fd1a340c9 // indirect
-	github.com/klauspost/compress v1.15.15 // indirect
+	github.com/dlclark/regexp2 v1.11.0 // indirect
+	github.com/klauspost/compress v1.17.0 // indirect
 	github.com/libsql/sqlite-antlr4-parser v0.0.0-20230802215326-5cb5bb604475 // indirect
+	github.com/nats-io/nkeys v0.4.5 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	golang.org/x/crypto v0.14.0 // indirect
 	golang.org/x/exp v0.0.0-20220722155223-a9213eeb770e // indirect
+	golang.org/x/sys v0.13.0 // indirect
 	nhooyr.io/websocket v1.8.7 // indirect
 )
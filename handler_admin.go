@@ -0,0 +1,133 @@
+package main
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// Limits for the number of users listed per page.
+const (
+	defaultAdminUsersLimit = 50
+	maxAdminUsersLimit     = 200
+)
+
+// handlerAdminUI serves the admin page. The page itself holds no data: it
+// asks for the admin key and calls the other /admin endpoints with it, so
+// it's served without one.
+func (cfg *apiConfig) handlerAdminUI(w http.ResponseWriter, r *http.Request) {
+	f, err := staticFiles.Open("static/admin.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := io.Copy(w, f); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handlerAdminUsersGet lists users newest first with their note and active
+// key counts, paginated with limit and offset.
+func (cfg *apiConfig) handlerAdminUsersGet(w http.ResponseWriter, r *http.Request) error {
+	limit, err := queryLimit(r, defaultAdminUsersLimit, maxAdminUsersLimit)
+	if err != nil {
+		return err
+	}
+	offset, err := queryOffset(r)
+	if err != nil {
+		return err
+	}
+
+	rows, err := cfg.DB.ListUsers(r.Context(), database.ListUsersParams{
+		Now:    sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true},
+		Limit:  int64(limit),
+		Offset: int64(offset),
+	})
+	if err != nil {
+		return errInternal("Couldn't list users", err)
+	}
+
+	resp := make([]AdminUser, len(rows))
+	for i, row := range rows {
+		resp[i], err = databaseListUsersRowToAdminUser(row)
+		if err != nil {
+			return errInternal("Couldn't convert user", err)
+		}
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// handlerAdminUserKeysRevoke expires all of a user's API keys at once, e.g.
+// for a leaked key or an abusive account. The user can't authenticate
+// again until they're given a new key.
+func (cfg *apiConfig) handlerAdminUserKeysRevoke(w http.ResponseWriter, r *http.Request) error {
+	user, err := cfg.DB.GetUserByID(r.Context(), chi.URLParam(r, "userID"))
+	if err != nil {
+		return errInternal("Couldn't get user", err)
+	}
+
+	revoked, err := cfg.DB.RevokeAPIKeysForUser(r.Context(), database.RevokeAPIKeysForUserParams{
+		Now:    sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true},
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't revoke keys", err)
+	}
+	cfg.authCache.forget(r.Context(), user.ID)
+	cfg.logSecurityEvent(r, audit.Event{
+		Type:   audit.TypeKeyRevoked,
+		Reason: "revoked by admin",
+		Actor:  audit.Actor{Type: audit.ActorAdmin},
+		Target: &audit.Target{UserID: user.ID, Count: revoked},
+	})
+
+	respondWithJSON(w, http.StatusOK, map[string]int64{"revoked": revoked})
+	return nil
+}
+
+// handlerAdminStatsGet reports instance-wide counts.
+func (cfg *apiConfig) handlerAdminStatsGet(w http.ResponseWriter, r *http.Request) error {
+	stats, err := cfg.DB.GetAdminStats(r.Context())
+	if err != nil {
+		return errInternal("Couldn't get stats", err)
+	}
+	respondWithJSON(w, http.StatusOK, AdminStats{
+		Users:            stats.Users,
+		Notes:            stats.Notes,
+		PublishedNotes:   stats.PublishedNotes,
+		Comments:         stats.Comments,
+		PendingNoteViews: cfg.noteViews.size(),
+	})
+	return nil
+}
+
+// handlerAdminFeaturesGet reports which optional features this instance has
+// turned on. They're set through the environment, so this is read-only.
+func (cfg *apiConfig) handlerAdminFeaturesGet(w http.ResponseWriter, r *http.Request) error {
+	respondWithJSON(w, http.StatusOK, []Feature{
+		{Name: "Semantic search", Env: "EMBEDDINGS_PROVIDER", Enabled: cfg.Embedder != nil},
+		{Name: "Summaries", Env: "LLM_PROVIDER", Enabled: cfg.LLM != nil},
+		{Name: "Translation", Env: "TRANSLATE_PROVIDER", Enabled: cfg.Translator != nil},
+		{Name: "Email", Env: "MAIL_PROVIDER", Enabled: cfg.Mailer != nil},
+		{Name: "Spelling and grammar checks", Env: "LANGUAGETOOL_URL", Enabled: cfg.LanguageTool != nil},
+		{Name: "Fuzzy title search", Env: "FUZZY_TITLE_SEARCH", Enabled: cfg.FuzzyTitleSearch},
+		{Name: "Mobile push", Env: "FCM_CREDENTIALS_FILE, APNS_KEY_FILE", Enabled: len(cfg.Push) > 0},
+		{Name: "Web push", Env: "WEB_PUSH_SUBJECT", Enabled: cfg.WebPush != nil},
+		{Name: "Latency objectives", Env: "SLO_FILE", Enabled: cfg.SLO != nil},
+		{Name: "Anomaly alerts", Env: "ALERT_WEBHOOK_URL", Enabled: cfg.Alerts != nil},
+		{Name: "Auth cache", Env: "AUTH_CACHE_TTL", Enabled: cfg.authCache != nil},
+		{Name: "Tenant databases", Env: "MULTI_TENANT", Enabled: cfg.Tenants != nil},
+		{Name: "Usage metering", Env: "USAGE_METERING", Enabled: cfg.Usage != nil},
+		{Name: "Plan limits", Env: "ENFORCE_PLANS", Enabled: cfg.EnforcePlans},
+		{Name: "Stripe billing", Env: "STRIPE_SECRET_KEY", Enabled: cfg.Stripe != nil},
+	})
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlerNoteArchive archives a note, hiding it from the note list unless
+// that's asked for archived notes, and responds with it. Archiving an
+// archived note again moves its archived_at to now.
+func (cfg *apiConfig) handlerNoteArchive(w http.ResponseWriter, r *http.Request, user database.User) error {
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.ArchiveNote(r.Context(), database.ArchiveNoteParams{
+		ArchivedAt: sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true},
+		ID:         noteID,
+		UserID:     user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't archive note", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID, nil)
+	}
+	cfg.publishEvent(r.Context(), events.TypeNoteArchived, user.ID, noteID)
+	return cfg.respondWithNote(w, r, noteID)
+}
+
+// handlerNoteUnarchive puts an archived note back in the note list and
+// responds with it. Notes that aren't archived are left as they are.
+func (cfg *apiConfig) handlerNoteUnarchive(w http.ResponseWriter, r *http.Request, user database.User) error {
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.UnarchiveNote(r.Context(), database.UnarchiveNoteParams{ID: noteID, UserID: user.ID})
+	if err != nil {
+		return errInternal("Couldn't unarchive note", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID, nil)
+	}
+	cfg.publishEvent(r.Context(), events.TypeNoteUnarchived, user.ID, noteID)
+	return cfg.respondWithNote(w, r, noteID)
+}
+
+// respondWithNote responds with the note noteID as it's stored now.
+func (cfg *apiConfig) respondWithNote(w http.ResponseWriter, r *http.Request, noteID string) error {
+	note, err := cfg.DB.GetNote(r.Context(), noteID)
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+	noteResp, err := databaseNoteToNote(note)
+	if err != nil {
+		return errInternal("Couldn't convert note", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, noteResp)
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// maxBulkNotes is how many notes one bulk request can create.
+const maxBulkNotes = 500
+
+// bulkNoteResult reports what became of one note of a bulk request: the ID
+// it was created with, or why it wasn't.
+type bulkNoteResult struct {
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// handlerNotesBulkCreate creates the notes in an array, each taking the
+// fields of handlerNotesCreate, for importing from other apps. Notes that
+// fail validation are skipped and the rest are created in one transaction,
+// so either all of them are saved or none. The results match the array
+// item for item. The status is 201 if every note was created and 207 if
+// some were skipped.
+func (cfg *apiConfig) handlerNotesBulkCreate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Note       string     `json:"note"`
+		ExpiresAt  *time.Time `json:"expires_at"`
+		Tags       []string   `json:"tags"`
+		NotebookID *string    `json:"notebook_id"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := []parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if len(params) == 0 {
+		return errValidation("No notes to create", nil)
+	}
+	if len(params) > maxBulkNotes {
+		return errValidation("At most "+strconv.Itoa(maxBulkNotes)+" notes can be created at once", nil)
+	}
+
+	type pendingNote struct {
+		index int
+		note  database.CreateNoteParams
+		tags  []string
+	}
+	results := make([]bulkNoteResult, len(params))
+	var pending []pendingNote
+	now := cfg.Clock.Now()
+	for i, p := range params {
+		expiresAt, err := noteExpiration(p.ExpiresAt, now)
+		if err == nil {
+			p.Tags, err = normalizeTags(p.Tags)
+		}
+		var notebookID sql.NullString
+		if err == nil {
+			notebookID, err = cfg.notebookRef(r.Context(), user.ID, p.NotebookID)
+		}
+		if msg, ok := bulkItemError(err); ok {
+			results[i].Error = msg
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		pending = append(pending, pendingNote{
+			index: i,
+			note: database.CreateNoteParams{
+				Note:       p.Note,
+				ExpiresAt:  expiresAt,
+				NotebookID: notebookID,
+			},
+			tags: p.Tags,
+		})
+	}
+	if err := cfg.checkNotesQuota(r.Context(), user, int64(len(pending))); err != nil {
+		return err
+	}
+
+	tx, err := cfg.beginTx(r.Context())
+	if err != nil {
+		return errInternal("Couldn't start transaction", err)
+	}
+	defer tx.Rollback()
+	created := make([]database.Note, 0, len(pending))
+	for _, p := range pending {
+		note, err := cfg.insertNote(r.Context(), tx.Store, user, p.note)
+		if err != nil {
+			return errInternal("Couldn't create note", err)
+		}
+		if len(p.tags) > 0 {
+			if err := setNoteTags(r.Context(), tx.Store, note.ID, user.ID, p.tags); err != nil {
+				return errInternal("Couldn't save tags", err)
+			}
+		}
+		results[p.index].ID = note.ID
+		created = append(created, note)
+	}
+	if err := tx.Commit(); err != nil {
+		return errInternal("Couldn't create notes", err)
+	}
+	for _, note := range created {
+		cfg.noteCreated(r.Context(), note)
+	}
+
+	status := http.StatusCreated
+	if len(created) < len(results) {
+		status = http.StatusMultiStatus
+	}
+	respondWithJSON(w, status, struct {
+		Results []bulkNoteResult `json:"results"`
+	}{results})
+	return nil
+}
+
+// bulkItemError returns the message of err if it's the fault of one note
+// of a bulk request, which is reported with that note rather than failing
+// the request.
+func bulkItemError(err error) (string, bool) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.Code < http.StatusInternalServerError {
+		return apiErr.Msg, true
+	}
+	return "", false
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type bulkResponse struct {
+	Results []bulkNoteResult `json:"results"`
+}
+
+func TestNotesBulkCreate(t *testing.T) {
+	api := newTestAPI(t)
+	_, key := api.newUser(t)
+
+	tests := []struct {
+		name       string
+		notes      []map[string]any
+		wantStatus int
+		wantErrors []bool
+	}{
+		{
+			name:       "all valid",
+			notes:      []map[string]any{{"note": "one"}, {"note": "two", "tags": []string{"work"}}},
+			wantStatus: http.StatusCreated,
+			wantErrors: []bool{false, false},
+		},
+		{
+			name: "some invalid",
+			notes: []map[string]any{
+				{"note": "three"},
+				{"note": "unknown notebook", "notebook_id": "missing"},
+				{"note": "expired", "expires_at": "2000-01-01T00:00:00Z"},
+				{"note": "four"},
+			},
+			wantStatus: http.StatusMultiStatus,
+			wantErrors: []bool{false, true, true, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp bulkResponse
+			if code := api.do(t, http.MethodPost, "/v1/notes/bulk", key, tt.notes, &resp); code != tt.wantStatus {
+				t.Fatalf("status %d, want %d", code, tt.wantStatus)
+			}
+			if len(resp.Results) != len(tt.notes) {
+				t.Fatalf("got %d results for %d notes", len(resp.Results), len(tt.notes))
+			}
+			for i, result := range resp.Results {
+				if (result.Error != "") != tt.wantErrors[i] || (result.ID == "") != tt.wantErrors[i] {
+					t.Errorf("result %d = %+v, want error %v", i, result, tt.wantErrors[i])
+				}
+			}
+		})
+	}
+
+	// Notes created together have the same creation time, so they're in no
+	// particular order.
+	var notes []Note
+	api.do(t, http.MethodGet, "/v1/notes", key, nil, &notes)
+	got := noteBodies(notes)
+	slices.Sort(got)
+	if want := []string{"four", "one", "three", "two"}; !slices.Equal(got, want) {
+		t.Errorf("notes = %v, want %v", got, want)
+	}
+}
+
+func TestNotesBulkCreateQuota(t *testing.T) {
+	api := newTestAPI(t)
+	api.cfg.EnforcePlans = true
+	_, key := api.newUser(t)
+
+	// The free plan allows 100 notes.
+	bulk := func(n int) int {
+		notes := make([]map[string]string, n)
+		for i := range notes {
+			notes[i] = map[string]string{"note": "note " + strconv.Itoa(i)}
+		}
+		return api.do(t, http.MethodPost, "/v1/notes/bulk", key, notes, nil)
+	}
+	if code := bulk(99); code != http.StatusCreated {
+		t.Fatalf("creating 99 notes: status %d", code)
+	}
+	if code := bulk(2); code != http.StatusPaymentRequired {
+		t.Errorf("creating 2 more notes: status %d, want %d", code, http.StatusPaymentRequired)
+	}
+	// Skipped notes don't count.
+	notes := []map[string]any{{"note": "last"}, {"note": "expired", "expires_at": "2000-01-01T00:00:00Z"}}
+	if code := api.do(t, http.MethodPost, "/v1/notes/bulk", key, notes, nil); code != http.StatusMultiStatus {
+		t.Errorf("creating the 100th note: status %d, want %d", code, http.StatusMultiStatus)
+	}
+
+	var page NotePage
+	api.do(t, http.MethodGet, "/v1/notes?limit=1", key, nil, &page)
+	if page.Meta.Total != 100 {
+		t.Errorf("user has %d notes, want 100", page.Meta.Total)
+	}
+}
+
+func TestNotesBulkCreateRollback(t *testing.T) {
+	api := newTestAPI(t)
+	_, key := api.newUser(t)
+
+	// Fail inserting one of the notes, after others have been inserted.
+	_, err := api.conn.Exec(`CREATE TRIGGER bulk_test_fail BEFORE INSERT ON notes
+WHEN NEW.note = 'bulk test failure' BEGIN SELECT RAISE(ABORT, 'failed'); END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { api.conn.Exec("DROP TRIGGER bulk_test_fail") })
+
+	notes := []map[string]any{
+		{"note": "one", "tags": []string{"work"}},
+		{"note": "two"},
+		{"note": "bulk test failure"},
+	}
+	if code := api.do(t, http.MethodPost, "/v1/notes/bulk", key, notes, nil); code != http.StatusInternalServerError {
+		t.Fatalf("status %d, want %d", code, http.StatusInternalServerError)
+	}
+
+	var got []Note
+	api.do(t, http.MethodGet, "/v1/notes", key, nil, &got)
+	if len(got) != 0 {
+		t.Errorf("notes = %v, want none", noteBodies(got))
+	}
+	var tagged []Note
+	api.do(t, http.MethodGet, "/v1/notes?tag=work", key, nil, &tagged)
+	if len(tagged) != 0 {
+		t.Errorf("notes tagged work = %v, want none", noteBodies(tagged))
+	}
+}
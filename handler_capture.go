@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/readable"
+)
+
+// maxCaptureRequestSize bounds capture requests, which may carry a whole page.
+const maxCaptureRequestSize = maxPageSize + 1<<20
+
+// handlerCapture creates a note from a web page, e.g. from a browser
+// extension. The page's readable content is converted to Markdown and the
+// optional selection is quoted at the top. Clients may send the page's HTML
+// themselves (for pages behind a login); otherwise the server fetches it.
+func (cfg *apiConfig) handlerCapture(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		URL       string `json:"url"`
+		Selection string `json:"selection"`
+		HTML      string `json:"html"`
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCaptureRequestSize))
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+
+	pageURL, err := url.Parse(params.URL)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return errValidation("url must be an absolute http or https URL", err)
+	}
+	if err := cfg.checkNoteQuota(r.Context(), user); err != nil {
+		return err
+	}
+
+	page := []byte(params.HTML)
+	if len(page) == 0 {
+		resp, err := cfg.pageFetcher.Get(r.Context(), pageURL.String(), "text/html,application/xhtml+xml")
+		if err != nil {
+			return errValidation("Couldn't fetch page", err)
+		}
+		page, pageURL = resp.Body, resp.URL
+	}
+
+	content, err := readable.Extract(bytes.NewReader(page), pageURL)
+	if err != nil {
+		return errValidation("Couldn't parse page", err)
+	}
+	title := content.Title
+	if title == "" {
+		title = pageURL.Host
+	}
+
+	note, err := cfg.createNote(r.Context(), user, database.CreateNoteParams{
+		Note:        captureNoteBody(title, params.Selection, content.Markdown),
+		SourceUrl:   sql.NullString{String: pageURL.String(), Valid: true},
+		SourceTitle: sql.NullString{String: title, Valid: true},
+	})
+	if err != nil {
+		return errInternal("Couldn't create note", err)
+	}
+
+	noteResp, err := databaseNoteToNote(note)
+	if err != nil {
+		return errInternal("Couldn't convert note", err)
+	}
+
+	respondWithJSON(w, http.StatusCreated, noteResp)
+	return nil
+}
+
+// captureNoteBody lays out a captured page: its title as a heading, then
+// the selection as a quote, then the page content.
+func captureNoteBody(title, selection, content string) string {
+	parts := []string{"# " + title}
+	if selection = strings.TrimSpace(selection); selection != "" {
+		parts = append(parts, "> "+strings.Join(strings.Split(selection, "\n"), "\n> "))
+	}
+	if content != "" {
+		parts = append(parts, content)
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// maxCheckTextLength is the most text a single check may send, in bytes,
+// matching the limit of the public LanguageTool API.
+const maxCheckTextLength = 20000
+
+// handlerCheck proxies a spelling and grammar check to the configured
+// LanguageTool service so clients don't need credentials of their own. The
+// service's response is returned as is. Cached responses don't count
+// against the user's rate limit.
+func (cfg *apiConfig) handlerCheck(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Text     string `json:"text"`
+		Language string `json:"language"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if strings.TrimSpace(params.Text) == "" {
+		return errValidation("Text is required", nil)
+	}
+	if len(params.Text) > maxCheckTextLength {
+		return errValidation("Text is too long", nil)
+	}
+	if params.Language == "" {
+		params.Language = "auto"
+	}
+
+	if resp, ok := cfg.LanguageTool.Cached(params.Text, params.Language); ok {
+		respondWithJSON(w, http.StatusOK, resp)
+		return nil
+	}
+	if !cfg.checkLimiter.Allow(user.ID) {
+		return errTooManyRequests("Check rate limit exceeded, try again later", nil)
+	}
+
+	resp, err := cfg.LanguageTool.Check(r.Context(), params.Text, params.Language)
+	if err != nil {
+		return errInternal("Couldn't check text", err)
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
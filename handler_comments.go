@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	maxCommentLength = 10000
+
+	defaultCommentsLimit = 50
+	maxCommentsLimit     = 200
+)
+
+// handlerNoteCommentsCreate adds a comment to a note. Comments are visible to
+// whoever can read the note, which for now is only its owner.
+func (cfg *apiConfig) handlerNoteCommentsCreate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if strings.TrimSpace(params.Body) == "" {
+		return errValidation("body is required", nil)
+	}
+	if utf8.RuneCountInString(params.Body) > maxCommentLength {
+		return errValidation("body is too long", nil)
+	}
+
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+
+	id := uuid.New().String()
+	err = cfg.DB.CreateNoteComment(r.Context(), database.CreateNoteCommentParams{
+		ID:        id,
+		CreatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		NoteID:    note.ID,
+		UserID:    user.ID,
+		Body:      params.Body,
+	})
+	if err != nil {
+		return errInternal("Couldn't create comment", err)
+	}
+
+	comment, err := cfg.DB.GetNoteComment(r.Context(), id)
+	if err != nil {
+		return errInternal("Couldn't get comment", err)
+	}
+	cfg.publishCommentEvent(r.Context(), events.TypeCommentCreated, user.ID, note.ID, comment.ID)
+	if note.UserID != user.ID {
+		cfg.notify(r.Context(), note.UserID, events.TypeCommentCreated, note.ID, comment.ID)
+	}
+
+	commentResp, err := databaseCommentToComment(comment)
+	if err != nil {
+		return errInternal("Couldn't convert comment", err)
+	}
+	respondWithJSON(w, http.StatusCreated, commentResp)
+	return nil
+}
+
+// handlerNoteCommentsGet lists a note's comments oldest first, paginated
+// with limit and offset.
+func (cfg *apiConfig) handlerNoteCommentsGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	limit, err := queryLimit(r, defaultCommentsLimit, maxCommentsLimit)
+	if err != nil {
+		return err
+	}
+	offset, err := queryOffset(r)
+	if err != nil {
+		return err
+	}
+
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+
+	comments, err := cfg.DB.GetNoteComments(r.Context(), database.GetNoteCommentsParams{
+		NoteID: note.ID,
+		Limit:  int64(limit),
+		Offset: int64(offset),
+	})
+	if err != nil {
+		return errInternal("Couldn't get comments", err)
+	}
+
+	reactions, err := cfg.commentReactionsByComment(r.Context(), note.ID)
+	if err != nil {
+		return errInternal("Couldn't get reactions", err)
+	}
+
+	resp := make([]Comment, len(comments))
+	for i, comment := range comments {
+		resp[i], err = databaseCommentToComment(comment)
+		if err != nil {
+			return errInternal("Couldn't convert comment", err)
+		}
+		resp[i].Reactions = reactions[comment.ID]
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// handlerNoteCommentsDelete deletes a comment. Only its author can delete it.
+func (cfg *apiConfig) handlerNoteCommentsDelete(w http.ResponseWriter, r *http.Request, user database.User) error {
+	noteID := chi.URLParam(r, "noteID")
+	commentID := chi.URLParam(r, "commentID")
+	n, err := cfg.DB.DeleteNoteComment(r.Context(), database.DeleteNoteCommentParams{
+		ID:     commentID,
+		NoteID: noteID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't delete comment", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find comment "+commentID, nil)
+	}
+	cfg.publishCommentEvent(r.Context(), events.TypeCommentDeleted, user.ID, noteID, commentID)
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ConsoleRoute is an endpoint the API console offers.
+type ConsoleRoute struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// handlerConsole serves the API console, a page for trying the API with a
+// pasted key.
+func handlerConsole(w http.ResponseWriter, r *http.Request) {
+	f, err := staticFiles.Open("static/console.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := io.Copy(w, f); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handlerConsoleRoutes lists the endpoints mounted on router under prefix,
+// so the console always offers exactly what this instance serves.
+func handlerConsoleRoutes(router chi.Routes, prefix string) (http.HandlerFunc, error) {
+	var routes []ConsoleRoute
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, ConsoleRoute{Method: method, Path: prefix + route})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondWithJSON(w, http.StatusOK, routes)
+	}, nil
+}
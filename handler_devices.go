@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxDeviceTokenLength is well above the longest FCM registration token.
+const maxDeviceTokenLength = 4096
+
+// handlerDevicesCreate registers a device for push notifications. A token
+// already registered, possibly by another user signed in on the same device
+// earlier, moves to the current user.
+func (cfg *apiConfig) handlerDevicesCreate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Provider string `json:"provider"`
+		Token    string `json:"token"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if cfg.Push[params.Provider] == nil {
+		return errValidation("Push notifications aren't configured for provider "+params.Provider, nil)
+	}
+	token := strings.TrimSpace(params.Token)
+	if token == "" {
+		return errValidation("token is required", nil)
+	}
+	if len(token) > maxDeviceTokenLength {
+		return errValidation("token is too long", nil)
+	}
+
+	err = cfg.DB.UpsertDeviceToken(r.Context(), database.UpsertDeviceTokenParams{
+		ID:        uuid.New().String(),
+		CreatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		UserID:    user.ID,
+		Provider:  params.Provider,
+		Token:     token,
+	})
+	if err != nil {
+		return errInternal("Couldn't register device", err)
+	}
+
+	device, err := cfg.DB.GetDeviceTokenByToken(r.Context(), token)
+	if err != nil {
+		return errInternal("Couldn't get device", err)
+	}
+	deviceResp, err := databaseDeviceTokenToDevice(device)
+	if err != nil {
+		return errInternal("Couldn't convert device", err)
+	}
+	respondWithJSON(w, http.StatusCreated, deviceResp)
+	return nil
+}
+
+func (cfg *apiConfig) handlerDevicesGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	devices, err := cfg.DB.GetDeviceTokensForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get devices", err)
+	}
+
+	resp := make([]Device, len(devices))
+	for i, device := range devices {
+		resp[i], err = databaseDeviceTokenToDevice(device)
+		if err != nil {
+			return errInternal("Couldn't convert device", err)
+		}
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+func (cfg *apiConfig) handlerDevicesDelete(w http.ResponseWriter, r *http.Request, user database.User) error {
+	n, err := cfg.DB.DeleteDeviceToken(r.Context(), database.DeleteDeviceTokenParams{
+		ID:     chi.URLParam(r, "deviceID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't delete device", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find device", nil)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
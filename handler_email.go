@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	netmail "net/mail"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/mail"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlerAdminEmailTest sends the test email to an address, to check that
+// the configured provider delivers.
+func (cfg *apiConfig) handlerAdminEmailTest(w http.ResponseWriter, r *http.Request) error {
+	type parameters struct {
+		To     string `json:"to"`
+		Name   string `json:"name"`
+		Locale string `json:"locale"`
+	}
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if _, err := netmail.ParseAddress(params.To); err != nil {
+		return errValidation("to must be an email address", err)
+	}
+	if params.Name == "" {
+		params.Name = params.To
+	}
+
+	msg, err := cfg.sendEmail(r.Context(), params.To, "test", params.Locale, mail.TestEmail{
+		Name:     params.Name,
+		Provider: cfg.Mailer.Name(),
+	})
+	if err != nil {
+		return errInternal("Couldn't send email", err)
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"to": msg.To, "subject": msg.Subject, "provider": cfg.Mailer.Name()})
+	return nil
+}
+
+// handlerEmailPreviews lists the emails that can be previewed.
+func (cfg *apiConfig) handlerEmailPreviews(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, cfg.emailTemplates.Names())
+}
+
+// handlerEmailPreview renders an email with sample data, as HTML or, with
+// ?format=text, as the plain text part headed by its subject. ?locale=
+// picks a translation.
+func (cfg *apiConfig) handlerEmailPreview(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	data, ok := mail.PreviewData[name]
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Couldn't find email "+name, nil)
+		return
+	}
+	msg, err := cfg.emailTemplates.Render(name, r.URL.Query().Get("locale"), data)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't render email", err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "text" || msg.HTML == "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "Subject: %s\n\n%s", msg.Subject, msg.Text)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, msg.HTML)
+}
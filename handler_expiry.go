@@ -0,0 +1,68 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlerNoteExpirationSet sets or, with a null expires_at, clears the time
+// after which a note is deleted. Changing it re-arms the expiry warning.
+func (cfg *apiConfig) handlerNoteExpirationSet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	expiresAt, err := noteExpiration(params.ExpiresAt, cfg.Clock.Now())
+	if err != nil {
+		return err
+	}
+
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.SetNoteExpiration(r.Context(), database.SetNoteExpirationParams{
+		ExpiresAt: expiresAt,
+		UpdatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		ID:        noteID,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't set note expiration", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID, nil)
+	}
+	cfg.notesChanged(r.Context(), user.ID)
+
+	note, err := cfg.DB.GetNote(r.Context(), noteID)
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+	noteResp, err := databaseNoteToNote(note)
+	if err != nil {
+		return errInternal("Couldn't convert note", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, noteResp)
+	return nil
+}
+
+// noteExpiration validates a requested expiry time, which must lie after
+// now. nil means the note doesn't expire.
+func noteExpiration(expiresAt *time.Time, now time.Time) (sql.NullString, error) {
+	if expiresAt == nil {
+		return sql.NullString{}, nil
+	}
+	if !expiresAt.After(now) {
+		return sql.NullString{}, errValidation("expires_at must be in the future", nil)
+	}
+	return sql.NullString{String: expiresAt.UTC().Format(time.RFC3339), Valid: true}, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/auth"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerKeysGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	keys, err := cfg.DB.GetAPIKeysForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get api keys", err)
+	}
+
+	keysResp := make([]APIKey, len(keys))
+	for i, key := range keys {
+		keysResp[i], err = databaseAPIKeyToAPIKey(key, false)
+		if err != nil {
+			return errInternal("Couldn't convert api key", err)
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, keysResp)
+	return nil
+}
+
+// handlerKeysRotate issues a replacement for a key. The old key keeps working
+// for cfg.KeyRotationGrace so automated clients can switch over without downtime.
+func (cfg *apiConfig) handlerKeysRotate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	oldKey, err := cfg.DB.GetAPIKeyForUser(r.Context(), database.GetAPIKeyForUserParams{
+		ID:     chi.URLParam(r, "keyID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get api key", err)
+	}
+	if oldKey.SupersededBy.Valid {
+		return errConflict("Api key was already rotated", nil)
+	}
+
+	secret, err := auth.GenerateAPIKey()
+	if err != nil {
+		return errInternal("Couldn't gen apikey", err)
+	}
+
+	now := cfg.Clock.Now().UTC()
+	newKey := database.ApiKey{
+		ID:        uuid.New().String(),
+		CreatedAt: now.Format(time.RFC3339),
+		UserID:    user.ID,
+		ApiKey:    secret,
+	}
+
+	tx, err := cfg.beginTx(r.Context())
+	if err != nil {
+		return errInternal("Couldn't start transaction", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.CreateAPIKey(r.Context(), database.CreateAPIKeyParams{
+		ID:        newKey.ID,
+		CreatedAt: newKey.CreatedAt,
+		UserID:    newKey.UserID,
+		ApiKey:    newKey.ApiKey,
+	})
+	if err != nil {
+		return errInternal("Couldn't create api key", err)
+	}
+
+	n, err := tx.SupersedeAPIKey(r.Context(), database.SupersedeAPIKeyParams{
+		SupersededBy: sql.NullString{String: newKey.ID, Valid: true},
+		ExpiresAt:    sql.NullString{String: now.Add(cfg.KeyRotationGrace).Format(time.RFC3339), Valid: true},
+		ID:           oldKey.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't rotate api key", err)
+	}
+	if n == 0 {
+		return errConflict("Api key was already rotated", nil)
+	}
+
+	// The user row always mirrors the newest key.
+	err = tx.UpdateUserAPIKey(r.Context(), database.UpdateUserAPIKeyParams{
+		ApiKey:    newKey.ApiKey,
+		UpdatedAt: now.Format(time.RFC3339),
+		ID:        user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't update user", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errInternal("Couldn't rotate api key", err)
+	}
+	// The old key now expires; stop serving it from the cache.
+	cfg.authCache.forget(r.Context(), user.ID)
+	actor := audit.Actor{Type: audit.ActorUser, UserID: user.ID}
+	cfg.logSecurityEvent(r, audit.Event{
+		Type:   audit.TypeKeyCreated,
+		Reason: "rotation",
+		Actor:  actor,
+		Target: &audit.Target{UserID: user.ID, KeyID: newKey.ID},
+	})
+	cfg.logSecurityEvent(r, audit.Event{
+		Type:   audit.TypeKeyRevoked,
+		Reason: "rotation",
+		Actor:  actor,
+		Target: &audit.Target{UserID: user.ID, KeyID: oldKey.ID},
+	})
+
+	keyResp, err := databaseAPIKeyToAPIKey(newKey, true)
+	if err != nil {
+		return errInternal("Couldn't convert api key", err)
+	}
+
+	respondWithJSON(w, http.StatusCreated, keyResp)
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlerNoteLinks lists the URLs referenced by a note with the result of
+// their latest check, so users can fix broken references.
+func (cfg *apiConfig) handlerNoteLinks(w http.ResponseWriter, r *http.Request, user database.User) error {
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+
+	links, err := cfg.DB.GetLinksForNote(r.Context(), note.ID)
+	if err != nil {
+		return errInternal("Couldn't get links", err)
+	}
+
+	resp := make([]NoteLink, len(links))
+	for i, link := range links {
+		resp[i], err = databaseNoteLinkToNoteLink(link)
+		if err != nil {
+			return errInternal("Couldn't convert link", err)
+		}
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
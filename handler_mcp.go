@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/ctxkeys"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/mcp"
+)
+
+// Limits for the search_notes tool.
+const (
+	mcpDefaultSearchLimit = 20
+	mcpMaxSearchLimit     = 100
+)
+
+// handlerMCP serves the Model Context Protocol endpoint. Tools act on behalf
+// of the user authenticated by middlewareAuth, found through the request context.
+func (cfg *apiConfig) handlerMCP(srv *mcp.Server) authedHandler {
+	return func(w http.ResponseWriter, r *http.Request, _ database.User) error {
+		srv.ServeHTTP(w, r)
+		return nil
+	}
+}
+
+func (cfg *apiConfig) newMCPServer() *mcp.Server {
+	return mcp.NewServer("notely", "1.0.0",
+		mcp.Tool{
+			Name:        "search_notes",
+			Description: "Search the user's notes for words, best match first, like GET /v1/notes/search.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"query":{"type":"string","description":"Text to look for."},"limit":{"type":"integer","minimum":1,"maximum":100}},"required":["query"]}`),
+			Handler:     mcpTool(cfg.mcpSearchNotes),
+		},
+		mcp.Tool{
+			Name:        "get_note",
+			Description: "Get one of the user's notes by ID.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`),
+			Handler:     mcpTool(cfg.mcpGetNote),
+		},
+		mcp.Tool{
+			Name:        "create_note",
+			Description: "Create a note for the user.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"note":{"type":"string","description":"The note body."}},"required":["note"]}`),
+			Handler:     mcpTool(cfg.mcpCreateNote),
+		},
+	)
+}
+
+// mcpTool reports the errors of handler to the client the way the API
+// does, so database errors don't reach the model: the message of an
+// apiError, "note not found" for a missing note, and a generic message
+// otherwise. They're logged in full.
+func mcpTool(handler func(context.Context, json.RawMessage) (interface{}, error)) func(context.Context, json.RawMessage) (interface{}, error) {
+	return func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		out, err := handler(ctx, args)
+		if err != nil {
+			log.Printf("MCP tool failed: %v", err)
+			return nil, mcpToolError(err)
+		}
+		return out, nil
+	}
+}
+
+func mcpToolError(err error) error {
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &apiErr):
+		return errors.New(apiErr.Msg)
+	case errors.Is(err, database.ErrNotFound):
+		return errors.New("note not found")
+	}
+	return errors.New("internal server error")
+}
+
+func (cfg *apiConfig) mcpSearchNotes(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	user, ok := ctxkeys.User(ctx)
+	if !ok {
+		return nil, errUnauthorized("Not authenticated", nil)
+	}
+	var params struct {
+		Query string `json:"query"`
+		Limit int64  `json:"limit"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return nil, errValidation("Couldn't decode arguments", err)
+	}
+	if params.Limit <= 0 || params.Limit > mcpMaxSearchLimit {
+		params.Limit = mcpDefaultSearchLimit
+	}
+
+	query := ftsQuery(params.Query)
+	if query == "" {
+		return nil, errValidation("query must not be empty", nil)
+	}
+
+	rows, err := cfg.DB.SearchNotes(ctx, database.SearchNotesParams{
+		Query:     query,
+		UserID:    user.ID,
+		AfterRank: -math.MaxFloat64,
+		Limit:     params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scoredNotes(rows)
+}
+
+func (cfg *apiConfig) mcpGetNote(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	user, ok := ctxkeys.User(ctx)
+	if !ok {
+		return nil, errUnauthorized("Not authenticated", nil)
+	}
+	var params struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return nil, errValidation("Couldn't decode arguments", err)
+	}
+
+	note, err := cfg.DB.GetNoteByID(ctx, database.GetNoteByIDParams{ID: params.ID, UserID: user.ID})
+	if err != nil {
+		return nil, err
+	}
+	return databaseNoteToNote(note)
+}
+
+func (cfg *apiConfig) mcpCreateNote(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	user, ok := ctxkeys.User(ctx)
+	if !ok {
+		return nil, errUnauthorized("Not authenticated", nil)
+	}
+	var params struct {
+		Note string `json:"note"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return nil, errValidation("Couldn't decode arguments", err)
+	}
+	if err := cfg.checkWritable(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := cfg.checkNoteQuota(ctx, user); err != nil {
+		return nil, err
+	}
+	note, err := cfg.createNote(ctx, user, database.CreateNoteParams{Note: params.Note})
+	if err != nil {
+		return nil, err
+	}
+	return databaseNoteToNote(note)
+}
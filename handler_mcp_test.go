@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+func TestMCPToolError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "api error", err: errValidation("query must not be empty", nil), want: "query must not be empty"},
+		{name: "api error with cause", err: errInternal("Couldn't get plan", errors.New("SQLITE_BUSY: database is locked")), want: "Couldn't get plan"},
+		{name: "not found", err: fmt.Errorf("get note: %w", database.ErrNotFound), want: "note not found"},
+		{name: "other", err: errors.New("libsql: no such column: notes.secret"), want: "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mcpToolError(tt.err).Error(); got != tt.want {
+				t.Errorf("mcpToolError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxNotebookNameLength is the longest notebook name, in characters.
+const maxNotebookNameLength = 100
+
+// handlerNotebooksGet lists all of the user's notebooks by name, each with
+// its parent, so clients can build the tree, and how many notes are in it.
+func (cfg *apiConfig) handlerNotebooksGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	rows, err := cfg.DB.GetNotebooksForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get notebooks", err)
+	}
+	notebooks := make([]Notebook, len(rows))
+	for i, row := range rows {
+		notebooks[i], err = databaseNotebookToNotebook(database.Notebook{
+			ID:        row.ID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			UserID:    row.UserID,
+			ParentID:  row.ParentID,
+			Name:      row.Name,
+		}, row.Notes)
+		if err != nil {
+			return errInternal("Couldn't convert notebook", err)
+		}
+	}
+	respondWithJSONList(w, http.StatusOK, notebooks)
+	return nil
+}
+
+// handlerNotebooksCreate adds a notebook, at the top or, with parent_id,
+// inside another notebook.
+func (cfg *apiConfig) handlerNotebooksCreate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Name     string  `json:"name"`
+		ParentID *string `json:"parent_id"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	name, err := notebookName(params.Name)
+	if err != nil {
+		return err
+	}
+	parentID, err := cfg.notebookRef(r.Context(), user.ID, params.ParentID)
+	if err != nil {
+		return err
+	}
+
+	now := cfg.Clock.Now().UTC().Format(time.RFC3339)
+	notebook, err := cfg.DB.CreateNotebook(r.Context(), database.CreateNotebookParams{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    user.ID,
+		ParentID:  parentID,
+		Name:      name,
+	})
+	if errors.Is(err, database.ErrConflict) {
+		return errConflict("There's already a notebook named "+name+" there", nil)
+	}
+	if err != nil {
+		return errInternal("Couldn't create notebook", err)
+	}
+
+	resp, err := databaseNotebookToNotebook(notebook, 0)
+	if err != nil {
+		return errInternal("Couldn't convert notebook", err)
+	}
+	respondWithJSON(w, http.StatusCreated, resp)
+	return nil
+}
+
+// handlerNotebookGet responds with one of the user's notebooks.
+func (cfg *apiConfig) handlerNotebookGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	notebook, err := cfg.DB.GetNotebook(r.Context(), database.GetNotebookParams{
+		ID:     chi.URLParam(r, "notebookID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get notebook", err)
+	}
+	resp, err := cfg.notebookWithCount(r.Context(), notebook)
+	if err != nil {
+		return err
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// handlerNotebookUpdate renames a notebook or moves it, with its notes and
+// the notebooks in it, to another parent; a null parent_id moves it to the
+// top.
+func (cfg *apiConfig) handlerNotebookUpdate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Name     string  `json:"name"`
+		ParentID *string `json:"parent_id"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	name, err := notebookName(params.Name)
+	if err != nil {
+		return err
+	}
+	notebookID := chi.URLParam(r, "notebookID")
+	if _, err := cfg.DB.GetNotebook(r.Context(), database.GetNotebookParams{ID: notebookID, UserID: user.ID}); err != nil {
+		return errInternal("Couldn't get notebook", err)
+	}
+	parentID, err := cfg.notebookRef(r.Context(), user.ID, params.ParentID)
+	if err != nil {
+		return err
+	}
+	if parentID.Valid {
+		tree, err := cfg.DB.GetNotebookTree(r.Context(), notebookID)
+		if err != nil {
+			return errInternal("Couldn't get notebooks", err)
+		}
+		if slices.Contains(tree, parentID.String) {
+			return errValidation("A notebook can't be moved into itself or a notebook inside it", nil)
+		}
+	}
+
+	notebook, err := cfg.DB.UpdateNotebook(r.Context(), database.UpdateNotebookParams{
+		Name:      name,
+		ParentID:  parentID,
+		UpdatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		ID:        notebookID,
+		UserID:    user.ID,
+	})
+	if errors.Is(err, database.ErrConflict) {
+		return errConflict("There's already a notebook named "+name+" there", nil)
+	}
+	if err != nil {
+		return errInternal("Couldn't update notebook", err)
+	}
+	resp, err := cfg.notebookWithCount(r.Context(), notebook)
+	if err != nil {
+		return err
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// handlerNotebookDelete deletes a notebook and the notebooks inside it. The
+// notes in them aren't deleted, only taken out of any notebook.
+func (cfg *apiConfig) handlerNotebookDelete(w http.ResponseWriter, r *http.Request, user database.User) error {
+	notebookID := chi.URLParam(r, "notebookID")
+	tx, err := cfg.beginTx(r.Context())
+	if err != nil {
+		return errInternal("Couldn't start transaction", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.GetNotebook(r.Context(), database.GetNotebookParams{ID: notebookID, UserID: user.ID}); err != nil {
+		return errInternal("Couldn't get notebook", err)
+	}
+	if err := tx.UnfileNotesInNotebookTree(r.Context(), notebookID); err != nil {
+		return errInternal("Couldn't take notes out of notebook", err)
+	}
+	if err := tx.DeleteNotebookTree(r.Context(), notebookID); err != nil {
+		return errInternal("Couldn't delete notebook", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return errInternal("Couldn't delete notebook", err)
+	}
+	cfg.notesChanged(r.Context(), user.ID)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// handlerNoteNotebookSet moves a note into the notebook notebook_id, or out
+// of any notebook if it's null.
+func (cfg *apiConfig) handlerNoteNotebookSet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		NotebookID *string `json:"notebook_id"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	notebookID, err := cfg.notebookRef(r.Context(), user.ID, params.NotebookID)
+	if err != nil {
+		return err
+	}
+
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.SetNoteNotebook(r.Context(), database.SetNoteNotebookParams{
+		NotebookID: notebookID,
+		UpdatedAt:  cfg.Clock.Now().UTC().Format(time.RFC3339),
+		ID:         noteID,
+		UserID:     user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't set note notebook", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID, nil)
+	}
+	cfg.notesChanged(r.Context(), user.ID)
+
+	note, err := cfg.DB.GetNote(r.Context(), noteID)
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+	noteResp, err := databaseNoteToNote(note)
+	if err != nil {
+		return errInternal("Couldn't convert note", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, noteResp)
+	return nil
+}
+
+// notebookName trims name and checks it can name a notebook.
+func notebookName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errValidation("Notebook name can't be empty", nil)
+	}
+	if utf8.RuneCountInString(name) > maxNotebookNameLength {
+		return "", errValidation("Notebook names can be at most 100 characters", nil)
+	}
+	return name, nil
+}
+
+// notebookRef checks that id, if set, is one of the user's notebooks, and
+// returns it for storing in a notebook reference.
+func (cfg *apiConfig) notebookRef(ctx context.Context, userID string, id *string) (sql.NullString, error) {
+	if id == nil {
+		return sql.NullString{}, nil
+	}
+	_, err := cfg.DB.GetNotebook(ctx, database.GetNotebookParams{ID: *id, UserID: userID})
+	if errors.Is(err, database.ErrNotFound) {
+		return sql.NullString{}, errNotFound("Couldn't find notebook "+*id, nil)
+	}
+	if err != nil {
+		return sql.NullString{}, errInternal("Couldn't get notebook", err)
+	}
+	return sql.NullString{String: *id, Valid: true}, nil
+}
+
+// notebookWithCount converts notebook, counting the notes in it.
+func (cfg *apiConfig) notebookWithCount(ctx context.Context, notebook database.Notebook) (Notebook, error) {
+	notes, err := cfg.DB.CountNotesInNotebook(ctx, sql.NullString{String: notebook.ID, Valid: true})
+	if err != nil {
+		return Notebook{}, errInternal("Couldn't count notes", err)
+	}
+	resp, err := databaseNotebookToNotebook(notebook, notes)
+	if err != nil {
+		return Notebook{}, errInternal("Couldn't convert notebook", err)
+	}
+	return resp, nil
+}
@@ -1,66 +1,426 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
-func (cfg *apiConfig) handlerNotesGet(w http.ResponseWriter, r *http.Request, user database.User) {
-	posts, err := cfg.DB.GetNotesForUser(r.Context(), user.ID)
+// Limits for the number of notes listed per page.
+const (
+	defaultNotesLimit = 50
+	maxNotesLimit     = 200
+)
+
+// handlerNotesGet lists the user's notes: all of them as an array, or with
+// ?limit= or ?offset= a page of them, newest first, with the total count.
+// ?sort= and ?order= change the order; see queryNoteOrder. The array is in
+// no particular order without them, except that pinned notes come first.
+// ?tag= and ?notebook_id= list only the notes with a tag or in a notebook,
+// and ?archived=true only archived notes, which are otherwise left out; see
+// queryNoteFilter.
+func (cfg *apiConfig) handlerNotesGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	query := r.URL.Query()
+	if query.Has("cursor") {
+		return cfg.handlerNotesCursorGet(w, r, user)
+	} else if query.Has("limit") || query.Has("offset") {
+		return cfg.handlerNotesPageGet(w, r, user)
+	} else if query.Has("sort") || query.Has("order") || query.Get("archived") == "true" {
+		// The cached note list has no archived notes in it.
+		return cfg.handlerNotesSortedGet(w, r, user)
+	}
+	filter, err := cfg.queryNoteFilter(r, user)
+	if err != nil {
+		return err
+	}
+	postsResp, err := cfg.noteList(r.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	if !filter.empty() {
+		postsResp = filter.apply(postsResp)
+	}
+
+	respondWithJSONList(w, http.StatusOK, postsResp)
+	return nil
+}
+
+// handlerNotesSortedGet lists all of the user's notes as an array, like
+// handlerNotesGet without parameters, but in the order asked for.
+func (cfg *apiConfig) handlerNotesSortedGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	order, err := queryNoteOrder(r)
+	if err != nil {
+		return err
+	}
+	filter, err := cfg.queryNoteFilter(r, user)
+	if err != nil {
+		return err
+	}
+	var posts []database.Note
+	if !filter.empty() {
+		posts, err = cfg.filteredNotes(r.Context(), user.ID, filter, order)
+	} else {
+		// SQLite treats a negative limit as none.
+		posts, err = cfg.notesPage(r.Context(), user.ID, order, -1, 0)
+	}
+	if err != nil {
+		return errInternal("Couldn't get posts for user", err)
+	}
+	postsResp, err := databasePostsToPosts(posts)
+	if err != nil {
+		return errInternal("Couldn't convert posts", err)
+	}
+	if err := cfg.addNoteDetails(r.Context(), user.ID, postsResp); err != nil {
+		return err
+	}
+
+	respondWithJSONList(w, http.StatusOK, postsResp)
+	return nil
+}
+
+func (cfg *apiConfig) handlerNotesPageGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	limit, err := queryLimit(r, defaultNotesLimit, maxNotesLimit)
+	if err != nil {
+		return err
+	}
+	offset, err := queryOffset(r)
+	if err != nil {
+		return err
+	}
+	order, err := queryNoteOrder(r)
+	if err != nil {
+		return err
+	}
+	filter, err := cfg.queryNoteFilter(r, user)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't get posts for user", err)
-		return
+		return err
 	}
 
+	var total int64
+	var posts []database.Note
+	if !filter.empty() {
+		// A user has few enough notes with a tag, in a notebook or archived
+		// to page through in memory.
+		posts, err = cfg.filteredNotes(r.Context(), user.ID, filter, order)
+		if err != nil {
+			return errInternal("Couldn't get posts for user", err)
+		}
+		total = int64(len(posts))
+		posts = posts[min(offset, len(posts)):min(offset+limit, len(posts))]
+	} else {
+		total, err = cfg.DB.CountUnarchivedNotesForUser(r.Context(), user.ID)
+		if err != nil {
+			return errInternal("Couldn't count notes", err)
+		}
+		posts, err = cfg.notesPage(r.Context(), user.ID, order, int64(limit), int64(offset))
+		if err != nil {
+			return errInternal("Couldn't get posts for user", err)
+		}
+	}
 	postsResp, err := databasePostsToPosts(posts)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't convert posts", err)
-		return
+		return errInternal("Couldn't convert posts", err)
+	}
+	if err := cfg.addNoteDetails(r.Context(), user.ID, postsResp); err != nil {
+		return err
+	}
+
+	respondWithJSON(w, http.StatusOK, NotePage{
+		Results: postsResp,
+		Meta:    PageMeta{Total: total, Limit: limit, Offset: offset},
+	})
+	return nil
+}
+
+// handlerNotesCursorGet responds with a page of the user's notes, newest
+// first unless ?sort= and ?order= say otherwise, starting after the
+// position in ?cursor=, which is empty for the first page. Unlike an
+// offset, a cursor doesn't shift when notes are added or deleted between
+// pages.
+func (cfg *apiConfig) handlerNotesCursorGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	if r.URL.Query().Has("offset") {
+		return errValidation("Cursor and offset can't be combined", nil)
+	}
+	limit, err := queryLimit(r, defaultNotesLimit, maxNotesLimit)
+	if err != nil {
+		return err
+	}
+	order, err := queryNoteOrder(r)
+	if err != nil {
+		return err
+	}
+	filter, err := cfg.queryNoteFilter(r, user)
+	if err != nil {
+		return err
+	}
+	// Cursors are issued for one order and filter, so search cursors and
+	// those for another order or filter aren't accepted in their place.
+	cursorQuery := searchQueryHash(slices.Concat([]string{"notes"}, order.cursorParts(), filter.cursorParts())...)
+	cursor, err := querySearchCursor(r, cursorQuery)
+	if err != nil {
+		return err
+	}
+
+	// One extra note tells whether there's a page after this one.
+	var posts []database.Note
+	if !filter.empty() {
+		posts, err = cfg.filteredNotes(r.Context(), user.ID, filter, order)
+		if err == nil && cursor != nil {
+			after := order.cursorPosition(cursor)
+			start := slices.IndexFunc(posts, func(note database.Note) bool {
+				return order.compare(order.position(note), after) > 0
+			})
+			if start < 0 {
+				start = len(posts)
+			}
+			posts = posts[start:]
+		}
+		posts = posts[:min(limit+1, len(posts))]
+	} else if cursor == nil {
+		posts, err = cfg.notesPage(r.Context(), user.ID, order, int64(limit)+1, 0)
+	} else {
+		posts, err = cfg.notesAfter(r.Context(), user.ID, order, order.cursorPosition(cursor), int64(limit)+1)
+	}
+	if err != nil {
+		return errInternal("Couldn't get posts for user", err)
+	}
+
+	page := NoteCursorPage{}
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[limit-1]
+		page.Meta.NextCursor = encodeSearchCursor(order.cursor(cursorQuery, order.position(last)))
+	}
+	page.Results, err = databasePostsToPosts(posts)
+	if err != nil {
+		return errInternal("Couldn't convert posts", err)
+	}
+	if err := cfg.addNoteDetails(r.Context(), user.ID, page.Results); err != nil {
+		return err
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+	return nil
+}
+
+// handlerNoteGet responds with one of the user's notes, with the same link
+// previews, reactions, views and tags as in the note list.
+func (cfg *apiConfig) handlerNoteGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+	resp, err := databaseNoteToNote(note)
+	if err != nil {
+		return errInternal("Couldn't convert note", err)
+	}
+
+	previews, err := cfg.DB.GetLinkPreviewsForNote(r.Context(), note.ID)
+	if err != nil {
+		return errInternal("Couldn't get link previews", err)
+	}
+	for _, preview := range previews {
+		if preview.Title.Valid {
+			resp.LinkPreviews = append(resp.LinkPreviews, databaseLinkPreviewToLinkPreview(preview))
+		}
+	}
+	reactions, err := cfg.DB.GetNoteReactionCounts(r.Context(), note.ID)
+	if err != nil {
+		return errInternal("Couldn't get reactions", err)
+	}
+	for _, row := range reactions {
+		resp.Reactions = append(resp.Reactions, ReactionCount{Emoji: row.Emoji, Count: row.Count})
+	}
+	view, err := cfg.DB.GetNoteView(r.Context(), note.ID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return errInternal("Couldn't get views", err)
+	}
+	if err == nil {
+		lastViewedAt, err := time.Parse(time.RFC3339, view.LastViewedAt)
+		if err != nil {
+			return errInternal("Couldn't parse view time", err)
+		}
+		resp.Views, resp.LastViewedAt = view.Views, &lastViewedAt
+	}
+	resp.Tags, err = cfg.DB.GetNoteTags(r.Context(), note.ID)
+	if err != nil {
+		return errInternal("Couldn't get tags", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// noteList returns all of userID's notes with their link previews,
+// reactions, views and tags. Concurrent requests for the same user share one set of
+// queries, so the result mustn't be modified.
+func (cfg *apiConfig) noteList(ctx context.Context, userID string) ([]Note, error) {
+	v, err, _ := cfg.noteLists.Do(tenantUserKey(ctx, userID), func() (any, error) {
+		// Other requests may be waiting on the result even if this one is
+		// canceled.
+		ctx := context.WithoutCancel(ctx)
+		posts, err := cfg.DB.GetNotesForUser(ctx, userID)
+		if err != nil {
+			return nil, errInternal("Couldn't get posts for user", err)
+		}
+
+		postsResp, err := databasePostsToPosts(posts)
+		if err != nil {
+			return nil, errInternal("Couldn't convert posts", err)
+		}
+		slices.SortStableFunc(postsResp, func(a, b Note) int {
+			return cmp.Compare(pinnedRank(b.Pinned), pinnedRank(a.Pinned))
+		})
+		if err := cfg.addNoteDetails(ctx, userID, postsResp); err != nil {
+			return nil, err
+		}
+		return postsResp, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return v.([]Note), nil
+}
 
-	respondWithJSON(w, http.StatusOK, postsResp)
+// addNoteDetails fills in the link previews, reactions, views and tags of
+// notes, all of which belong to userID.
+func (cfg *apiConfig) addNoteDetails(ctx context.Context, userID string, notes []Note) error {
+	previews, err := cfg.linkPreviewsByNote(ctx, userID)
+	if err != nil {
+		return errInternal("Couldn't get link previews", err)
+	}
+	reactions, err := cfg.noteReactionsByNote(ctx, userID)
+	if err != nil {
+		return errInternal("Couldn't get reactions", err)
+	}
+	views, err := cfg.noteViewsByNote(ctx, userID)
+	if err != nil {
+		return errInternal("Couldn't get views", err)
+	}
+	tags, err := cfg.noteTagsByNote(ctx, userID)
+	if err != nil {
+		return errInternal("Couldn't get tags", err)
+	}
+	for i := range notes {
+		notes[i].LinkPreviews = previews[notes[i].ID]
+		notes[i].Reactions = reactions[notes[i].ID]
+		notes[i].Tags = tags[notes[i].ID]
+		if v, ok := views[notes[i].ID]; ok {
+			lastViewedAt, err := time.Parse(time.RFC3339, v.LastViewedAt)
+			if err != nil {
+				return errInternal("Couldn't parse view time", err)
+			}
+			notes[i].Views, notes[i].LastViewedAt = v.Views, &lastViewedAt
+		}
+	}
+	return nil
 }
 
-func (cfg *apiConfig) handlerNotesCreate(w http.ResponseWriter, r *http.Request, user database.User) {
+func (cfg *apiConfig) handlerNotesCreate(w http.ResponseWriter, r *http.Request, user database.User) error {
 	type parameters struct {
-		Note string `json:"note"`
+		Note       string     `json:"note"`
+		ExpiresAt  *time.Time `json:"expires_at"`
+		Tags       []string   `json:"tags"`
+		NotebookID *string    `json:"notebook_id"`
 	}
 	decoder := json.NewDecoder(r.Body)
 	params := parameters{}
 	err := decoder.Decode(&params)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
-		return
+		return errValidation("Couldn't decode parameters", err)
 	}
-
-	id := uuid.New().String()
-	err = cfg.DB.CreateNote(r.Context(), database.CreateNoteParams{
-		ID:        id,
-		CreatedAt: time.Now().UTC().Format(time.RFC3339),
-		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
-		Note:      params.Note,
-		UserID:    user.ID,
-	})
+	expiresAt, err := noteExpiration(params.ExpiresAt, cfg.Clock.Now())
+	if err != nil {
+		return err
+	}
+	tags, err := normalizeTags(params.Tags)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't create note", err)
-		return
+		return err
+	}
+	notebookID, err := cfg.notebookRef(r.Context(), user.ID, params.NotebookID)
+	if err != nil {
+		return err
+	}
+	if err := cfg.checkNoteQuota(r.Context(), user); err != nil {
+		return err
 	}
 
-	note, err := cfg.DB.GetNote(r.Context(), id)
+	note, err := cfg.createNote(r.Context(), user, database.CreateNoteParams{
+		Note:       params.Note,
+		ExpiresAt:  expiresAt,
+		NotebookID: notebookID,
+	})
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, "Couldn't get note", err)
-		return
+		return errInternal("Couldn't create note", err)
+	}
+	if len(tags) > 0 {
+		if err := setNoteTags(r.Context(), cfg.DB, note.ID, user.ID, tags); err != nil {
+			return errInternal("Couldn't save tags", err)
+		}
 	}
 
 	noteResp, err := databaseNoteToNote(note)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't convert note", err)
-		return
+		return errInternal("Couldn't convert note", err)
 	}
+	noteResp.Tags = tags
 
 	respondWithJSON(w, http.StatusCreated, noteResp)
+	return nil
+}
+
+// createNote saves a new note for user, announces it and returns it as stored.
+// The ID, timestamps, owner and title in params are filled in here.
+func (cfg *apiConfig) createNote(ctx context.Context, user database.User, params database.CreateNoteParams) (database.Note, error) {
+	note, err := cfg.insertNote(ctx, cfg.DB, user, params)
+	if err != nil {
+		return database.Note{}, err
+	}
+	cfg.noteCreated(ctx, note)
+	return note, nil
+}
+
+// insertNote saves a new note for user through db, filling in params like
+// createNote, and returns it as stored without announcing it.
+func (cfg *apiConfig) insertNote(ctx context.Context, db *database.Store, user database.User, params database.CreateNoteParams) (database.Note, error) {
+	params.ID = uuid.New().String()
+	params.Title = noteTitle(params.Note)
+	params.CreatedAt = cfg.Clock.Now().UTC().Format(time.RFC3339)
+	params.UpdatedAt = params.CreatedAt
+	params.UserID = user.ID
+	err := db.CreateNote(ctx, params)
+	if err != nil {
+		return database.Note{}, err
+	}
+	return db.GetNote(ctx, params.ID)
+}
+
+// noteCreated announces a note saved by insertNote and indexes it. Call it
+// once the note is committed.
+func (cfg *apiConfig) noteCreated(ctx context.Context, note database.Note) {
+	cfg.publishEvent(ctx, events.TypeNoteCreated, note.UserID, note.ID)
+	cfg.embedNote(ctx, note)
+	cfg.indexNoteTitle(ctx, note.ID, note.UserID, note.Title)
+	cfg.recordLinks(ctx, note)
+}
+
+// noteContentHash identifies a version of a note body, so results derived
+// from it can be cached until the content changes.
+func noteContentHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/quiethours"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultNotificationsLimit = 50
+	maxNotificationsLimit     = 200
+)
+
+// handlerNotificationsGet lists the user's notifications, newest first, with
+// the number of unread ones. ?unread=true leaves out those already read.
+func (cfg *apiConfig) handlerNotificationsGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	limit, err := queryLimit(r, defaultNotificationsLimit, maxNotificationsLimit)
+	if err != nil {
+		return err
+	}
+	offset, err := queryOffset(r)
+	if err != nil {
+		return err
+	}
+
+	// Notifications held back by quiet hours only show up once delivered.
+	now := sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true}
+	var notifications []database.Notification
+	if r.URL.Query().Get("unread") == "true" {
+		notifications, err = cfg.DB.GetUnreadNotificationsForUser(r.Context(), database.GetUnreadNotificationsForUserParams{
+			UserID: user.ID,
+			Now:    now,
+			Limit:  int64(limit),
+			Offset: int64(offset),
+		})
+	} else {
+		notifications, err = cfg.DB.GetNotificationsForUser(r.Context(), database.GetNotificationsForUserParams{
+			UserID: user.ID,
+			Now:    now,
+			Limit:  int64(limit),
+			Offset: int64(offset),
+		})
+	}
+	if err != nil {
+		return errInternal("Couldn't get notifications", err)
+	}
+	unread, err := cfg.DB.CountUnreadNotifications(r.Context(), database.CountUnreadNotificationsParams{
+		UserID: user.ID,
+		Now:    now,
+	})
+	if err != nil {
+		return errInternal("Couldn't count unread notifications", err)
+	}
+
+	resp := NotificationList{
+		UnreadCount:   unread,
+		Notifications: make([]Notification, len(notifications)),
+	}
+	for i, notification := range notifications {
+		resp.Notifications[i], err = databaseNotificationToNotification(notification)
+		if err != nil {
+			return errInternal("Couldn't convert notification", err)
+		}
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+func (cfg *apiConfig) handlerNotificationRead(w http.ResponseWriter, r *http.Request, user database.User) error {
+	notificationID := chi.URLParam(r, "notificationID")
+	n, err := cfg.DB.MarkNotificationRead(r.Context(), database.MarkNotificationReadParams{
+		ReadAt: sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true},
+		ID:     notificationID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't mark notification read", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find notification "+notificationID, nil)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (cfg *apiConfig) handlerNotificationsReadAll(w http.ResponseWriter, r *http.Request, user database.User) error {
+	now := sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true}
+	err := cfg.DB.MarkAllNotificationsRead(r.Context(), database.MarkAllNotificationsReadParams{
+		ReadAt: now,
+		UserID: user.ID,
+		Now:    now,
+	})
+	if err != nil {
+		return errInternal("Couldn't mark notifications read", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// handlerNotificationPreferencesGet reports, per type of notification, which
+// channels deliver it, e.g. {"comment.created": {"in_app": true}}.
+func (cfg *apiConfig) handlerNotificationPreferencesGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	prefs, err := cfg.notificationPreferences(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get notification preferences", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, prefs)
+	return nil
+}
+
+// handlerNotificationPreferencesUpdate changes parts of the preference matrix.
+// Types and channels missing from the request keep their setting.
+func (cfg *apiConfig) handlerNotificationPreferencesUpdate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	params := notificationPreferences{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	prefs, err := cfg.notificationPreferences(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get notification preferences", err)
+	}
+	for notificationType, channels := range params {
+		if _, ok := prefs[notificationType]; !ok {
+			return errValidation("Unknown notification type "+notificationType, nil)
+		}
+		for channel := range channels {
+			if _, ok := prefs[notificationType][channel]; !ok {
+				return errValidation("Unknown notification channel "+channel, nil)
+			}
+		}
+	}
+
+	tx, err := cfg.beginTx(r.Context())
+	if err != nil {
+		return errInternal("Couldn't start transaction", err)
+	}
+	defer tx.Rollback()
+
+	for notificationType, channels := range params {
+		for channel, enabled := range channels {
+			err := tx.UpsertNotificationPreference(r.Context(), database.UpsertNotificationPreferenceParams{
+				UserID:    user.ID,
+				EventType: notificationType,
+				Channel:   channel,
+				Enabled:   enabled,
+			})
+			if err != nil {
+				return errInternal("Couldn't update notification preferences", err)
+			}
+			prefs[notificationType][channel] = enabled
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errInternal("Couldn't update notification preferences", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, prefs)
+	return nil
+}
+
+// handlerNotificationPreferencesReset goes back to the default preferences.
+func (cfg *apiConfig) handlerNotificationPreferencesReset(w http.ResponseWriter, r *http.Request, user database.User) error {
+	err := cfg.DB.DeleteNotificationPreferences(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't reset notification preferences", err)
+	}
+
+	return cfg.handlerNotificationPreferencesGet(w, r, user)
+}
+
+func (cfg *apiConfig) handlerQuietHoursGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	quietHours, err := cfg.DB.GetNotificationQuietHours(r.Context(), user.ID)
+	if err != nil {
+		return errNotFound("No quiet hours set", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseQuietHoursToQuietHours(quietHours))
+	return nil
+}
+
+// handlerQuietHoursUpdate sets the daily period, in the user's time zone,
+// during which non-urgent notifications are held back.
+func (cfg *apiConfig) handlerQuietHoursUpdate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	params := QuietHours{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	schedule, err := quiethours.New(params.Start, params.End, params.Timezone)
+	if err != nil {
+		return errValidation("Invalid quiet hours", err)
+	}
+
+	err = cfg.DB.UpsertNotificationQuietHours(r.Context(), database.UpsertNotificationQuietHoursParams{
+		UserID:    user.ID,
+		StartTime: schedule.Start.String(),
+		EndTime:   schedule.End.String(),
+		Timezone:  schedule.Location.String(),
+	})
+	if err != nil {
+		return errInternal("Couldn't set quiet hours", err)
+	}
+
+	return cfg.handlerQuietHoursGet(w, r, user)
+}
+
+func (cfg *apiConfig) handlerQuietHoursDelete(w http.ResponseWriter, r *http.Request, user database.User) error {
+	err := cfg.DB.DeleteNotificationQuietHours(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't remove quiet hours", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlerNotePin pins a note, so it comes first in the default note list,
+// and responds with it.
+func (cfg *apiConfig) handlerNotePin(w http.ResponseWriter, r *http.Request, user database.User) error {
+	return cfg.setNotePinned(w, r, user, true)
+}
+
+// handlerNoteUnpin unpins a note and responds with it.
+func (cfg *apiConfig) handlerNoteUnpin(w http.ResponseWriter, r *http.Request, user database.User) error {
+	return cfg.setNotePinned(w, r, user, false)
+}
+
+func (cfg *apiConfig) setNotePinned(w http.ResponseWriter, r *http.Request, user database.User, pinned bool) error {
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.SetNotePinned(r.Context(), database.SetNotePinnedParams{
+		Pinned: pinned,
+		ID:     noteID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't pin note", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID, nil)
+	}
+	eventType := events.TypeNoteUnpinned
+	if pinned {
+		eventType = events.TypeNotePinned
+	}
+	cfg.publishEvent(r.Context(), eventType, user.ID, noteID)
+	return cfg.respondWithNote(w, r, noteID)
+}
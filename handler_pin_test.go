@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestNotesPinnedFirst(t *testing.T) {
+	api := newTestAPI(t)
+	_, key := api.newUser(t)
+	for _, body := range []string{"a", "b", "c", "d", "e"} {
+		note := api.newNote(t, key, body)
+		if body == "b" || body == "d" {
+			if code := api.do(t, http.MethodPost, "/v1/notes/"+note.ID+"/pin", key, nil, nil); code != http.StatusOK {
+				t.Fatalf("pinning %s: status %d", body, code)
+			}
+		}
+	}
+
+	pinnedFirst := []string{"d", "b", "e", "c", "a"}
+	newestFirst := []string{"e", "d", "c", "b", "a"}
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "default", query: "", want: pinnedFirst},
+		{name: "explicit sort", query: "sort=created_at", want: newestFirst},
+		{name: "explicit order", query: "order=desc", want: newestFirst},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.query == "" {
+				// The array is in no particular order apart from pinned
+				// notes coming first.
+				var notes []Note
+				api.do(t, http.MethodGet, "/v1/notes", key, nil, &notes)
+				if got := noteBodies(notes[:2]); !slices.Equal(got, []string{"b", "d"}) && !slices.Equal(got, []string{"d", "b"}) {
+					t.Errorf("array starts with %v, want the pinned notes", got)
+				}
+			} else {
+				var notes []Note
+				api.do(t, http.MethodGet, "/v1/notes?"+tt.query, key, nil, &notes)
+				if got := noteBodies(notes); !slices.Equal(got, tt.want) {
+					t.Errorf("array = %v, want %v", got, tt.want)
+				}
+			}
+
+			var page NotePage
+			api.do(t, http.MethodGet, "/v1/notes?limit=10&"+tt.query, key, nil, &page)
+			if got := noteBodies(page.Results); !slices.Equal(got, tt.want) {
+				t.Errorf("page = %v, want %v", got, tt.want)
+			}
+
+			// Page sizes that end a page at the last pinned note and that
+			// span pinned and unpinned notes.
+			for _, limit := range []string{"1", "2", "3"} {
+				if got := api.cursorPages(t, key, "limit="+limit+"&"+tt.query); !slices.Equal(got, tt.want) {
+					t.Errorf("cursor pages of %s = %v, want %v", limit, got, tt.want)
+				}
+			}
+		})
+	}
+
+	t.Run("cursor for another order", func(t *testing.T) {
+		var page NoteCursorPage
+		api.do(t, http.MethodGet, "/v1/notes?cursor=&limit=2", key, nil, &page)
+		target := "/v1/notes?sort=created_at&limit=2&cursor=" + url.QueryEscape(page.Meta.NextCursor)
+		if code := api.do(t, http.MethodGet, target, key, nil, nil); code != http.StatusBadRequest {
+			t.Errorf("GET with a cursor from the default order: status %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+}
+
+// cursorPages follows the cursors of GET /v1/notes with query to the last
+// page and returns the bodies of the notes on all of them.
+func (api *testAPI) cursorPages(t *testing.T, apiKey, query string) []string {
+	t.Helper()
+	var bodies []string
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		var page NoteCursorPage
+		target := "/v1/notes?" + query + "&cursor=" + url.QueryEscape(cursor)
+		if code := api.do(t, http.MethodGet, target, apiKey, nil, &page); code != http.StatusOK {
+			t.Fatalf("GET %s: status %d", target, code)
+		}
+		bodies = append(bodies, noteBodies(page.Results)...)
+		if page.Meta.NextCursor == "" {
+			return bodies
+		}
+		cursor = page.Meta.NextCursor
+	}
+	t.Fatalf("GET /v1/notes?%s: too many pages", query)
+	return nil
+}
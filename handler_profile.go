@@ -0,0 +1,104 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// profilePageSize is how many published notes a public profile page lists.
+const profilePageSize = 20
+
+// handlerUsersProfileUpdate sets whether the user's public profile at
+// /u/{username} is shown. A public profile needs a username.
+func (cfg *apiConfig) handlerUsersProfileUpdate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		ProfilePublic bool `json:"profile_public"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if params.ProfilePublic && !user.Username.Valid {
+		return errValidation("A public profile needs a username", nil)
+	}
+
+	err = cfg.DB.SetUserProfilePublic(r.Context(), database.SetUserProfilePublicParams{
+		ProfilePublic: params.ProfilePublic,
+		UpdatedAt:     cfg.Clock.Now().UTC().Format(time.RFC3339),
+		ID:            user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't update profile", err)
+	}
+	cfg.authCache.forget(r.Context(), user.ID)
+
+	return cfg.respondWithUser(w, r, user.ID)
+}
+
+// handlerProfile renders a user's public profile: their published notes,
+// newest first, profilePageSize at a time. Users who haven't opted in are
+// indistinguishable from ones that don't exist.
+func (cfg *apiConfig) handlerProfile(w http.ResponseWriter, r *http.Request) {
+	user, err := cfg.DB.GetUserByUsername(r.Context(), sql.NullString{String: normalizeUsername(chi.URLParam(r, "username")), Valid: true})
+	if err != nil {
+		siteLookupError(w, r, err)
+		return
+	}
+	if !user.ProfilePublic {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			http.Error(w, "Invalid page", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Fetch one extra note to tell whether there is a next page.
+	entries, err := cfg.DB.GetPublishedNoteListForUserPage(r.Context(), database.GetPublishedNoteListForUserPageParams{
+		UserID: user.ID,
+		Limit:  profilePageSize + 1,
+		Offset: int64(page-1) * profilePageSize,
+	})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Couldn't get published notes", http.StatusInternalServerError)
+		return
+	}
+
+	profilePath := "/u/" + user.Username.String
+	index := siteIndex{Author: user.Name}
+	if len(entries) > profilePageSize {
+		entries = entries[:profilePageSize]
+		index.NextPath = profilePath + "?page=" + strconv.Itoa(page+1)
+	}
+	if page == 2 {
+		index.PrevPath = profilePath
+	} else if page > 2 {
+		index.PrevPath = profilePath + "?page=" + strconv.Itoa(page-1)
+	}
+	for _, entry := range entries {
+		sn, err := databaseNoteListEntryToSiteNote(user, entry)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Couldn't render notes", http.StatusInternalServerError)
+			return
+		}
+		index.Notes = append(index.Notes, sn)
+	}
+
+	renderSiteTemplate(w, "index.html", index)
+}
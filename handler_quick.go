@@ -0,0 +1,239 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// Limits for the command palette. Candidates are the most recently updated
+// matching notes, ranked in memory; the limit applies after ranking.
+const (
+	defaultQuickLimit   = 8
+	maxQuickLimit       = 20
+	quickCandidateLimit = 50
+)
+
+// Command palette results are cached briefly per user and query, since
+// palettes query on every keystroke and often repeat a prefix when the user
+// deletes characters.
+const (
+	quickCacheTTL  = 30 * time.Second
+	quickCacheSize = 10000
+)
+
+const (
+	quickKindNote   = "note"
+	quickKindAction = "action"
+)
+
+// quickAction is something the client can do from the command palette.
+// Keywords are matched like the title but ranked lower.
+type quickAction struct {
+	ID       string
+	Title    string
+	Keywords string
+}
+
+var quickActions = []quickAction{
+	{ID: "note.create", Title: "New note", Keywords: "add write compose"},
+	{ID: "notes.publish", Title: "Publish notes", Keywords: "site share public"},
+	{ID: "notifications.open", Title: "Notifications", Keywords: "inbox alerts"},
+	{ID: "notifications.read_all", Title: "Mark all notifications read", Keywords: "inbox clear"},
+	{ID: "notifications.preferences", Title: "Notification settings", Keywords: "preferences quiet hours push"},
+	{ID: "keys.open", Title: "API keys", Keywords: "tokens rotate"},
+	{ID: "profile.open", Title: "Profile", Keywords: "username account"},
+}
+
+// handlerQuick serves the command palette: a short mixed list of notes and
+// actions matching q, best match first. Without q it lists the most recently
+// updated notes followed by all actions.
+func (cfg *apiConfig) handlerQuick(w http.ResponseWriter, r *http.Request, user database.User) error {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	limit, err := queryLimit(r, defaultQuickLimit, maxQuickLimit)
+	if err != nil {
+		return err
+	}
+
+	cacheKey := tenantUserKey(r.Context(), user.ID)
+	results, ok := cfg.quickCache.get(cacheKey, query)
+	if !ok {
+		results, err = cfg.quickResults(r, user, query)
+		if err != nil {
+			return err
+		}
+		cfg.quickCache.put(cacheKey, query, results)
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	respondWithJSON(w, http.StatusOK, results)
+	return nil
+}
+
+// quickResults ranks every note and action matching query, up to
+// maxQuickLimit, so cached results serve any limit.
+func (cfg *apiConfig) quickResults(r *http.Request, user database.User, query string) ([]QuickResult, error) {
+	notes, err := cfg.quickNotes(r, user, query)
+	if err != nil {
+		return nil, errInternal("Couldn't search notes", err)
+	}
+
+	type scored struct {
+		result QuickResult
+		score  int
+	}
+	candidates := make([]scored, 0, len(notes)+len(quickActions))
+	for _, note := range notes {
+		updatedAt, err := time.Parse(time.RFC3339, note.UpdatedAt)
+		if err != nil {
+			return nil, errInternal("Couldn't convert note", err)
+		}
+		title := noteTitle(note.Head)
+		candidates = append(candidates, scored{
+			result: QuickResult{Kind: quickKindNote, ID: note.ID, Title: title, UpdatedAt: &updatedAt},
+			// The query matched the note somewhere, so it ranks at least as a body match.
+			score: max(quickMatchScore(title, query), quickScoreOther),
+		})
+	}
+	for _, action := range quickActions {
+		score := quickMatchScore(action.Title, query)
+		if score == 0 && strings.Contains(action.Keywords, query) {
+			score = quickScoreOther
+		}
+		if score == 0 {
+			continue
+		}
+		candidates = append(candidates, scored{
+			result: QuickResult{Kind: quickKindAction, ID: action.ID, Title: action.Title},
+			score:  score,
+		})
+	}
+
+	// Notes come back newest first, which the stable sort keeps among equal scores.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	results := make([]QuickResult, 0, min(len(candidates), maxQuickLimit))
+	for _, c := range candidates[:min(len(candidates), maxQuickLimit)] {
+		results = append(results, c.result)
+	}
+	return results, nil
+}
+
+// quickNotes returns the candidate notes for query, most recently updated
+// first: notes with words starting with each word of query, through the
+// full-text index, or the latest notes if query is empty.
+func (cfg *apiConfig) quickNotes(r *http.Request, user database.User, query string) ([]database.QuickSearchNotesRow, error) {
+	if query == "" {
+		recent, err := cfg.DB.QuickRecentNotes(r.Context(), database.QuickRecentNotesParams{
+			UserID: user.ID,
+			Limit:  quickCandidateLimit,
+		})
+		notes := make([]database.QuickSearchNotesRow, 0, len(recent))
+		for _, note := range recent {
+			notes = append(notes, database.QuickSearchNotesRow(note))
+		}
+		return notes, err
+	}
+	words := strings.Fields(query)
+	for i := range words {
+		words[i] += "*"
+	}
+	match := ftsQuery(strings.Join(words, " "))
+	if match == "" {
+		return nil, nil
+	}
+	return cfg.DB.QuickSearchNotes(r.Context(), database.QuickSearchNotesParams{
+		Query:  match,
+		UserID: user.ID,
+		Limit:  quickCandidateLimit,
+	})
+}
+
+// Match scores, from an exact title down to a match only in a note's body
+// or an action's keywords.
+const (
+	quickScoreExact      = 5
+	quickScorePrefix     = 4
+	quickScoreWordPrefix = 3
+	quickScoreContains   = 2
+	quickScoreOther      = 1
+)
+
+// quickMatchScore rates how well title matches the lowercased query, or 0 if
+// it doesn't. An empty query matches everything equally.
+func quickMatchScore(title, query string) int {
+	title = strings.ToLower(title)
+	switch {
+	case query == "":
+		return quickScoreOther
+	case title == query:
+		return quickScoreExact
+	case strings.HasPrefix(title, query):
+		return quickScorePrefix
+	case strings.Contains(" "+title, " "+query):
+		return quickScoreWordPrefix
+	case strings.Contains(title, query):
+		return quickScoreContains
+	}
+	return 0
+}
+
+// quickCache holds command palette results per user, keyed by
+// tenantUserKey, and query for a short while. A user's entries are dropped
+// whenever one of their notes changes, so the TTL only bounds how long
+// cached results can lag behind changes that aren't announced that way.
+type quickCache struct {
+	mu      sync.Mutex
+	entries *ttlMap[quickCacheKey, []QuickResult]
+	byUser  map[string]map[string]bool // Queries cached per user key.
+}
+
+type quickCacheKey struct {
+	userKey string
+	query   string
+}
+
+func newQuickCache(ttl time.Duration, size int, clock clock.Clock) *quickCache {
+	c := &quickCache{byUser: make(map[string]map[string]bool)}
+	c.entries = newTTLMap(ttl, size, clock, func(key quickCacheKey, _ []QuickResult) {
+		delete(c.byUser[key.userKey], key.query)
+		if len(c.byUser[key.userKey]) == 0 {
+			delete(c.byUser, key.userKey)
+		}
+	})
+	return c
+}
+
+func (c *quickCache) get(userKey, query string) ([]QuickResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.get(quickCacheKey{userKey: userKey, query: query})
+}
+
+func (c *quickCache) put(userKey, query string, results []QuickResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.put(quickCacheKey{userKey: userKey, query: query}, results)
+	if c.byUser[userKey] == nil {
+		c.byUser[userKey] = make(map[string]bool)
+	}
+	c.byUser[userKey][query] = true
+}
+
+// forget drops a user's cached results, e.g. after one of their notes
+// changed.
+func (c *quickCache) forget(userKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for query := range c.byUser[userKey] {
+		c.entries.delete(quickCacheKey{userKey: userKey, query: query})
+	}
+	delete(c.byUser, userKey)
+}
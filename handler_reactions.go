@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// defaultReactionEmoji is the allowlist used unless REACTION_EMOJI is set.
+const defaultReactionEmoji = "👍,👎,❤️,🎉,😄,😕,🚀,👀"
+
+// parseReactionEmoji turns a comma-separated list into the allowlist of emoji
+// users may react with.
+func parseReactionEmoji(list string) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, emoji := range strings.Split(list, ",") {
+		if emoji = strings.TrimSpace(emoji); emoji != "" {
+			allowed[emoji] = true
+		}
+	}
+	return allowed
+}
+
+// reactionEmojiParam reads the {emoji} URL parameter and checks it against the allowlist.
+func (cfg *apiConfig) reactionEmojiParam(r *http.Request) (string, error) {
+	emoji, err := url.PathUnescape(chi.URLParam(r, "emoji"))
+	if err != nil {
+		return "", errValidation("Couldn't decode emoji", err)
+	}
+	if !cfg.reactionEmoji[emoji] {
+		return "", errValidation("emoji "+emoji+" isn't an allowed reaction", nil)
+	}
+	return emoji, nil
+}
+
+// handlerNoteReactionAdd reacts to a note with an emoji and responds with the
+// note's reaction counts. Reacting twice with the same emoji has no effect.
+func (cfg *apiConfig) handlerNoteReactionAdd(w http.ResponseWriter, r *http.Request, user database.User) error {
+	return cfg.updateNoteReaction(w, r, user, true)
+}
+
+// handlerNoteReactionRemove takes back a reaction to a note.
+func (cfg *apiConfig) handlerNoteReactionRemove(w http.ResponseWriter, r *http.Request, user database.User) error {
+	return cfg.updateNoteReaction(w, r, user, false)
+}
+
+func (cfg *apiConfig) updateNoteReaction(w http.ResponseWriter, r *http.Request, user database.User, add bool) error {
+	emoji, err := cfg.reactionEmojiParam(r)
+	if err != nil {
+		return err
+	}
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+
+	if add {
+		err = cfg.DB.AddNoteReaction(r.Context(), database.AddNoteReactionParams{
+			NoteID:    note.ID,
+			UserID:    user.ID,
+			Emoji:     emoji,
+			CreatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		})
+	} else {
+		err = cfg.DB.RemoveNoteReaction(r.Context(), database.RemoveNoteReactionParams{
+			NoteID: note.ID,
+			UserID: user.ID,
+			Emoji:  emoji,
+		})
+	}
+	if err != nil {
+		return errInternal("Couldn't update reaction", err)
+	}
+	cfg.notesChanged(r.Context(), user.ID)
+
+	rows, err := cfg.DB.GetNoteReactionCounts(r.Context(), note.ID)
+	if err != nil {
+		return errInternal("Couldn't get reactions", err)
+	}
+	counts := make([]ReactionCount, len(rows))
+	for i, row := range rows {
+		counts[i] = ReactionCount{Emoji: row.Emoji, Count: row.Count}
+	}
+	respondWithJSON(w, http.StatusOK, counts)
+	return nil
+}
+
+// handlerCommentReactionAdd reacts to a comment with an emoji and responds
+// with the comment's reaction counts.
+func (cfg *apiConfig) handlerCommentReactionAdd(w http.ResponseWriter, r *http.Request, user database.User) error {
+	return cfg.updateCommentReaction(w, r, user, true)
+}
+
+// handlerCommentReactionRemove takes back a reaction to a comment.
+func (cfg *apiConfig) handlerCommentReactionRemove(w http.ResponseWriter, r *http.Request, user database.User) error {
+	return cfg.updateCommentReaction(w, r, user, false)
+}
+
+func (cfg *apiConfig) updateCommentReaction(w http.ResponseWriter, r *http.Request, user database.User, add bool) error {
+	emoji, err := cfg.reactionEmojiParam(r)
+	if err != nil {
+		return err
+	}
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+	commentID := chi.URLParam(r, "commentID")
+	comment, err := cfg.DB.GetNoteComment(r.Context(), commentID)
+	if err != nil {
+		return errInternal("Couldn't get comment", err)
+	}
+	if comment.NoteID != note.ID {
+		return errNotFound("Couldn't find comment "+commentID, nil)
+	}
+
+	if add {
+		err = cfg.DB.AddCommentReaction(r.Context(), database.AddCommentReactionParams{
+			CommentID: comment.ID,
+			UserID:    user.ID,
+			Emoji:     emoji,
+			CreatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		})
+	} else {
+		err = cfg.DB.RemoveCommentReaction(r.Context(), database.RemoveCommentReactionParams{
+			CommentID: comment.ID,
+			UserID:    user.ID,
+			Emoji:     emoji,
+		})
+	}
+	if err != nil {
+		return errInternal("Couldn't update reaction", err)
+	}
+
+	rows, err := cfg.DB.GetCommentReactionCounts(r.Context(), comment.ID)
+	if err != nil {
+		return errInternal("Couldn't get reactions", err)
+	}
+	counts := make([]ReactionCount, len(rows))
+	for i, row := range rows {
+		counts[i] = ReactionCount{Emoji: row.Emoji, Count: row.Count}
+	}
+	respondWithJSON(w, http.StatusOK, counts)
+	return nil
+}
+
+// noteReactionsByNote returns the reaction counts on all of a user's notes, keyed by note ID.
+func (cfg *apiConfig) noteReactionsByNote(ctx context.Context, userID string) (map[string][]ReactionCount, error) {
+	rows, err := cfg.DB.GetNoteReactionCountsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string][]ReactionCount)
+	for _, row := range rows {
+		counts[row.NoteID] = append(counts[row.NoteID], ReactionCount{Emoji: row.Emoji, Count: row.Count})
+	}
+	return counts, nil
+}
+
+// commentReactionsByComment returns the reaction counts on a note's comments, keyed by comment ID.
+func (cfg *apiConfig) commentReactionsByComment(ctx context.Context, noteID string) (map[string][]ReactionCount, error) {
+	rows, err := cfg.DB.GetCommentReactionCountsForNote(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string][]ReactionCount)
+	for _, row := range rows {
+		counts[row.CommentID] = append(counts[row.CommentID], ReactionCount{Emoji: row.Emoji, Count: row.Count})
+	}
+	return counts, nil
+}
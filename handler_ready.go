@@ -2,6 +2,12 @@ package main
 
 import "net/http"
 
-func handlerReadiness(w http.ResponseWriter, r *http.Request) {
+// handlerReadiness reports 503 once shutdown has started, so load balancers
+// stop sending traffic while in-flight requests drain.
+func (cfg *apiConfig) handlerReadiness(w http.ResponseWriter, r *http.Request) {
+	if cfg.draining.Load() {
+		respondWithJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "draining"})
+		return
+	}
 	respondWithJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
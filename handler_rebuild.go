@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerRebuildStart recomputes derived data (note titles, the link graph,
+// title trigrams and embeddings) from the notes table, e.g. after a schema
+// change or a corrupted index. The body may list the kinds to rebuild as
+// {"derived": [...]}; it defaults to everything this instance maintains. A
+// rebuild that stopped on an error is resumed instead, ignoring the body.
+func (cfg *apiConfig) handlerRebuildStart(w http.ResponseWriter, r *http.Request) error {
+	type parameters struct {
+		Derived []string `json:"derived"`
+	}
+	params := parameters{}
+	err := json.NewDecoder(r.Body).Decode(&params)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return errValidation("Couldn't decode parameters", err)
+	}
+
+	rebuild, err := cfg.DB.GetUnfinishedIndexRebuild(r.Context())
+	if errors.Is(err, database.ErrNotFound) {
+		available := cfg.availableDerived()
+		if len(params.Derived) == 0 {
+			params.Derived = available
+		}
+		for _, d := range params.Derived {
+			if !slices.Contains(available, d) {
+				return errValidation("Can't rebuild "+d+"; available: "+strings.Join(available, ", "), nil)
+			}
+		}
+		// Keep the order of availableDerived whatever order was asked for.
+		derived := slices.DeleteFunc(slices.Clone(available), func(d string) bool {
+			return !slices.Contains(params.Derived, d)
+		})
+		rebuild = database.IndexRebuild{
+			ID:      uuid.New().String(),
+			Derived: strings.Join(derived, ","),
+		}
+	} else if err != nil {
+		return errInternal("Couldn't get unfinished rebuild", err)
+	}
+
+	if err := cfg.startIndexRebuild(r.Context(), rebuild); err != nil {
+		return err
+	}
+	return cfg.respondWithRebuild(w, r, http.StatusAccepted)
+}
+
+// handlerRebuildGet reports the progress of the latest rebuild.
+func (cfg *apiConfig) handlerRebuildGet(w http.ResponseWriter, r *http.Request) error {
+	return cfg.respondWithRebuild(w, r, http.StatusOK)
+}
+
+func (cfg *apiConfig) respondWithRebuild(w http.ResponseWriter, r *http.Request, code int) error {
+	rebuild, err := cfg.DB.GetLatestIndexRebuild(r.Context())
+	if err != nil {
+		return errInternal("Couldn't get rebuild", err)
+	}
+	rebuildResp, err := databaseIndexRebuildToIndexRebuild(rebuild)
+	if err != nil {
+		return errInternal("Couldn't convert rebuild", err)
+	}
+	rebuildResp.Running = cfg.rebuilding.Load() && rebuildResp.FinishedAt == nil
+	respondWithJSON(w, code, rebuildResp)
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/embeddings"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/keywords"
+	"github.com/go-chi/chi/v5"
+)
+
+// Limits for the number of related notes returned.
+const (
+	defaultRelatedNotes = 5
+	maxRelatedNotes     = 20
+)
+
+// handlerNoteRelated returns the user's notes most similar to the given one.
+// Similarity uses embeddings when the note has one for the configured model,
+// and keyword overlap otherwise.
+func (cfg *apiConfig) handlerNoteRelated(w http.ResponseWriter, r *http.Request, user database.User) error {
+	limit, err := queryLimit(r, defaultRelatedNotes, maxRelatedNotes)
+	if err != nil {
+		return err
+	}
+
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+
+	scored, ok, err := cfg.relatedByEmbedding(r, user, note)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		scored, err = cfg.relatedByKeywords(r, user, note)
+		if err != nil {
+			return err
+		}
+	}
+
+	results := []ScoredNote{}
+	for _, s := range scored {
+		if s.Score <= 0 {
+			continue
+		}
+		results = append(results, s)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	respondWithJSON(w, http.StatusOK, results)
+	return nil
+}
+
+// relatedByEmbedding scores the user's other embedded notes against note.
+// It reports false when embeddings are off or note hasn't been embedded.
+func (cfg *apiConfig) relatedByEmbedding(r *http.Request, user database.User, note database.Note) ([]ScoredNote, bool, error) {
+	if cfg.Embedder == nil {
+		return nil, false, nil
+	}
+	rows, err := cfg.DB.GetNoteEmbeddingsForUser(r.Context(), database.GetNoteEmbeddingsForUserParams{
+		UserID: user.ID,
+		Model:  cfg.Embedder.Model(),
+	})
+	if err != nil {
+		return nil, false, errInternal("Couldn't get note embeddings", err)
+	}
+
+	vectors := make([][]float32, len(rows))
+	var target []float32
+	for i, row := range rows {
+		vectors[i], err = embeddings.Decode(row.Embedding)
+		if err != nil {
+			return nil, false, errInternal("Couldn't decode note embedding", err)
+		}
+		if row.Note.ID == note.ID {
+			target = vectors[i]
+		}
+	}
+	if target == nil {
+		return nil, false, nil
+	}
+
+	var scored []ScoredNote
+	for i, row := range rows {
+		if row.Note.ID == note.ID {
+			continue
+		}
+		n, err := databaseNoteToNote(row.Note)
+		if err != nil {
+			return nil, false, errInternal("Couldn't convert note", err)
+		}
+		scored = append(scored, ScoredNote{Note: n, Score: embeddings.Cosine(target, vectors[i])})
+	}
+	return scored, true, nil
+}
+
+// relatedByKeywords scores the user's other notes by the overlap of their
+// TF-IDF weighted terms with note's.
+func (cfg *apiConfig) relatedByKeywords(r *http.Request, user database.User, note database.Note) ([]ScoredNote, error) {
+	notes, err := cfg.DB.GetNotesForUser(r.Context(), user.ID)
+	if err != nil {
+		return nil, errInternal("Couldn't get notes for user", err)
+	}
+	bodies := make([]string, len(notes))
+	for i, n := range notes {
+		bodies[i] = n.Note
+	}
+	corpus := keywords.NewCorpus(bodies)
+	target := corpus.Weights(note.Note)
+
+	var scored []ScoredNote
+	for _, other := range notes {
+		if other.ID == note.ID {
+			continue
+		}
+		n, err := databaseNoteToNote(other)
+		if err != nil {
+			return nil, errInternal("Couldn't convert note", err)
+		}
+		scored = append(scored, ScoredNote{Note: n, Score: keywords.Similarity(target, corpus.Weights(other.Note))})
+	}
+	return scored, nil
+}
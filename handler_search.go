@@ -0,0 +1,198 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/embeddings"
+)
+
+// Limits for the number of search results returned.
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+// handlerNotesSemanticSearch ranks the user's notes by cosine similarity
+// between their embeddings and the embedding of the q parameter. Pages after
+// the first are requested with the cursor from the previous page's meta.
+func (cfg *apiConfig) handlerNotesSemanticSearch(w http.ResponseWriter, r *http.Request, user database.User) error {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		return errValidation("Missing search query q", nil)
+	}
+	limit, err := queryLimit(r, defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		return err
+	}
+	queryHash := searchQueryHash("semantic", cfg.Embedder.Model(), query)
+	cursor, err := querySearchCursor(r, queryHash)
+	if err != nil {
+		return err
+	}
+
+	queryVector, err := cfg.Embedder.Embed(r.Context(), query)
+	if err != nil {
+		return errInternal("Couldn't embed search query", err)
+	}
+
+	rows, err := cfg.DB.GetNoteEmbeddingsForUser(r.Context(), database.GetNoteEmbeddingsForUserParams{
+		UserID: user.ID,
+		Model:  cfg.Embedder.Model(),
+	})
+	if err != nil {
+		return errInternal("Couldn't get note embeddings", err)
+	}
+
+	results := make([]ScoredNote, 0, len(rows))
+	for _, row := range rows {
+		vector, err := embeddings.Decode(row.Embedding)
+		if err != nil {
+			return errInternal("Couldn't decode note embedding", err)
+		}
+		score := embeddings.Cosine(queryVector, vector)
+		if !cursor.rankedAfter([]float64{score}, row.Note.ID) {
+			continue
+		}
+		note, err := databaseNoteToNote(row.Note)
+		if err != nil {
+			return errInternal("Couldn't convert note", err)
+		}
+		results = append(results, ScoredNote{Note: note, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	page := SearchPage{Results: results}
+	if len(results) > limit {
+		last := results[limit-1]
+		page.Results = results[:limit]
+		page.Meta.NextCursor = encodeSearchCursor(searchCursor{Query: queryHash, Rank: []float64{last.Score}, ID: last.ID})
+	}
+	respondWithJSON(w, http.StatusOK, page)
+	return nil
+}
+
+// handlerNotesSearch finds the user's notes containing every word of the q
+// parameter, in their title or body, best matches first. A word ending in *
+// matches as a prefix. Pages after the first are requested with the cursor
+// from the previous page's meta.
+func (cfg *apiConfig) handlerNotesSearch(w http.ResponseWriter, r *http.Request, user database.User) error {
+	query := ftsQuery(r.URL.Query().Get("q"))
+	if query == "" {
+		return errValidation("Missing search query q", nil)
+	}
+	limit, err := queryLimit(r, defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		return err
+	}
+	queryHash := searchQueryHash("fts", query)
+	cursor, err := querySearchCursor(r, queryHash)
+	if err != nil {
+		return err
+	}
+
+	// The index ranks best matches lowest, so the cursor keeps its rank as is.
+	params := database.SearchNotesParams{
+		Query:     query,
+		UserID:    user.ID,
+		AfterRank: -math.MaxFloat64,
+		Limit:     int64(limit + 1),
+	}
+	if cursor != nil {
+		if len(cursor.Rank) != 1 {
+			return errValidation("Invalid cursor", nil)
+		}
+		params.AfterRank = cursor.Rank[0]
+		params.AfterID = cursor.ID
+	}
+	rows, err := cfg.DB.SearchNotes(r.Context(), params)
+	if err != nil {
+		return errInternal("Couldn't search notes", err)
+	}
+	results, err := scoredNotes(rows)
+	if err != nil {
+		return errInternal("Couldn't convert note", err)
+	}
+
+	page := SearchPage{Results: results}
+	if len(rows) > limit {
+		last := rows[limit-1]
+		page.Results = results[:limit]
+		page.Meta.NextCursor = encodeSearchCursor(searchCursor{Query: queryHash, Rank: []float64{last.Rank}, ID: last.Note.ID})
+	}
+	respondWithJSON(w, http.StatusOK, page)
+	return nil
+}
+
+// scoredNotes converts full-text search results, scoring them so that
+// better matches score higher.
+func scoredNotes(rows []database.SearchNotesRow) ([]ScoredNote, error) {
+	results := make([]ScoredNote, 0, len(rows))
+	for _, row := range rows {
+		note, err := databaseNoteToNote(row.Note)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, ScoredNote{Note: note, Score: -row.Rank})
+	}
+	return results, nil
+}
+
+// ftsQuery turns a search as typed into an FTS5 query matching notes with
+// every word. Each word is quoted so punctuation and FTS5 operators in it
+// are taken literally; a trailing * is kept outside the quotes to make it a
+// prefix match.
+func ftsQuery(q string) string {
+	var terms []string
+	for _, word := range strings.Fields(q) {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.TrimRight(word, "*")
+		if word == "" {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
+
+// queryLimit reads the optional limit parameter, defaulting to def and capped at maxLimit.
+func queryLimit(r *http.Request, def, maxLimit int) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return def, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, errValidation("limit must be a positive integer", err)
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return limit, nil
+}
+
+// queryOffset reads the optional offset query parameter used for pagination.
+func queryOffset(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("offset")
+	if value == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, errValidation("offset must be a non-negative integer", err)
+	}
+	return offset, nil
+}
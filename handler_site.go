@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/go-chi/chi/v5"
+)
+
+// Templates for the public pages served under /site, embedded like the static files.
+//
+//go:embed templates/site/*
+var siteTemplateFiles embed.FS
+
+var siteTemplates = template.Must(template.ParseFS(siteTemplateFiles, "templates/site/*.html"))
+
+// maxSiteTitleLength caps how many characters of a note's first line are used as its title.
+const maxSiteTitleLength = 80
+
+type siteNote struct {
+	Author      string
+	Title       string
+	Path        string
+	IndexPath   string
+	PublishedAt time.Time
+	Blocks      []renderBlock
+	HasMermaid  bool
+	Noindex     bool // Ask search engines not to index the page.
+}
+
+type siteIndex struct {
+	Author string
+	Notes  []siteNote
+
+	// PrevPath and NextPath link to neighbouring pages of a paginated index.
+	PrevPath string
+	NextPath string
+}
+
+func (cfg *apiConfig) handlerNotesPublish(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		NoteIDs []string `json:"note_ids"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+
+	tx, err := cfg.beginTx(r.Context())
+	if err != nil {
+		return errInternal("Couldn't start transaction", err)
+	}
+	defer tx.Rollback()
+
+	// The request describes the complete set of published notes, so anything
+	// not listed is unpublished. Notes that stay published keep their
+	// publication date, and only newly published ones are announced.
+	publishedIDs, err := tx.GetPublishedNoteIDsForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get published notes", err)
+	}
+	listed := make(map[string]bool, len(params.NoteIDs))
+	for _, id := range params.NoteIDs {
+		listed[id] = true
+	}
+	wasPublished := make(map[string]bool, len(publishedIDs))
+	for _, id := range publishedIDs {
+		wasPublished[id] = true
+		if listed[id] {
+			continue
+		}
+		err = tx.UnpublishNote(r.Context(), database.UnpublishNoteParams{ID: id, UserID: user.ID})
+		if err != nil {
+			return errInternal("Couldn't unpublish note", err)
+		}
+	}
+
+	publishedAt := sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true}
+	var newlyPublished []string
+	for _, id := range params.NoteIDs {
+		n, err := tx.PublishNote(r.Context(), database.PublishNoteParams{
+			PublishedAt: publishedAt,
+			ID:          id,
+			UserID:      user.ID,
+		})
+		if err != nil {
+			return errInternal("Couldn't publish note", err)
+		}
+		if n == 0 {
+			return errNotFound("Couldn't find note "+id, nil)
+		}
+		if !wasPublished[id] {
+			// Marked so a note listed twice is only announced once.
+			wasPublished[id] = true
+			newlyPublished = append(newlyPublished, id)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errInternal("Couldn't publish notes", err)
+	}
+	// Unpublishing isn't announced.
+	cfg.notesChanged(r.Context(), user.ID)
+	for _, id := range newlyPublished {
+		cfg.publishEvent(r.Context(), events.TypeNotePublished, user.ID, id)
+	}
+
+	notes, err := cfg.DB.GetPublishedNotesForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get published notes", err)
+	}
+
+	notesResp, err := databasePostsToPosts(notes)
+	if err != nil {
+		return errInternal("Couldn't convert notes", err)
+	}
+
+	respondWithJSONList(w, http.StatusOK, notesResp)
+	return nil
+}
+
+func (cfg *apiConfig) handlerSiteIndex(w http.ResponseWriter, r *http.Request) {
+	user, err := cfg.DB.GetUserByID(r.Context(), chi.URLParam(r, "userID"))
+	if err != nil {
+		siteLookupError(w, r, err)
+		return
+	}
+
+	entries, err := cfg.DB.GetPublishedNoteListForUser(r.Context(), user.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Couldn't get published notes", http.StatusInternalServerError)
+		return
+	}
+
+	page := siteIndex{Author: user.Name}
+	for _, entry := range entries {
+		sn, err := databaseNoteListEntryToSiteNote(user, entry)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Couldn't render notes", http.StatusInternalServerError)
+			return
+		}
+		page.Notes = append(page.Notes, sn)
+	}
+
+	renderSiteTemplate(w, "index.html", page)
+}
+
+func (cfg *apiConfig) handlerSiteNote(w http.ResponseWriter, r *http.Request) {
+	user, err := cfg.DB.GetUserByID(r.Context(), chi.URLParam(r, "userID"))
+	if err != nil {
+		siteLookupError(w, r, err)
+		return
+	}
+
+	note, err := cfg.DB.GetPublishedNote(r.Context(), database.GetPublishedNoteParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		siteLookupError(w, r, err)
+		return
+	}
+
+	page, err := databaseNoteToSiteNote(user, note)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "Couldn't render note", http.StatusInternalServerError)
+		return
+	}
+	cfg.noteViews.record(tenantID(r.Context()), note.ID)
+
+	if page.Noindex {
+		w.Header().Set("X-Robots-Tag", "noindex")
+	}
+	renderSiteTemplate(w, "note.html", page)
+}
+
+// siteLookupError responds with a plain 404 for missing users or notes and a 500 otherwise.
+func siteLookupError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, database.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	log.Println(err)
+	http.Error(w, "Couldn't load page", http.StatusInternalServerError)
+}
+
+// renderSiteTemplate executes the template into a buffer first so a failing
+// template never leaves a half-written page behind.
+func renderSiteTemplate(w http.ResponseWriter, name string, data interface{}) {
+	var buf bytes.Buffer
+	if err := siteTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("Error rendering %s: %v", name, err)
+		http.Error(w, "Couldn't render page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := buf.WriteTo(w); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+func databaseNoteToSiteNote(user database.User, note database.Note) (siteNote, error) {
+	publishedAt, err := time.Parse(time.RFC3339, note.PublishedAt.String)
+	if err != nil {
+		return siteNote{}, err
+	}
+	indexPath := "/site/" + user.ID
+	blocks := renderNoteBlocks(note.Note)
+	return siteNote{
+		Author:      user.Name,
+		Title:       noteTitle(note.Note),
+		Path:        indexPath + "/" + note.ID,
+		IndexPath:   indexPath,
+		PublishedAt: publishedAt,
+		Blocks:      blocks,
+		HasMermaid:  hasBlockKind(blocks, blockMermaid),
+		Noindex:     note.Noindex,
+	}, nil
+}
+
+// databaseNoteListEntryToSiteNote is databaseNoteToSiteNote for index pages,
+// which only show titles and so don't need the note body.
+func databaseNoteListEntryToSiteNote(user database.User, entry database.NoteListEntry) (siteNote, error) {
+	publishedAt, err := time.Parse(time.RFC3339, entry.PublishedAt.String)
+	if err != nil {
+		return siteNote{}, err
+	}
+	indexPath := "/site/" + user.ID
+	return siteNote{
+		Author:      user.Name,
+		Title:       entry.Title,
+		Path:        indexPath + "/" + entry.NoteID,
+		IndexPath:   indexPath,
+		PublishedAt: publishedAt,
+	}, nil
+}
+
+// noteTitle derives a title from the first non-empty line of a note body.
+func noteTitle(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > maxSiteTitleLength {
+			return string(runes[:maxSiteTitleLength]) + "…"
+		}
+		return line
+	}
+	return "Untitled"
+}
+
+// noteParagraphs splits a note body into paragraphs on blank lines.
+func noteParagraphs(body string) []string {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
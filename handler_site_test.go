@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+)
+
+// recordedEvents is a Publisher that keeps the events published to it.
+type recordedEvents struct {
+	events []events.Event
+}
+
+func (p *recordedEvents) Publish(ctx context.Context, e events.Event) error {
+	p.events = append(p.events, e)
+	return nil
+}
+
+func (p *recordedEvents) Close() error { return nil }
+
+func TestNotesPublish(t *testing.T) {
+	api := newTestAPI(t)
+	recorded := &recordedEvents{}
+	api.cfg.Events = recorded
+	_, key := api.newUser(t)
+	a, b, c := api.newNote(t, key, "a"), api.newNote(t, key, "b"), api.newNote(t, key, "c")
+
+	publish := func(ids ...string) map[string]time.Time {
+		t.Helper()
+		recorded.events = nil
+		var notes []Note
+		if code := api.do(t, http.MethodPost, "/v1/publish", key, map[string][]string{"note_ids": ids}, &notes); code != http.StatusOK {
+			t.Fatalf("publishing %v: status %d", ids, code)
+		}
+		published := make(map[string]time.Time)
+		for _, note := range notes {
+			published[note.ID] = *note.PublishedAt
+		}
+		return published
+	}
+	announced := func() []string {
+		var ids []string
+		for _, e := range recorded.events {
+			if e.Type == events.TypeNotePublished {
+				ids = append(ids, e.NoteID)
+			}
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	sorted := func(ids ...string) []string {
+		slices.Sort(ids)
+		return ids
+	}
+
+	first := publish(a.ID, b.ID)
+	if len(first) != 2 {
+		t.Fatalf("published %d notes, want 2", len(first))
+	}
+	if got, want := announced(), sorted(a.ID, b.ID); !slices.Equal(got, want) {
+		t.Errorf("announced %v, want %v", got, want)
+	}
+
+	api.clock.Advance(time.Hour)
+	second := publish(a.ID, c.ID, c.ID)
+	if _, ok := second[b.ID]; ok || len(second) != 2 {
+		t.Errorf("published %v, want a and c", second)
+	}
+	if !second[a.ID].Equal(first[a.ID]) {
+		t.Errorf("a was republished at %v, want its original date %v", second[a.ID], first[a.ID])
+	}
+	if !second[c.ID].After(first[a.ID]) {
+		t.Errorf("c was published at %v, want after %v", second[c.ID], first[a.ID])
+	}
+	if got, want := announced(), []string{c.ID}; !slices.Equal(got, want) {
+		t.Errorf("announced %v, want only c %v", got, want)
+	}
+
+	publish(a.ID, c.ID)
+	if got := announced(); len(got) != 0 {
+		t.Errorf("republishing the same notes announced %v", got)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// sitemapPageSize is how many URLs each page of the sitemap lists; the
+	// protocol allows up to 50,000.
+	sitemapPageSize = 10000
+
+	// sitemapCacheTTL is how long a rendered sitemap page is served before
+	// it's rebuilt, so crawlers fetching it repeatedly cost one query.
+	sitemapCacheTTL = 10 * time.Minute
+)
+
+// handlerNoteNoindexSet sets whether a note's published page asks search
+// engines not to index it. Such notes are also left out of the sitemap.
+func (cfg *apiConfig) handlerNoteNoindexSet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Noindex bool `json:"noindex"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.SetNoteNoindex(r.Context(), database.SetNoteNoindexParams{
+		Noindex:   params.Noindex,
+		UpdatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		ID:        noteID,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't update note", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID, nil)
+	}
+	cfg.notesChanged(r.Context(), user.ID)
+
+	note, err := cfg.DB.GetNote(r.Context(), noteID)
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+	noteResp, err := databaseNoteToNote(note)
+	if err != nil {
+		return errInternal("Couldn't convert note", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, noteResp)
+	return nil
+}
+
+// handlerRobots keeps crawlers out of the API and tools, and points them to
+// the sitemap if there is one. Notes that shouldn't be indexed aren't
+// disallowed here: crawlers have to fetch them to see their noindex.
+func (cfg *apiConfig) handlerRobots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, "User-agent: *\n")
+	for _, path := range []string{"/v1/", "/admin/", "/console", "/dev/", "/mcp"} {
+		fmt.Fprintf(w, "Disallow: %s\n", path)
+	}
+	if cfg.SiteURL != "" && cfg.DB != nil && tenantID(r.Context()) == "" {
+		fmt.Fprintf(w, "\nSitemap: %s/sitemap.xml\n", cfg.SiteURL)
+	}
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name        `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []sitemapMember `xml:"sitemap"`
+}
+
+type sitemapMember struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// handlerSitemapIndex lists the sitemap's pages, one per sitemapPageSize
+// public profiles and notes.
+func (cfg *apiConfig) handlerSitemapIndex(w http.ResponseWriter, r *http.Request) {
+	cfg.serveSitemap(w, r, 0, func() (any, error) {
+		count, err := cfg.DB.CountSitemapURLs(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		pages := max(1, (count+sitemapPageSize-1)/sitemapPageSize)
+		index := sitemapIndex{}
+		for page := int64(1); page <= pages; page++ {
+			index.Sitemaps = append(index.Sitemaps, sitemapMember{
+				Loc: cfg.SiteURL + "/sitemap/" + strconv.FormatInt(page, 10) + ".xml",
+			})
+		}
+		return index, nil
+	})
+}
+
+// handlerSitemapPage lists one page of public profiles and notes.
+func (cfg *apiConfig) handlerSitemapPage(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(chi.URLParam(r, "page"))
+	if err != nil || page < 1 {
+		http.NotFound(w, r)
+		return
+	}
+	cfg.serveSitemap(w, r, page, func() (any, error) {
+		rows, err := cfg.DB.GetSitemapURLs(r.Context(), database.GetSitemapURLsParams{
+			Limit:  sitemapPageSize,
+			Offset: int64(page-1) * sitemapPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 && page > 1 {
+			return nil, nil
+		}
+		set := sitemapURLSet{URLs: make([]sitemapURL, len(rows))}
+		for i, row := range rows {
+			set.URLs[i] = sitemapURL{Loc: cfg.SiteURL + row.Path}
+			if lastMod, err := time.Parse(time.RFC3339, row.Lastmod); err == nil {
+				set.URLs[i].LastMod = lastMod.UTC().Format("2006-01-02")
+			}
+		}
+		return set, nil
+	})
+}
+
+// serveSitemap responds with the cached rendering of page if it's fresh,
+// and otherwise renders what build returns and caches it. A nil document
+// from build means the page doesn't exist. SITE_URL is the default
+// database's site, so tenants have no sitemap.
+func (cfg *apiConfig) serveSitemap(w http.ResponseWriter, r *http.Request, page int, build func() (any, error)) {
+	if tenantID(r.Context()) != "" {
+		http.NotFound(w, r)
+		return
+	}
+	body, ok := cfg.sitemapCache.get(page)
+	if !ok {
+		doc, err := build()
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "Couldn't build sitemap", http.StatusInternalServerError)
+			return
+		}
+		if doc == nil {
+			http.NotFound(w, r)
+			return
+		}
+		var buf bytes.Buffer
+		buf.WriteString(xml.Header)
+		if err := xml.NewEncoder(&buf).Encode(doc); err != nil {
+			log.Println(err)
+			http.Error(w, "Couldn't build sitemap", http.StatusInternalServerError)
+			return
+		}
+		body = buf.Bytes()
+		cfg.sitemapCache.put(page, body)
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// sitemapCache keeps rendered sitemap pages, keyed by page number with 0
+// for the index, for sitemapCacheTTL.
+type sitemapCache struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	entries map[int]sitemapCacheEntry
+}
+
+type sitemapCacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+func newSitemapCache(clock clock.Clock) *sitemapCache {
+	return &sitemapCache{
+		clock:   clock,
+		entries: make(map[int]sitemapCacheEntry),
+	}
+}
+
+func (c *sitemapCache) get(page int) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[page]
+	if !ok || c.clock.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+func (c *sitemapCache) put(page int, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	for p, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, p)
+		}
+	}
+	c.entries[page] = sitemapCacheEntry{body: body, expires: now.Add(sitemapCacheTTL)}
+}
+
+// siteURLFromEnv reads SITE_URL, the public base URL the sitemap's absolute
+// links start with, without a trailing slash.
+func siteURLFromEnv(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+		return "", fmt.Errorf("SITE_URL must start with http:// or https://: %q", value)
+	}
+	return strings.TrimSuffix(value, "/"), nil
+}
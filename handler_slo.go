@@ -0,0 +1,10 @@
+package main
+
+import "net/http"
+
+// handlerSLOGet reports each objective's compliance and burn rate over the
+// rolling window.
+func (cfg *apiConfig) handlerSLOGet(w http.ResponseWriter, r *http.Request) error {
+	respondWithJSON(w, http.StatusOK, cfg.SLO.Report())
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/trigram"
+)
+
+// Limits for type-ahead suggestions.
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 25
+)
+
+// Fuzzy title matching ranks up to fuzzyCandidateLimit notes sharing the
+// most trigrams with the query, and only suggests those containing at least
+// minFuzzyCoverage of the query's trigrams.
+const (
+	fuzzyCandidateLimit = 100
+	minFuzzyCoverage    = 0.5
+)
+
+// handlerNoteTitleSuggest returns notes whose title starts with q, ignoring
+// case, in title order. The prefix match runs on the notes_user_id_title_idx
+// index, so it stays fast however many notes the user has. With
+// ?fuzzy=true, titles resembling q anywhere are suggested instead, best
+// match first, which tolerates typos. Either way, later pages are requested
+// with the cursor from the previous page's meta.
+func (cfg *apiConfig) handlerNoteTitleSuggest(w http.ResponseWriter, r *http.Request, user database.User) error {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		return errValidation("Missing search query q", nil)
+	}
+	limit, err := queryLimit(r, defaultSuggestLimit, maxSuggestLimit)
+	if err != nil {
+		return err
+	}
+	fuzzy := r.URL.Query().Get("fuzzy") == "true"
+	if fuzzy && !cfg.FuzzyTitleSearch {
+		return errValidation("Fuzzy title search isn't enabled", nil)
+	}
+	queryHash := searchQueryHash("title", strconv.FormatBool(fuzzy), query)
+	cursor, err := querySearchCursor(r, queryHash)
+	if err != nil {
+		return err
+	}
+
+	if fuzzy {
+		page, err := cfg.fuzzyTitleSuggestions(r.Context(), user.ID, query, limit, cursor, queryHash)
+		if err != nil {
+			return errInternal("Couldn't get title suggestions", err)
+		}
+		respondWithJSON(w, http.StatusOK, page)
+		return nil
+	}
+
+	params := database.SuggestNoteTitlesParams{
+		UserID: user.ID,
+		Title:  escapeLike(query) + "%",
+		Limit:  int64(limit) + 1,
+	}
+	if cursor != nil {
+		params.AfterTitle, params.AfterID = cursor.Key, cursor.ID
+	}
+	rows, err := cfg.DB.SuggestNoteTitles(r.Context(), params)
+	if err != nil {
+		return errInternal("Couldn't get title suggestions", err)
+	}
+
+	page := SearchPage{}
+	if len(rows) > limit {
+		last := rows[limit-1]
+		rows = rows[:limit]
+		page.Meta.NextCursor = encodeSearchCursor(searchCursor{Query: queryHash, Key: last.Title, ID: last.ID})
+	}
+	suggestions := make([]TitleSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = TitleSuggestion{ID: row.ID, Title: row.Title}
+	}
+	page.Results = suggestions
+	respondWithJSON(w, http.StatusOK, page)
+	return nil
+}
+
+// fuzzyTitleSuggestions ranks userID's notes by the share of query's
+// trigrams their title contains, breaking ties by overall similarity so
+// shorter, closer titles come first, and returns the page after cursor.
+func (cfg *apiConfig) fuzzyTitleSuggestions(ctx context.Context, userID, query string, limit int, cursor *searchCursor, queryHash string) (SearchPage, error) {
+	queryTrigrams := trigram.Trigrams(query)
+	if len(queryTrigrams) == 0 {
+		return SearchPage{Results: []TitleSuggestion{}}, nil
+	}
+	rows, err := cfg.DB.GetFuzzyNoteTitleMatches(ctx, database.GetFuzzyNoteTitleMatchesParams{
+		UserID:   userID,
+		Trigrams: queryTrigrams,
+		Limit:    fuzzyCandidateLimit,
+	})
+	if err != nil {
+		return SearchPage{}, err
+	}
+
+	type scored struct {
+		suggestion TitleSuggestion
+		rank       []float64 // Coverage, then similarity.
+	}
+	candidates := make([]scored, 0, len(rows))
+	for _, row := range rows {
+		coverage := float64(row.Shared) / float64(len(queryTrigrams))
+		if coverage < minFuzzyCoverage {
+			continue
+		}
+		rank := []float64{coverage, trigram.Similarity(queryTrigrams, trigram.Trigrams(row.Title))}
+		if !cursor.rankedAfter(rank, row.ID) {
+			continue
+		}
+		candidates = append(candidates, scored{
+			suggestion: TitleSuggestion{ID: row.ID, Title: row.Title},
+			rank:       rank,
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		for k := range a.rank {
+			if a.rank[k] != b.rank[k] {
+				return a.rank[k] > b.rank[k]
+			}
+		}
+		return a.suggestion.ID < b.suggestion.ID
+	})
+
+	page := SearchPage{}
+	if len(candidates) > limit {
+		last := candidates[limit-1]
+		candidates = candidates[:limit]
+		page.Meta.NextCursor = encodeSearchCursor(searchCursor{Query: queryHash, Rank: last.rank, ID: last.suggestion.ID})
+	}
+	suggestions := make([]TitleSuggestion, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.suggestion
+	}
+	page.Results = suggestions
+	return page, nil
+}
+
+// escapeLike escapes LIKE wildcards so s matches literally (with ESCAPE '\').
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/keywords"
+	"github.com/go-chi/chi/v5"
+)
+
+// Limits for the number of suggested tags returned.
+const (
+	defaultSuggestedTags = 5
+	maxSuggestedTags     = 20
+)
+
+// handlerNoteSuggestedTags suggests tags for a note: the terms that are
+// frequent in it but rare across the user's other notes.
+func (cfg *apiConfig) handlerNoteSuggestedTags(w http.ResponseWriter, r *http.Request, user database.User) error {
+	limit, err := queryLimit(r, defaultSuggestedTags, maxSuggestedTags)
+	if err != nil {
+		return err
+	}
+
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+
+	notes, err := cfg.DB.GetNotesForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get notes for user", err)
+	}
+	bodies := make([]string, len(notes))
+	for i, n := range notes {
+		bodies[i] = n.Note
+	}
+
+	suggestions := []TagSuggestion{}
+	for _, k := range keywords.NewCorpus(bodies).Keywords(note.Note, limit) {
+		suggestions = append(suggestions, TagSuggestion{Tag: k.Term, Score: k.Score})
+	}
+	respondWithJSON(w, http.StatusOK, suggestions)
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/llm"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlerNoteSummarize returns an LLM-written summary of a note. Summaries
+// are cached per note and only regenerated when the note's content or the
+// configured model changes; only regenerations count against the rate limit.
+func (cfg *apiConfig) handlerNoteSummarize(w http.ResponseWriter, r *http.Request, user database.User) error {
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+
+	contentHash := noteContentHash(note.Note)
+
+	cached, err := cfg.DB.GetNoteSummary(r.Context(), note.ID)
+	if err == nil && cached.ContentHash == contentHash && cached.Model == cfg.LLM.Model() {
+		return respondWithNoteSummary(w, cached, true)
+	}
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return errInternal("Couldn't get note summary", err)
+	}
+
+	if !cfg.summarizeLimiter.Allow(user.ID) {
+		return errTooManyRequests("Summary rate limit exceeded, try again later", nil)
+	}
+
+	text, err := llm.Summarize(r.Context(), cfg.LLM, note.Note)
+	if err != nil {
+		return errInternal("Couldn't summarize note", err)
+	}
+
+	summary := database.NoteSummary{
+		NoteID:      note.ID,
+		ContentHash: contentHash,
+		Model:       cfg.LLM.Model(),
+		Summary:     text,
+		CreatedAt:   cfg.Clock.Now().UTC().Format(time.RFC3339),
+	}
+	err = cfg.DB.UpsertNoteSummary(r.Context(), database.UpsertNoteSummaryParams(summary))
+	if err != nil {
+		return errInternal("Couldn't save note summary", err)
+	}
+	return respondWithNoteSummary(w, summary, false)
+}
+
+func respondWithNoteSummary(w http.ResponseWriter, summary database.NoteSummary, cached bool) error {
+	resp, err := databaseNoteSummaryToNoteSummary(summary, cached)
+	if err != nil {
+		return errInternal("Couldn't convert note summary", err)
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// Limits on the tags of a note.
+const (
+	maxTagsPerNote = 20
+	maxTagLength   = 50
+)
+
+// handlerNoteTagsSet replaces the tags of a note with the tags array in
+// the body; an empty array removes them all.
+func (cfg *apiConfig) handlerNoteTagsSet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Tags []string `json:"tags"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	tags, err := normalizeTags(params.Tags)
+	if err != nil {
+		return err
+	}
+
+	noteID := chi.URLParam(r, "noteID")
+	tx, err := cfg.beginTx(r.Context())
+	if err != nil {
+		return errInternal("Couldn't start transaction", err)
+	}
+	defer tx.Rollback()
+	n, err := tx.TouchNote(r.Context(), database.TouchNoteParams{
+		UpdatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		ID:        noteID,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't update note", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID, nil)
+	}
+	if err := setNoteTags(r.Context(), tx.Store, noteID, user.ID, tags); err != nil {
+		return errInternal("Couldn't save tags", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return errInternal("Couldn't save tags", err)
+	}
+	cfg.notesChanged(r.Context(), user.ID)
+
+	note, err := cfg.DB.GetNote(r.Context(), noteID)
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+	noteResp, err := databaseNoteToNote(note)
+	if err != nil {
+		return errInternal("Couldn't convert note", err)
+	}
+	noteResp.Tags = tags
+
+	respondWithJSON(w, http.StatusOK, noteResp)
+	return nil
+}
+
+// handlerTagsGet lists the user's tags in alphabetical order, with how many
+// notes have each.
+func (cfg *apiConfig) handlerTagsGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	rows, err := cfg.DB.GetTagsForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get tags", err)
+	}
+	tags := make([]TagCount, len(rows))
+	for i, row := range rows {
+		tags[i] = TagCount{Tag: row.Tag, Notes: row.Notes}
+	}
+	respondWithJSONList(w, http.StatusOK, tags)
+	return nil
+}
+
+// normalizeTag lowercases tag and drops a leading #, so "#Work" and "work"
+// are the same tag. Tags are letters, digits, "-", "_" and "/", the last
+// for nesting them like "work/reports".
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if tag == "" {
+		return "", errValidation("Tags can't be empty", nil)
+	}
+	if utf8.RuneCountInString(tag) > maxTagLength {
+		return "", errValidation("Tags can be at most 50 characters", nil)
+	}
+	for _, c := range tag {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !strings.ContainsRune("-_/", c) {
+			return "", errValidation("Tags can only contain letters, digits, -, _ and /: "+tag, nil)
+		}
+	}
+	return tag, nil
+}
+
+// normalizeTags normalizes each of tags, returning them sorted and without
+// duplicates.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := normalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+	if len(normalized) > maxTagsPerNote {
+		return nil, errValidation("A note can have at most 20 tags", nil)
+	}
+	return normalized, nil
+}
+
+// setNoteTags replaces the tags of a note of userID. Run it in a
+// transaction so the note never shows only some of its tags.
+func setNoteTags(ctx context.Context, db *database.Store, noteID, userID string, tags []string) error {
+	if err := db.DeleteNoteTags(ctx, noteID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		err := db.AddNoteTag(ctx, database.AddNoteTagParams{
+			NoteID: noteID,
+			UserID: userID,
+			Tag:    tag,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// noteTagsByNote returns the tags of a user's notes, keyed by note ID.
+func (cfg *apiConfig) noteTagsByNote(ctx context.Context, userID string) (map[string][]string, error) {
+	rows, err := cfg.DB.GetNoteTagsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string][]string)
+	for _, row := range rows {
+		tags[row.NoteID] = append(tags[row.NoteID], row.Tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestNoteTagsSetRefreshesLists(t *testing.T) {
+	api := newTestAPI(t)
+	user, key := api.newUser(t)
+	note := api.newNote(t, key, "Groceries")
+
+	// Read the list and the palette first, so stale results would show.
+	var notes []Note
+	if code := api.do(t, http.MethodGet, "/v1/notes", key, nil, &notes); code != http.StatusOK {
+		t.Fatalf("GET /v1/notes: status %d", code)
+	}
+	if code := api.do(t, http.MethodGet, "/v1/quick", key, nil, nil); code != http.StatusOK {
+		t.Fatalf("GET /v1/quick: status %d", code)
+	}
+	cacheKey := tenantUserKey(context.Background(), user.ID)
+	if _, ok := api.cfg.quickCache.get(cacheKey, ""); !ok {
+		t.Fatal("palette results weren't cached")
+	}
+
+	body := map[string][]string{"tags": {"Home", "#errands"}}
+	if code := api.do(t, http.MethodPut, "/v1/notes/"+note.ID+"/tags", key, body, nil); code != http.StatusOK {
+		t.Fatalf("PUT tags: status %d", code)
+	}
+	if _, ok := api.cfg.quickCache.get(cacheKey, ""); ok {
+		t.Error("palette results are still cached after the tags changed")
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		page   bool
+	}{
+		{name: "array", target: "/v1/notes"},
+		{name: "page", target: "/v1/notes?limit=10", page: true},
+		{name: "tag filter", target: "/v1/notes?tag=home"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Note
+			if tt.page {
+				var page NotePage
+				api.do(t, http.MethodGet, tt.target, key, nil, &page)
+				got = page.Results
+			} else {
+				api.do(t, http.MethodGet, tt.target, key, nil, &got)
+			}
+			if len(got) != 1 || !slices.Equal(got[0].Tags, []string{"errands", "home"}) {
+				t.Errorf("GET %s = %+v, want the note tagged errands and home", tt.target, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/tenancy"
+	"github.com/go-chi/chi/v5"
+)
+
+// tenantConnectTimeout bounds the check of a tenant's new database.
+const tenantConnectTimeout = 10 * time.Second
+
+// handlerAdminTenantsGet lists the tenants and their databases.
+func (cfg *apiConfig) handlerAdminTenantsGet(w http.ResponseWriter, r *http.Request) error {
+	tenants, err := cfg.defaultDB.ListTenants(r.Context())
+	if err != nil {
+		return errInternal("Couldn't list tenants", err)
+	}
+	resp := make([]AdminTenant, len(tenants))
+	for i, tenant := range tenants {
+		resp[i], err = databaseTenantToAdminTenant(tenant)
+		if err != nil {
+			return errInternal("Couldn't convert tenant", err)
+		}
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// handlerAdminTenantPut adds a tenant or moves it to another database, e.g.
+// one restored from a backup, after checking it can be queried. The
+// database must already have the schema applied. Requests for the tenant
+// go to the new database at once on this instance, and within a minute on
+// the others. A tenant with a region must have its database there; it
+// defaults to the one TENANT_REGIONS names for the tenant.
+func (cfg *apiConfig) handlerAdminTenantPut(w http.ResponseWriter, r *http.Request) error {
+	type parameters struct {
+		DatabaseURL string `json:"database_url"`
+		Region      string `json:"region"`
+	}
+	id := chi.URLParam(r, "tenantID")
+	if !tenancy.ValidID(id) {
+		return errValidation("Tenant IDs are lowercase letters, digits and hyphens", nil)
+	}
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if params.Region != "" {
+		regionURL, ok := cfg.TenantRegions.DatabaseURL(params.Region, id)
+		if !ok {
+			return errValidation("Unknown region "+params.Region, nil)
+		}
+		if params.DatabaseURL == "" {
+			params.DatabaseURL = regionURL
+		} else if !cfg.TenantRegions.InRegion(params.Region, params.DatabaseURL) {
+			return errValidation("database_url isn't in region "+params.Region, nil)
+		}
+	}
+	if params.DatabaseURL == "" {
+		return errValidation("Missing database_url", nil)
+	}
+
+	db, _, err := cfg.openDatabase(params.DatabaseURL)
+	if err != nil {
+		return errValidation("Invalid database_url", err)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), tenantConnectTimeout)
+	defer cancel()
+	// Pinging doesn't reach hosted databases, and the query also checks the schema.
+	_, err = db.ExecContext(ctx, "SELECT COUNT(*) FROM notes")
+	db.Close()
+	if err != nil {
+		return errValidation("Couldn't query notes in tenant database; is it migrated?", err)
+	}
+
+	now := cfg.Clock.Now().UTC().Format(time.RFC3339)
+	tenant, err := cfg.defaultDB.UpsertTenant(r.Context(), database.UpsertTenantParams{
+		ID:          id,
+		DatabaseUrl: params.DatabaseURL,
+		Region:      sql.NullString{String: params.Region, Valid: params.Region != ""},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return errInternal("Couldn't save tenant", err)
+	}
+	cfg.Tenants.Forget(id)
+
+	resp, err := databaseTenantToAdminTenant(tenant)
+	if err != nil {
+		return errInternal("Couldn't convert tenant", err)
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+// handlerAdminTenantDelete removes a tenant, so requests for it are turned
+// away. Its database is left as it is.
+func (cfg *apiConfig) handlerAdminTenantDelete(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "tenantID")
+	deleted, err := cfg.defaultDB.DeleteTenant(r.Context(), id)
+	if err != nil {
+		return errInternal("Couldn't delete tenant", err)
+	}
+	if deleted == 0 {
+		return errNotFound("Tenant not found", nil)
+	}
+	cfg.Tenants.Forget(id)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
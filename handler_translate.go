@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// languageCode matches ISO 639 codes with an optional region, e.g. "de" or "pt-BR".
+var languageCode = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z]{2,4})?$`)
+
+// handlerNoteTranslate returns a note translated into the language given by
+// the to parameter. Translations are cached per note and language until the
+// note's content changes.
+func (cfg *apiConfig) handlerNoteTranslate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	language := r.URL.Query().Get("to")
+	if !languageCode.MatchString(language) {
+		return errValidation("to must be a language code like de or pt-BR", nil)
+	}
+
+	note, err := cfg.DB.GetNoteByID(r.Context(), database.GetNoteByIDParams{
+		ID:     chi.URLParam(r, "noteID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't get note", err)
+	}
+	contentHash := noteContentHash(note.Note)
+
+	cached, err := cfg.DB.GetNoteTranslation(r.Context(), database.GetNoteTranslationParams{
+		NoteID:   note.ID,
+		Language: language,
+	})
+	if err == nil && cached.ContentHash == contentHash && cached.Provider == cfg.Translator.Name() {
+		return respondWithNoteTranslation(w, cached, true)
+	}
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return errInternal("Couldn't get note translation", err)
+	}
+
+	text, err := cfg.Translator.Translate(r.Context(), note.Note, language)
+	if err != nil {
+		return errInternal("Couldn't translate note", err)
+	}
+
+	translation := database.NoteTranslation{
+		NoteID:      note.ID,
+		Language:    language,
+		ContentHash: contentHash,
+		Provider:    cfg.Translator.Name(),
+		Translation: text,
+		CreatedAt:   cfg.Clock.Now().UTC().Format(time.RFC3339),
+	}
+	err = cfg.DB.UpsertNoteTranslation(r.Context(), database.UpsertNoteTranslationParams(translation))
+	if err != nil {
+		return errInternal("Couldn't save note translation", err)
+	}
+	return respondWithNoteTranslation(w, translation, false)
+}
+
+func respondWithNoteTranslation(w http.ResponseWriter, translation database.NoteTranslation, cached bool) error {
+	resp, err := databaseNoteTranslationToNoteTranslation(translation, cached)
+	if err != nil {
+		return errInternal("Couldn't convert note translation", err)
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlerNoteDelete moves a note to the trash. It's left out of everything
+// but the trash until it's restored or deleted for good.
+func (cfg *apiConfig) handlerNoteDelete(w http.ResponseWriter, r *http.Request, user database.User) error {
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.TrashNote(r.Context(), database.TrashNoteParams{
+		DeletedAt: sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true},
+		ID:        noteID,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't delete note", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID, nil)
+	}
+	cfg.publishEvent(r.Context(), events.TypeNoteTrashed, user.ID, noteID)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// handlerNotesTrashGet lists the user's trashed notes, most recently
+// deleted first.
+func (cfg *apiConfig) handlerNotesTrashGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	dbNotes, err := cfg.DB.GetTrashedNotesForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get trash", err)
+	}
+	notes := make([]Note, len(dbNotes))
+	for i, note := range dbNotes {
+		notes[i], err = databaseNoteToNote(note)
+		if err != nil {
+			return errInternal("Couldn't convert note", err)
+		}
+	}
+	respondWithJSONList(w, http.StatusOK, notes)
+	return nil
+}
+
+// handlerNoteRestore takes a note out of the trash, as it was when it was
+// deleted, and responds with it.
+func (cfg *apiConfig) handlerNoteRestore(w http.ResponseWriter, r *http.Request, user database.User) error {
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.RestoreNote(r.Context(), database.RestoreNoteParams{ID: noteID, UserID: user.ID})
+	if err != nil {
+		return errInternal("Couldn't restore note", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID+" in the trash", nil)
+	}
+	cfg.publishEvent(r.Context(), events.TypeNoteRestored, user.ID, noteID)
+	return cfg.respondWithNote(w, r, noteID)
+}
+
+// handlerNoteTrashDelete deletes a trashed note for good, with its
+// comments, reactions and everything derived from it. Notes have to be in
+// the trash first, so one can't be lost to a single mistaken request.
+func (cfg *apiConfig) handlerNoteTrashDelete(w http.ResponseWriter, r *http.Request, user database.User) error {
+	noteID := chi.URLParam(r, "noteID")
+	n, err := cfg.DB.DeleteTrashedNote(r.Context(), database.DeleteTrashedNoteParams{ID: noteID, UserID: user.ID})
+	if err != nil {
+		return errInternal("Couldn't delete note", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find note "+noteID+" in the trash", nil)
+	}
+	cfg.publishEvent(r.Context(), events.TypeNoteDeleted, user.ID, noteID)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -1,18 +1,18 @@
 package main
 
 import (
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
 
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/auth"
 	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
 	"github.com/google/uuid"
 )
 
-func (cfg *apiConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request) {
+func (cfg *apiConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request) error {
 	type parameters struct {
 		Name string `json:"name"`
 	}
@@ -20,60 +20,79 @@ func (cfg *apiConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request)
 	params := parameters{}
 	err := decoder.Decode(&params)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
-		return
+		return errValidation("Couldn't decode parameters", err)
 	}
 
-	apiKey, err := generateRandomSHA256Hash()
+	apiKey, err := auth.GenerateAPIKey()
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't gen apikey", err)
-		return
+		return errInternal("Couldn't gen apikey", err)
 	}
 
-	err = cfg.DB.CreateUser(r.Context(), database.CreateUserParams{
-		ID:        uuid.New().String(),
-		CreatedAt: time.Now().UTC().Format(time.RFC3339),
-		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
-		Name:      params.Name,
-		ApiKey:    apiKey,
-	})
+	userID, keyID, err := cfg.createUserWithKey(r.Context(), params.Name, apiKey, cfg.Clock.Now().UTC().Format(time.RFC3339))
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't create user", err)
-		return
+		return errInternal("Couldn't create user", err)
 	}
+	cfg.logSecurityEvent(r, audit.Event{
+		Type:   audit.TypeKeyCreated,
+		Reason: "new user",
+		Actor:  audit.Actor{Type: audit.ActorUser, UserID: userID},
+		Target: &audit.Target{UserID: userID, KeyID: keyID},
+	})
 
 	user, err := cfg.DB.GetUser(r.Context(), apiKey)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
-		return
+		return errInternal("Couldn't get user", err)
 	}
 
 	userResp, err := databaseUserToUser(user)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't convert user", err)
-		return
+		return errInternal("Couldn't convert user", err)
 	}
 	respondWithJSON(w, http.StatusCreated, userResp)
+	return nil
 }
 
-func generateRandomSHA256Hash() (string, error) {
-	randomBytes := make([]byte, 32)
-	_, err := rand.Read(randomBytes)
+// createUserWithKey inserts a user together with its first API key and
+// returns their IDs.
+func (cfg *apiConfig) createUserWithKey(ctx context.Context, name, apiKey, now string) (userID, keyID string, err error) {
+	tx, err := cfg.beginTx(ctx)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	hash := sha256.Sum256(randomBytes)
-	hashString := hex.EncodeToString(hash[:])
-	return hashString, nil
-}
+	defer tx.Rollback()
 
-func (cfg *apiConfig) handlerUsersGet(w http.ResponseWriter, r *http.Request, user database.User) {
+	userID = uuid.New().String()
+	err = tx.CreateUser(ctx, database.CreateUserParams{
+		ID:        userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      name,
+		ApiKey:    apiKey,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	keyID = uuid.New().String()
+	err = tx.CreateAPIKey(ctx, database.CreateAPIKeyParams{
+		ID:        keyID,
+		CreatedAt: now,
+		UserID:    userID,
+		ApiKey:    apiKey,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return userID, keyID, tx.Commit()
+}
 
+func (cfg *apiConfig) handlerUsersGet(w http.ResponseWriter, r *http.Request, user database.User) error {
 	userResp, err := databaseUserToUser(user)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't convert user", err)
-		return
+		return errInternal("Couldn't convert user", err)
 	}
 
 	respondWithJSON(w, http.StatusOK, userResp)
+	return nil
 }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 30
+)
+
+// reservedUsernames can't be claimed because they would be confusing on a
+// public profile or collide with paths the service uses itself.
+var reservedUsernames = map[string]bool{
+	"about":    true,
+	"admin":    true,
+	"api":      true,
+	"help":     true,
+	"login":    true,
+	"logout":   true,
+	"mcp":      true,
+	"me":       true,
+	"notely":   true,
+	"root":     true,
+	"settings": true,
+	"signup":   true,
+	"site":     true,
+	"static":   true,
+	"support":  true,
+	"system":   true,
+	"u":        true,
+	"v1":       true,
+	"www":      true,
+}
+
+// PublicUser is what other users can learn about someone from their username.
+type PublicUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// normalizeUsername is the canonical form usernames are stored and looked up
+// in: lowercase and NFC, so "Zoë" and "zoë" (in either encoding) are one name.
+func normalizeUsername(username string) string {
+	return norm.NFC.String(strings.ToLower(strings.TrimSpace(username)))
+}
+
+// validateUsername checks a normalized username's length, characters and the
+// reserved list. Usernames are letters, digits, '_' and '-'.
+func validateUsername(username string) error {
+	if n := utf8.RuneCountInString(username); n < minUsernameLength || n > maxUsernameLength {
+		return errValidation("username must be between "+strconv.Itoa(minUsernameLength)+" and "+strconv.Itoa(maxUsernameLength)+" characters", nil)
+	}
+	for _, r := range username {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return errValidation("username may only contain letters, digits, '_' and '-'", nil)
+		}
+	}
+	if reservedUsernames[username] {
+		return errValidation("username "+username+" is reserved", nil)
+	}
+	return nil
+}
+
+// handlerUsernameUpdate claims or changes the user's username. Once set, it
+// can only be changed again after UsernameCooldown so links to a profile
+// don't start pointing at someone else too quickly.
+func (cfg *apiConfig) handlerUsernameUpdate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Username string `json:"username"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+
+	username := normalizeUsername(params.Username)
+	if err := validateUsername(username); err != nil {
+		return err
+	}
+	if user.Username.Valid && user.Username.String == username {
+		return cfg.respondWithUser(w, r, user.ID)
+	}
+
+	now := cfg.Clock.Now().UTC()
+	if user.UsernameChangedAt.Valid {
+		changedAt, err := time.Parse(time.RFC3339, user.UsernameChangedAt.String)
+		if err != nil {
+			return errInternal("Couldn't parse username change time", err)
+		}
+		if next := changedAt.Add(cfg.UsernameCooldown); now.Before(next) {
+			return errTooManyRequests("Username can't be changed again until "+next.Format(time.RFC3339), nil)
+		}
+	}
+
+	err = cfg.DB.UpdateUsername(r.Context(), database.UpdateUsernameParams{
+		Username:          sql.NullString{String: username, Valid: true},
+		UsernameChangedAt: sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+		UpdatedAt:         now.Format(time.RFC3339),
+		ID:                user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't update username", err)
+	}
+	cfg.authCache.forget(r.Context(), user.ID)
+
+	return cfg.respondWithUser(w, r, user.ID)
+}
+
+// handlerUsersByUsername looks up another user by username, e.g. to share
+// notes with them.
+func (cfg *apiConfig) handlerUsersByUsername(w http.ResponseWriter, r *http.Request, _ database.User) error {
+	username := normalizeUsername(chi.URLParam(r, "username"))
+	user, err := cfg.DB.GetUserByUsername(r.Context(), sql.NullString{String: username, Valid: true})
+	if err != nil {
+		return errNotFound("Couldn't find user "+username, err)
+	}
+
+	respondWithJSON(w, http.StatusOK, PublicUser{
+		ID:       user.ID,
+		Username: user.Username.String,
+		Name:     user.Name,
+	})
+	return nil
+}
+
+// respondWithUser reloads the user after an update and responds with it.
+func (cfg *apiConfig) respondWithUser(w http.ResponseWriter, r *http.Request, userID string) error {
+	user, err := cfg.DB.GetUserByID(r.Context(), userID)
+	if err != nil {
+		return errInternal("Couldn't get user", err)
+	}
+	userResp, err := databaseUserToUser(user)
+	if err != nil {
+		return errInternal("Couldn't convert user", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, userResp)
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/push"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/safefetch"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxWebPushEndpointLength is well above the endpoints browsers hand out.
+const maxWebPushEndpointLength = 2048
+
+// newWebPush sets up Web Push with the VAPID key from privateKey, or if
+// that's empty the one stored in the database, generating it on first use.
+func (cfg *apiConfig) newWebPush(ctx context.Context, privateKey, subject string) (*push.WebPush, error) {
+	if privateKey == "" {
+		generated, err := push.GenerateVAPIDKey()
+		if err != nil {
+			return nil, err
+		}
+		// Several instances may start at once; whichever inserts first wins
+		// and everyone reads back the same key.
+		err = cfg.DB.CreateVAPIDKey(ctx, database.CreateVAPIDKeyParams{
+			CreatedAt:  cfg.Clock.Now().UTC().Format(time.RFC3339),
+			PrivateKey: generated,
+		})
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err = cfg.DB.GetVAPIDKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Push service endpoints come from the user's browser, so don't let them point at internal services.
+	client := safefetch.New(safefetch.Options{}).HTTPClient()
+	return push.NewWebPush(privateKey, subject, client)
+}
+
+// handlerWebPushPublicKey returns the VAPID public key the web client passes
+// as applicationServerKey when subscribing.
+func (cfg *apiConfig) handlerWebPushPublicKey(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]string{"public_key": cfg.WebPush.PublicKey()})
+}
+
+// handlerWebPushSubscriptionsCreate registers a browser for push
+// notifications. The body is what PushSubscription.toJSON() returns.
+func (cfg *apiConfig) handlerWebPushSubscriptionsCreate(w http.ResponseWriter, r *http.Request, user database.User) error {
+	type parameters struct {
+		Endpoint string `json:"endpoint"`
+		Keys     struct {
+			P256dh string `json:"p256dh"`
+			Auth   string `json:"auth"`
+		} `json:"keys"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if len(params.Endpoint) > maxWebPushEndpointLength {
+		return errValidation("endpoint is too long", nil)
+	}
+	// Keys are base64url; some browsers pad them, which the encoding used for storage doesn't.
+	sub := push.Subscription{
+		Endpoint: params.Endpoint,
+		P256dh:   strings.TrimRight(params.Keys.P256dh, "="),
+		Auth:     strings.TrimRight(params.Keys.Auth, "="),
+	}
+	if err := sub.Validate(); err != nil {
+		return errValidation(err.Error(), nil)
+	}
+
+	err = cfg.DB.UpsertWebPushSubscription(r.Context(), database.UpsertWebPushSubscriptionParams{
+		ID:        uuid.New().String(),
+		CreatedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+		UserID:    user.ID,
+		Endpoint:  sub.Endpoint,
+		P256dh:    sub.P256dh,
+		Auth:      sub.Auth,
+	})
+	if err != nil {
+		return errInternal("Couldn't create subscription", err)
+	}
+
+	subscription, err := cfg.DB.GetWebPushSubscriptionByEndpoint(r.Context(), sub.Endpoint)
+	if err != nil {
+		return errInternal("Couldn't get subscription", err)
+	}
+	subscriptionResp, err := databaseWebPushSubscriptionToWebPushSubscription(subscription)
+	if err != nil {
+		return errInternal("Couldn't convert subscription", err)
+	}
+	respondWithJSON(w, http.StatusCreated, subscriptionResp)
+	return nil
+}
+
+func (cfg *apiConfig) handlerWebPushSubscriptionsGet(w http.ResponseWriter, r *http.Request, user database.User) error {
+	subscriptions, err := cfg.DB.GetWebPushSubscriptionsForUser(r.Context(), user.ID)
+	if err != nil {
+		return errInternal("Couldn't get subscriptions", err)
+	}
+
+	resp := make([]WebPushSubscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		resp[i], err = databaseWebPushSubscriptionToWebPushSubscription(subscription)
+		if err != nil {
+			return errInternal("Couldn't convert subscription", err)
+		}
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return nil
+}
+
+func (cfg *apiConfig) handlerWebPushSubscriptionsDelete(w http.ResponseWriter, r *http.Request, user database.User) error {
+	n, err := cfg.DB.DeleteWebPushSubscription(r.Context(), database.DeleteWebPushSubscriptionParams{
+		ID:     chi.URLParam(r, "subscriptionID"),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return errInternal("Couldn't delete subscription", err)
+	}
+	if n == 0 {
+		return errNotFound("Couldn't find subscription", nil)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// sendWebPush pushes msg to each of userID's subscribed browsers, forgetting
+// subscriptions the push service reports as expired.
+func (cfg *apiConfig) sendWebPush(ctx context.Context, userID string, msg push.Message) {
+	subscriptions, err := cfg.DB.GetWebPushSubscriptionsForUser(ctx, userID)
+	if err != nil {
+		log.Printf("Couldn't get web push subscriptions for %s: %v", userID, err)
+		return
+	}
+	for _, subscription := range subscriptions {
+		err := cfg.WebPush.Send(ctx, push.Subscription{
+			Endpoint: subscription.Endpoint,
+			P256dh:   subscription.P256dh,
+			Auth:     subscription.Auth,
+		}, msg)
+		if errors.Is(err, push.ErrUnregistered) {
+			if err := cfg.DB.DeleteWebPushSubscriptionByEndpoint(ctx, subscription.Endpoint); err != nil {
+				log.Printf("Couldn't remove expired web push subscription %s: %v", subscription.ID, err)
+			}
+			continue
+		}
+		if err != nil {
+			log.Printf("Couldn't push to web push subscription %s: %v", subscription.ID, err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package alerting posts an alert to a webhook when the rate of server
+// errors, failed logins or database errors is abnormal, for deployments
+// without a metrics stack.
+// internal/alerting/alerting.go:
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/httpjson"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/rolling"
+)
+
+const (
+	DefaultWindow       = 5 * time.Minute
+	DefaultErrorRate    = 0.05
+	DefaultAuthFailures = 100
+	DefaultDBErrors     = 10
+
+	// bucketsPerWindow is how finely the window slides.
+	bucketsPerWindow = 10
+
+	// minRequests is how many requests the window needs before its share
+	// of server errors can raise an alert, so one failure at night doesn't.
+	minRequests = 20
+
+	webhookTimeout = 10 * time.Second
+)
+
+// Metrics alerts are raised for.
+const (
+	MetricErrorRate    = "5xx_rate"
+	MetricAuthFailures = "auth_failures"
+	MetricDBErrors     = "db_errors"
+)
+
+// Config holds the thresholds, each over Window. A zero threshold turns its
+// alert off.
+type Config struct {
+	WebhookURL   string
+	Window       time.Duration
+	ErrorRate    float64 // Share of requests answered with a 5xx.
+	AuthFailures int64
+	DBErrors     int64
+}
+
+// Alert is the JSON document posted to the webhook. Text makes it a valid
+// Slack (or Mattermost, or Discord "/slack") incoming webhook message; the
+// other fields are for receivers that want them.
+type Alert struct {
+	Text      string  `json:"text"`
+	Metric    string  `json:"metric"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Window    string  `json:"window"`
+	Host      string  `json:"host,omitempty"`
+}
+
+// counts are the events in a slice of the window.
+type counts struct {
+	requests     int64
+	serverErrors int64
+	authFailures int64
+	dbErrors     int64
+}
+
+// Monitor counts events and raises alerts. A nil *Monitor ignores them, so
+// callers needn't check whether alerting is on.
+type Monitor struct {
+	cfg   Config
+	clock clock.Clock
+	send  func(Alert)
+	host  string
+
+	mu        sync.Mutex
+	window    *rolling.Window[counts]
+	alertedAt map[string]time.Time
+}
+
+// FromEnv builds a Monitor posting to ALERT_WEBHOOK_URL, with thresholds
+// from ALERT_WINDOW, ALERT_5XX_RATE, ALERT_AUTH_FAILURES and
+// ALERT_DB_ERRORS. Alerting is off by default, in which case it returns nil.
+func FromEnv(getenv func(string) string, clock clock.Clock) (*Monitor, error) {
+	cfg := Config{
+		WebhookURL:   getenv("ALERT_WEBHOOK_URL"),
+		Window:       DefaultWindow,
+		ErrorRate:    DefaultErrorRate,
+		AuthFailures: DefaultAuthFailures,
+		DBErrors:     DefaultDBErrors,
+	}
+	if cfg.WebhookURL == "" {
+		return nil, nil
+	}
+	if v := getenv("ALERT_WINDOW"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < bucketsPerWindow*time.Second {
+			return nil, fmt.Errorf("ALERT_WINDOW must be a duration of at least %ds: %q", bucketsPerWindow, v)
+		}
+		cfg.Window = d
+	}
+	if v := getenv("ALERT_5XX_RATE"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("ALERT_5XX_RATE must be between 0 and 1: %q", v)
+		}
+		cfg.ErrorRate = rate
+	}
+	for name, threshold := range map[string]*int64{"ALERT_AUTH_FAILURES": &cfg.AuthFailures, "ALERT_DB_ERRORS": &cfg.DBErrors} {
+		if v := getenv(name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%s must be a non-negative integer: %q", name, v)
+			}
+			*threshold = n
+		}
+	}
+	return New(cfg, clock, webhook(cfg.WebhookURL)), nil
+}
+
+// New returns a Monitor calling send, in its own goroutine, for each alert.
+// A metric alerts at most once per window.
+func New(cfg Config, clock clock.Clock, send func(Alert)) *Monitor {
+	host, _ := os.Hostname()
+	return &Monitor{
+		cfg:       cfg,
+		clock:     clock,
+		send:      send,
+		host:      host,
+		window:    rolling.New[counts](cfg.Window, bucketsPerWindow),
+		alertedAt: make(map[string]time.Time),
+	}
+}
+
+// RecordRequest counts a response with status.
+func (m *Monitor) RecordRequest(status int) {
+	if m == nil {
+		return
+	}
+	m.record(func(c *counts) {
+		c.requests++
+		if status >= 500 {
+			c.serverErrors++
+		}
+	}, status >= 500)
+}
+
+// RecordAuthFailure counts a request rejected for its credentials.
+func (m *Monitor) RecordAuthFailure() {
+	if m == nil {
+		return
+	}
+	m.record(func(c *counts) { c.authFailures++ }, true)
+}
+
+// RecordDBError counts a failed database statement.
+func (m *Monitor) RecordDBError() {
+	if m == nil {
+		return
+	}
+	m.record(func(c *counts) { c.dbErrors++ }, true)
+}
+
+// record applies count to the current bucket and, if it was a bad event,
+// checks the thresholds.
+func (m *Monitor) record(count func(*counts), bad bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	count(m.window.Current(now))
+	if !bad {
+		return
+	}
+
+	var sum counts
+	m.window.Each(now, func(_ int, c counts) {
+		sum.requests += c.requests
+		sum.serverErrors += c.serverErrors
+		sum.authFailures += c.authFailures
+		sum.dbErrors += c.dbErrors
+	})
+	if m.cfg.ErrorRate > 0 && sum.requests >= minRequests {
+		if rate := float64(sum.serverErrors) / float64(sum.requests); rate >= m.cfg.ErrorRate {
+			m.alert(now, MetricErrorRate, rate, m.cfg.ErrorRate,
+				fmt.Sprintf("%.1f%% of %d requests failed with a server error", rate*100, sum.requests))
+		}
+	}
+	if m.cfg.AuthFailures > 0 && sum.authFailures >= m.cfg.AuthFailures {
+		m.alert(now, MetricAuthFailures, float64(sum.authFailures), float64(m.cfg.AuthFailures),
+			fmt.Sprintf("%d requests failed authentication", sum.authFailures))
+	}
+	if m.cfg.DBErrors > 0 && sum.dbErrors >= m.cfg.DBErrors {
+		m.alert(now, MetricDBErrors, float64(sum.dbErrors), float64(m.cfg.DBErrors),
+			fmt.Sprintf("%d database statements failed", sum.dbErrors))
+	}
+}
+
+// alert sends an alert for metric unless one was sent within the window.
+func (m *Monitor) alert(now time.Time, metric string, value, threshold float64, what string) {
+	if last, ok := m.alertedAt[metric]; ok && now.Sub(last) < m.cfg.Window {
+		return
+	}
+	m.alertedAt[metric] = now
+	text := fmt.Sprintf("notely: %s in the last %s", what, m.cfg.Window)
+	if m.host != "" {
+		text += " on " + m.host
+	}
+	go m.send(Alert{
+		Text:      text,
+		Metric:    metric,
+		Value:     value,
+		Threshold: threshold,
+		Window:    m.cfg.Window.String(),
+		Host:      m.host,
+	})
+}
+
+// webhook returns a send function that logs the alert and posts it to url.
+func webhook(url string) func(Alert) {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(alert Alert) {
+		log.Printf("Alert: %s", alert.Text)
+		if err := httpjson.Post(context.Background(), client, url, nil, alert, nil); err != nil {
+			log.Printf("Couldn't send alert: %v", err)
+		}
+	}
+}
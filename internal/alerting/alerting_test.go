@@ -0,0 +1,143 @@
+package alerting
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "disabled by default", env: map[string]string{"ALERT_5XX_RATE": "0.1"}, wantNil: true},
+		{name: "defaults", env: map[string]string{"ALERT_WEBHOOK_URL": "http://hooks.test"}},
+		{name: "bad window", env: map[string]string{"ALERT_WEBHOOK_URL": "http://hooks.test", "ALERT_WINDOW": "1s"}, wantErr: true},
+		{name: "rate above 1", env: map[string]string{"ALERT_WEBHOOK_URL": "http://hooks.test", "ALERT_5XX_RATE": "5"}, wantErr: true},
+		{name: "negative count", env: map[string]string{"ALERT_WEBHOOK_URL": "http://hooks.test", "ALERT_DB_ERRORS": "-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := FromEnv(func(k string) string { return tt.env[k] }, clock.System)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (m == nil) != tt.wantNil {
+				t.Errorf("FromEnv() = %v, wantNil %v", m, tt.wantNil)
+			}
+		})
+	}
+}
+
+func newTestMonitor() (*Monitor, *clock.Fake, chan Alert) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	alerts := make(chan Alert, 10)
+	m := New(Config{Window: 5 * time.Minute, ErrorRate: 0.1, AuthFailures: 3, DBErrors: 2}, fake, func(a Alert) { alerts <- a })
+	return m, fake, alerts
+}
+
+// received returns the alerts sent so far, waiting briefly for senders.
+func received(alerts chan Alert) []Alert {
+	var got []Alert
+	for {
+		select {
+		case a := <-alerts:
+			got = append(got, a)
+		case <-time.After(50 * time.Millisecond):
+			return got
+		}
+	}
+}
+
+func TestMonitorErrorRate(t *testing.T) {
+	m, _, alerts := newTestMonitor()
+	// Too few requests to judge, however many failed.
+	for range 5 {
+		m.RecordRequest(http.StatusInternalServerError)
+	}
+	if got := received(alerts); len(got) != 0 {
+		t.Fatalf("alerted with too few requests: %+v", got)
+	}
+
+	for range 40 {
+		m.RecordRequest(http.StatusOK)
+	}
+	m.RecordRequest(http.StatusBadGateway)
+	got := received(alerts)
+	if len(got) != 1 || got[0].Metric != MetricErrorRate {
+		t.Fatalf("got alerts %+v, want one 5xx_rate alert", got)
+	}
+	// 6 of 46 requests.
+	if got[0].Value < 0.13 || got[0].Value > 0.131 || got[0].Threshold != 0.1 {
+		t.Errorf("alert value %v threshold %v", got[0].Value, got[0].Threshold)
+	}
+}
+
+func TestMonitorCountsAndWindow(t *testing.T) {
+	m, fake, alerts := newTestMonitor()
+	m.RecordAuthFailure()
+	m.RecordAuthFailure()
+	// Failures older than the window no longer count.
+	fake.Advance(6 * time.Minute)
+	m.RecordAuthFailure()
+	m.RecordAuthFailure()
+	if got := received(alerts); len(got) != 0 {
+		t.Fatalf("alerted on failures spread beyond the window: %+v", got)
+	}
+	m.RecordAuthFailure()
+	m.RecordDBError()
+	m.RecordDBError()
+	got := received(alerts)
+	metrics := map[string]bool{}
+	for _, a := range got {
+		metrics[a.Metric] = true
+	}
+	if len(got) != 2 || !metrics[MetricAuthFailures] || !metrics[MetricDBErrors] {
+		t.Fatalf("got alerts %+v, want one auth_failures and one db_errors", got)
+	}
+
+	// Each metric alerts at most once per window.
+	fake.Advance(time.Minute)
+	m.RecordAuthFailure()
+	if got := received(alerts); len(got) != 0 {
+		t.Fatalf("alerted again within the window: %+v", got)
+	}
+	fake.Advance(5 * time.Minute)
+	for range 3 {
+		m.RecordAuthFailure()
+	}
+	if got := received(alerts); len(got) != 1 {
+		t.Fatalf("got %d alerts after the window, want 1", len(got))
+	}
+}
+
+func TestNilMonitor(t *testing.T) {
+	var m *Monitor
+	m.RecordRequest(http.StatusInternalServerError)
+	m.RecordAuthFailure()
+	m.RecordDBError()
+}
+
+func TestWebhook(t *testing.T) {
+	bodies := make(chan map[string]any, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	webhook(srv.URL)(Alert{Text: "notely: 3 database statements failed in the last 5m0s", Metric: MetricDBErrors})
+	body := <-bodies
+	if body["text"] != "notely: 3 database statements failed in the last 5m0s" || body["metric"] != MetricDBErrors {
+		t.Errorf("posted %v", body)
+	}
+}
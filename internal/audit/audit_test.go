@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantNop bool
+		wantErr bool
+	}{
+		{name: "disabled by default", env: map[string]string{}, wantNop: true},
+		{name: "unknown sink", env: map[string]string{"SECURITY_LOG": "stderr"}, wantErr: true},
+		{name: "file without path", env: map[string]string{"SECURITY_LOG": "file"}, wantErr: true},
+		{name: "file", env: map[string]string{"SECURITY_LOG": "file", "SECURITY_LOG_FILE": dir + "/security.jsonl"}},
+		{name: "syslog with bad scheme", env: map[string]string{"SECURITY_LOG": "syslog", "SECURITY_LOG_SYSLOG_ADDR": "http://localhost:514"}, wantErr: true},
+		{name: "syslog over udp", env: map[string]string{"SECURITY_LOG": "syslog", "SECURITY_LOG_SYSLOG_ADDR": "udp://127.0.0.1:514"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := FromEnv(func(k string) string { return tt.env[k] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer l.Close()
+			if _, isNop := l.(Nop); isNop != tt.wantNop {
+				t.Errorf("FromEnv() = %T, wantNop %v", l, tt.wantNop)
+			}
+		})
+	}
+}
+
+func testEvent() Event {
+	return Event{
+		Time:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Schema:  Schema,
+		Type:    TypeAuthFailure,
+		Outcome: OutcomeFailure,
+		Reason:  "unknown api key",
+		Actor:   Actor{Type: ActorAnonymous, KeyFingerprint: KeyFingerprint("ntly_x")},
+		Request: &Request{ID: "r1", Method: "GET", Path: "/v1/notes"},
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := t.TempDir() + "/security.jsonl"
+	l, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := l.Log(testEvent()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrote %d lines, want 2", len(lines))
+	}
+	var got Event
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != TypeAuthFailure || got.Schema != Schema || got.Request.Path != "/v1/notes" || got.Target != nil {
+		t.Errorf("read back %+v", got)
+	}
+}
+
+func TestSyslog(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	l, err := DialSyslog("udp://" + pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if err := l.Log(testEvent()); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 4096)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := string(buf[:n])
+	// authpriv (10) * 8 + warning (4), since the event is a failure.
+	if !strings.HasPrefix(msg, "<84>1 2024-05-01T12:00:00Z ") {
+		t.Errorf("message %q has the wrong header", msg)
+	}
+	if !strings.Contains(msg, " notely ") || !strings.Contains(msg, " auth.failure - {") {
+		t.Errorf("message %q lacks the app name or type", msg)
+	}
+	if !strings.HasSuffix(msg, `"path":"/v1/notes"}}`) {
+		t.Errorf("message %q doesn't end with the event", msg)
+	}
+}
@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// File appends events to a file as JSON lines.
+type File struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenFile opens path for appending, creating it readable only by its owner.
+func OpenFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600) // #nosec G304 -- path comes from operator configuration.
+	if err != nil {
+		return nil, err
+	}
+	return &File{f: f}, nil
+}
+
+func (l *File) Log(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.f.Write(append(data, '\n'))
+	return err
+}
+
+func (l *File) Close() error {
+	return l.f.Close()
+}
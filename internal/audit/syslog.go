@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSyslogAddr is the local syslog daemon's socket.
+	defaultSyslogAddr = "unixgram:///dev/log"
+
+	// facilityAuthpriv is the syslog facility for security messages, which
+	// syslog daemons usually keep apart from the rest.
+	facilityAuthpriv = 10
+
+	severityWarning = 4
+	severityNotice  = 5
+
+	syslogAppName      = "notely"
+	syslogWriteTimeout = 5 * time.Second
+)
+
+// Syslog sends events as RFC 5424 messages with the JSON document as the
+// message, at facility authpriv: failures with severity warning, the rest
+// notice.
+type Syslog struct {
+	network  string
+	address  string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// DialSyslog connects to addr, a URL whose scheme is the network: e.g.
+// "udp://siem.internal:514", "tcp://siem.internal:601" or
+// "unixgram:///dev/log". A dropped connection is redialed on the next Log.
+func DialSyslog(addr string) (*Syslog, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing SECURITY_LOG_SYSLOG_ADDR: %w", err)
+	}
+	s := &Syslog{network: u.Scheme}
+	switch u.Scheme {
+	case "udp", "tcp":
+		s.address = u.Host
+	case "unix", "unixgram":
+		s.address = u.Path
+	default:
+		return nil, fmt.Errorf("SECURITY_LOG_SYSLOG_ADDR must start with udp://, tcp://, unix:// or unixgram://: %q", addr)
+	}
+	s.hostname, err = os.Hostname()
+	if err != nil || s.hostname == "" {
+		s.hostname = "-"
+	}
+	s.conn, err = net.Dial(s.network, s.address)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Syslog) Log(e Event) error {
+	msg, err := s.format(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if s.conn, err = net.Dial(s.network, s.address); err != nil {
+			return err
+		}
+	}
+	if err := s.write(msg); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *Syslog) write(msg []byte) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout)); err != nil {
+		return err
+	}
+	// Stream transports need each message framed; datagrams carry one each.
+	if s.network == "tcp" || s.network == "unix" {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+	_, err := s.conn.Write(msg)
+	return err
+}
+
+// format renders e as "<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG".
+func (s *Syslog) format(e Event) ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	severity := severityNotice
+	if e.Outcome == OutcomeFailure {
+		severity = severityWarning
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %d %s - ",
+		facilityAuthpriv*8+severity,
+		e.Time.UTC().Format(time.RFC3339Nano),
+		s.hostname, syslogAppName, os.Getpid(), e.Type)
+	return append([]byte(header), data...), nil
+}
+
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
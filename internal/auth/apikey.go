@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash/crc32"
+	"math/big"
+	"strings"
+)
+
+// APIKeyPrefix marks keys issued by Notely so secret scanners can recognize them.
+const APIKeyPrefix = "ntly_"
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// apiKeyRandomLength base62 characters carry about 238 bits of entropy.
+	apiKeyRandomLength = 40
+	// apiKeyChecksumLength base62 characters are enough to hold any CRC32.
+	apiKeyChecksumLength = 6
+	apiKeyLength         = len(APIKeyPrefix) + apiKeyRandomLength + apiKeyChecksumLength
+)
+
+// ErrInvalidAPIKey is returned for keys that carry the Notely prefix but whose
+// length, alphabet or checksum is wrong, e.g. because of a typo.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// GenerateAPIKey returns a new random key of the form
+// "ntly_<40 base62 chars><6 base62 chars CRC32 of the random part>".
+func GenerateAPIKey() (string, error) {
+	alphabetSize := big.NewInt(int64(len(base62Alphabet)))
+	var sb strings.Builder
+	sb.Grow(apiKeyLength)
+	sb.WriteString(APIKeyPrefix)
+	for i := 0; i < apiKeyRandomLength; i++ {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(base62Alphabet[n.Int64()])
+	}
+	random := sb.String()[len(APIKeyPrefix):]
+	sb.WriteString(apiKeyChecksum(random))
+	return sb.String(), nil
+}
+
+// ValidateAPIKey checks the format and checksum of a prefixed key without
+// touching the database. Keys without the prefix predate the format and are
+// passed through unchanged.
+func ValidateAPIKey(key string) error {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil
+	}
+	if len(key) != apiKeyLength {
+		return ErrInvalidAPIKey
+	}
+	body := key[len(APIKeyPrefix):]
+	for i := 0; i < len(body); i++ {
+		if strings.IndexByte(base62Alphabet, body[i]) < 0 {
+			return ErrInvalidAPIKey
+		}
+	}
+	random, checksum := body[:apiKeyRandomLength], body[apiKeyRandomLength:]
+	if sum := apiKeyChecksumBytes(random); string(sum[:]) != checksum {
+		return ErrInvalidAPIKey
+	}
+	return nil
+}
+
+// apiKeyChecksum encodes the CRC32 of s as a fixed-width base62 string.
+func apiKeyChecksum(s string) string {
+	out := apiKeyChecksumBytes(s)
+	return string(out[:])
+}
+
+// apiKeyChecksumBytes is apiKeyChecksum without allocating, for validation.
+func apiKeyChecksumBytes(s string) [apiKeyChecksumLength]byte {
+	// Table-driven CRC32 over the string itself: passing []byte(s) to the
+	// crc32 package would copy it to the heap.
+	sum := ^uint32(0)
+	for i := 0; i < len(s); i++ {
+		sum = crc32.IEEETable[byte(sum)^s[i]] ^ sum>>8
+	}
+	sum = ^sum
+	var out [apiKeyChecksumLength]byte
+	for i := apiKeyChecksumLength - 1; i >= 0; i-- {
+		out[i] = base62Alphabet[sum%62]
+		sum /= 62
+	}
+	return out
+}
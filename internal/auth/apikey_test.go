@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		key, err := GenerateAPIKey()
+		if err != nil {
+			t.Fatalf("GenerateAPIKey() error = %v", err)
+		}
+		if !strings.HasPrefix(key, APIKeyPrefix) || len(key) != apiKeyLength {
+			t.Fatalf("GenerateAPIKey() = %q, want %d chars starting with %q", key, apiKeyLength, APIKeyPrefix)
+		}
+		if err := ValidateAPIKey(key); err != nil {
+			t.Fatalf("ValidateAPIKey(%q) error = %v", key, err)
+		}
+		if seen[key] {
+			t.Fatalf("GenerateAPIKey() returned duplicate key %q", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestValidateAPIKey(t *testing.T) {
+	key, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	// Swap one character of the random part for a different base62 character.
+	typo := []byte(key)
+	if typo[len(APIKeyPrefix)] == 'a' {
+		typo[len(APIKeyPrefix)] = 'b'
+	} else {
+		typo[len(APIKeyPrefix)] = 'a'
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{name: "valid key", key: key, wantErr: nil},
+		{name: "legacy hex key", key: strings.Repeat("0f", 32), wantErr: nil},
+		{name: "typo in random part", key: string(typo), wantErr: ErrInvalidAPIKey},
+		{name: "truncated", key: key[:len(key)-1], wantErr: ErrInvalidAPIKey},
+		{name: "invalid alphabet", key: key[:len(key)-1] + "-", wantErr: ErrInvalidAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateAPIKey(tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateAPIKey(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetAPIKeyRejectsBadChecksum(t *testing.T) {
+	key, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	bad := key[:len(key)-1] + string(base62Alphabet[(strings.IndexByte(base62Alphabet, key[len(key)-1])+1)%62])
+	if _, err := GetAPIKey(http.Header{"Authorization": []string{"ApiKey " + bad}}); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("GetAPIKey() error = %v, want %v", err, ErrInvalidAPIKey)
+	}
+}
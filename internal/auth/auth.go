@@ -6,19 +6,33 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"unicode"
 )
 
+// MaxAuthHeaderLength is the longest Authorization header GetAPIKey will parse.
+// Real keys are far shorter; anything longer is rejected before parsing.
+const MaxAuthHeaderLength = 1024
+
 // ErrNoAuthHeaderIncluded is a custom error returned when the Authorization
 // header is missing from the request. This allows callers to handle this
 // specific case distinctly.
 var ErrNoAuthHeaderIncluded = errors.New("no authorization header included")
 
+// ErrMalformedAuthHeader is returned when the Authorization header is present
+// but isn't exactly "ApiKey <key>".
+var ErrMalformedAuthHeader = errors.New("malformed authorization header")
+
 // GetAPIKey extracts the API key from the HTTP request headers.
 //
 // It expects the "Authorization" header in the format "ApiKey <key>".
 // If the header is missing, it returns ErrNoAuthHeaderIncluded.
-// If the format is invalid (e.g., wrong prefix or missing key), it returns
-// a "malformed authorization header" error.
+// If the format is invalid (e.g., wrong prefix, missing or empty key, extra
+// parts, non-printable characters or a header longer than
+// MaxAuthHeaderLength), it returns ErrMalformedAuthHeader.
+// Keys in the "ntly_" format are checksum-verified and a mismatch returns
+// ErrInvalidAPIKey, so typos never reach the database.
+// Surrounding whitespace and runs of spaces or tabs between the prefix and
+// the key are tolerated.
 // On success, it returns the extracted key and nil error.
 //
 // Parameters:
@@ -35,14 +49,36 @@ func GetAPIKey(headers http.Header) (string, error) {
 	if authHeader == "" {
 		return "", ErrNoAuthHeaderIncluded
 	} // Header is missing; return the predefined error.
-	// Split the header by space to separate prefix and key.
-	splitAuth := strings.Split(authHeader, " ")
-	// Check for exactly two parts and correct "ApiKey" prefix.
-	if len(splitAuth) < 2 || splitAuth[0] != "ApiKey" {
+	// Refuse oversized headers before doing any work on them.
+	if len(authHeader) > MaxAuthHeaderLength {
+		return "", ErrMalformedAuthHeader
+	}
+	// Split off the scheme at the first whitespace by slicing rather than
+	// with strings.Fields, so parsing doesn't allocate on every request;
+	// tabs and repeated spaces are still normalized.
+	header := strings.TrimSpace(authHeader)
+	scheme, key := header, ""
+	if i := strings.IndexFunc(header, unicode.IsSpace); i >= 0 {
+		scheme, key = header[:i], strings.TrimLeftFunc(header[i:], unicode.IsSpace)
+	}
+	// Check for the "ApiKey" prefix and a non-empty key.
+	if scheme != "ApiKey" || key == "" {
 		// Invalid format; return a descriptive error.
-		return "", errors.New("malformed authorization header")
+		return "", ErrMalformedAuthHeader
+	}
+	// Keys are printable ASCII; reject control characters and other unicode.
+	// This also rejects extra parts, which would be separated by whitespace.
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c < '!' || c > '~' {
+			return "", ErrMalformedAuthHeader
+		}
+	}
+
+	// Catch typos in prefixed keys before the caller looks them up.
+	if err := ValidateAPIKey(key); err != nil {
+		return "", err
 	}
 
-	// Valid header; return the key (second part).
-	return splitAuth[1], nil
+	// Valid header; return the key.
+	return key, nil
 }
@@ -3,6 +3,7 @@ package auth
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -31,11 +32,9 @@ import (
 //     new instances each time, and errors.Is would fail on pointer inequality.
 //
 // The test covers valid, missing, and malformed header scenarios to ensure
-// robust coverage of boundary conditions. Note that the current function
-// implementation allows empty keys ("ApiKey ") and extra parts after the key
-// (taking only the second split part), without erroring—tests reflect this
-// behavior. If stricter validation is desired, the function could be updated
-// to check len(splitAuth) == 2 && splitAuth[1] != "".
+// robust coverage of boundary conditions. Empty keys ("ApiKey ") and extra
+// parts after the key are rejected as malformed, while extra whitespace
+// around or between the parts is normalized away.
 func TestGetAPIKey(t *testing.T) {
 	// Define the table as a slice of anonymous structs for test cases.
 	tests := []struct {
@@ -63,17 +62,35 @@ func TestGetAPIKey(t *testing.T) {
 			wantErr: errors.New("malformed authorization header"),
 		},
 		{
-			name:    "empty key after prefix (allowed by function)",
+			name:    "empty key after prefix",
 			headers: http.Header{"Authorization": []string{"ApiKey "}},
 			wantKey: "",
-			wantErr: nil,
+			wantErr: errors.New("malformed authorization header"),
 		},
 		{
-			name:    "header with extra parts (takes second part, ignores rest)",
+			name:    "header with extra parts",
 			headers: http.Header{"Authorization": []string{"ApiKey key extra"}},
-			wantKey: "key",
+			wantKey: "",
+			wantErr: errors.New("malformed authorization header"),
+		},
+		{
+			name:    "tabs and repeated spaces are normalized",
+			headers: http.Header{"Authorization": []string{"  ApiKey\t  my-secret-key \t"}},
+			wantKey: "my-secret-key",
 			wantErr: nil,
 		},
+		{
+			name:    "non-ASCII key",
+			headers: http.Header{"Authorization": []string{"ApiKey kéy"}},
+			wantKey: "",
+			wantErr: errors.New("malformed authorization header"),
+		},
+		{
+			name:    "oversized header",
+			headers: http.Header{"Authorization": []string{"ApiKey " + strings.Repeat("a", MaxAuthHeaderLength)}},
+			wantKey: "",
+			wantErr: errors.New("malformed authorization header"),
+		},
 		{
 			name:    "case sensitivity in prefix",
 			headers: http.Header{"Authorization": []string{"apikey mykey"}},
@@ -103,3 +120,62 @@ func TestGetAPIKey(t *testing.T) {
 		})
 	}
 }
+
+// FuzzGetAPIKey feeds arbitrary header values to GetAPIKey. Whatever the input,
+// it must not panic, and any key it accepts must be non-empty printable ASCII
+// that round-trips through a canonical "ApiKey <key>" header.
+func FuzzGetAPIKey(f *testing.F) {
+	seeds := []string{
+		"ApiKey my-secret-key",
+		"ApiKey ",
+		"ApiKey\tkey",
+		"ApiKey   key  ",
+		"Bearer token",
+		"ApiKey key extra",
+		"ApiKey k\u00e9y",
+		"ApiKey\u00a0key",
+		"ApiKey " + strings.Repeat("x", MaxAuthHeaderLength),
+		"",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		key, err := GetAPIKey(http.Header{"Authorization": []string{value}})
+		if err != nil {
+			if key != "" {
+				t.Errorf("GetAPIKey(%q) returned key %q along with error %v", value, key, err)
+			}
+			return
+		}
+		if key == "" || len(key) > MaxAuthHeaderLength {
+			t.Fatalf("GetAPIKey(%q) accepted key of length %d", value, len(key))
+		}
+		for i := 0; i < len(key); i++ {
+			if key[i] < '!' || key[i] > '~' {
+				t.Fatalf("GetAPIKey(%q) accepted non-printable byte %#x", value, key[i])
+			}
+		}
+		again, err := GetAPIKey(http.Header{"Authorization": []string{"ApiKey " + key}})
+		if err != nil || again != key {
+			t.Fatalf("round trip of %q = %q, %v", key, again, err)
+		}
+	})
+}
+
+// BenchmarkGetAPIKey runs on every authenticated request, so it should not
+// allocate, not even for a prefixed key whose checksum has to be verified.
+func BenchmarkGetAPIKey(b *testing.B) {
+	key, err := GenerateAPIKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	headers := http.Header{"Authorization": []string{"ApiKey " + key}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetAPIKey(headers); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
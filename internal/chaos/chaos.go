@@ -0,0 +1,105 @@
+// Package chaos injects faults into HTTP requests, so clients and their
+// retries can be tested against a local server that misbehaves like a real
+// one sometimes does.
+package chaos
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault is a kind of failure the injector can cause.
+type Fault string
+
+const (
+	Latency Fault = "latency" // Delay the request by up to the maximum latency, then serve it.
+	Error   Fault = "error"   // Answer with a 500 or 503 instead of serving the request.
+	Drop    Fault = "drop"    // Close the connection without answering.
+)
+
+var allFaults = []Fault{Latency, Error, Drop}
+
+// defaultMaxLatency is the longest delay injected unless CHAOS_MAX_LATENCY says otherwise.
+const defaultMaxLatency = 2 * time.Second
+
+// Injector causes one of its faults, picked at random, in a share of the
+// requests passing through Middleware.
+type Injector struct {
+	rate       float64
+	faults     []Fault
+	maxLatency time.Duration
+}
+
+// FromEnv builds the injector configured by CHAOS_RATE, the share of
+// requests to inject a fault into, from 0 to 1. Injection is off by
+// default, in which case nil is returned. CHAOS_FAULTS narrows the faults
+// to a comma-separated list of latency, error and drop (default all), and
+// CHAOS_MAX_LATENCY bounds injected delays (default 2s).
+func FromEnv(getenv func(string) string) (*Injector, error) {
+	value := getenv("CHAOS_RATE")
+	if value == "" {
+		return nil, nil
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("CHAOS_RATE must be a number from 0 to 1: %q", value)
+	}
+
+	in := &Injector{rate: rate, faults: allFaults, maxLatency: defaultMaxLatency}
+	if value := getenv("CHAOS_FAULTS"); value != "" {
+		in.faults = nil
+		for _, name := range strings.Split(value, ",") {
+			fault := Fault(strings.TrimSpace(name))
+			switch fault {
+			case Latency, Error, Drop:
+				in.faults = append(in.faults, fault)
+			default:
+				return nil, fmt.Errorf("unknown fault %q in CHAOS_FAULTS; available: latency, error, drop", fault)
+			}
+		}
+	}
+	if value := getenv("CHAOS_MAX_LATENCY"); value != "" {
+		in.maxLatency, err = time.ParseDuration(value)
+		if err != nil || in.maxLatency <= 0 {
+			return nil, fmt.Errorf("CHAOS_MAX_LATENCY must be a positive duration: %q", value)
+		}
+	}
+	return in, nil
+}
+
+// Middleware injects faults into requests at the configured rate.
+func (in *Injector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rand.Float64() >= in.rate {
+			next.ServeHTTP(w, r)
+			return
+		}
+		switch in.faults[rand.IntN(len(in.faults))] {
+		case Latency:
+			timer := time.NewTimer(rand.N(in.maxLatency))
+			select {
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			next.ServeHTTP(w, r)
+		case Error:
+			code := http.StatusInternalServerError
+			if rand.IntN(2) == 0 {
+				code = http.StatusServiceUnavailable
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			fmt.Fprint(w, `{"error":"Injected fault"}`)
+		case Drop:
+			// The server closes the connection of a handler aborted this
+			// way without writing a response.
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
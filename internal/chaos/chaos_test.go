@@ -0,0 +1,92 @@
+package chaos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantNil     bool
+		wantFaults  int
+		wantLatency time.Duration
+		wantErr     bool
+	}{
+		{name: "off by default", env: map[string]string{}, wantNil: true},
+		{name: "all faults", env: map[string]string{"CHAOS_RATE": "0.1"}, wantFaults: 3, wantLatency: defaultMaxLatency},
+		{name: "some faults", env: map[string]string{"CHAOS_RATE": "1", "CHAOS_FAULTS": "error, drop", "CHAOS_MAX_LATENCY": "100ms"}, wantFaults: 2, wantLatency: 100 * time.Millisecond},
+		{name: "rate out of range", env: map[string]string{"CHAOS_RATE": "2"}, wantErr: true},
+		{name: "rate not a number", env: map[string]string{"CHAOS_RATE": "often"}, wantErr: true},
+		{name: "unknown fault", env: map[string]string{"CHAOS_RATE": "0.5", "CHAOS_FAULTS": "fire"}, wantErr: true},
+		{name: "bad latency", env: map[string]string{"CHAOS_RATE": "0.5", "CHAOS_MAX_LATENCY": "0s"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := FromEnv(func(name string) string { return tt.env[name] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (in == nil) != tt.wantNil {
+				t.Fatalf("FromEnv() = %v, wantNil %v", in, tt.wantNil)
+			}
+			if in == nil {
+				return
+			}
+			if len(in.faults) != tt.wantFaults || in.maxLatency != tt.wantLatency {
+				t.Errorf("FromEnv() faults = %v, max latency = %v; want %d faults, %v", in.faults, in.maxLatency, tt.wantFaults, tt.wantLatency)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		rate       float64
+		fault      Fault
+		wantServed bool
+		wantDrop   bool
+	}{
+		{name: "rate 0 leaves requests alone", rate: 0, fault: Error, wantServed: true},
+		{name: "latency still serves", rate: 1, fault: Latency, wantServed: true},
+		{name: "error answers instead", rate: 1, fault: Error},
+		{name: "drop aborts", rate: 1, fault: Drop, wantDrop: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &Injector{rate: tt.rate, faults: []Fault{tt.fault}, maxLatency: time.Millisecond}
+			served := false
+			h := in.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served = true
+			}))
+			rec := httptest.NewRecorder()
+
+			dropped := func() (dropped bool) {
+				defer func() {
+					dropped = recover() == http.ErrAbortHandler
+				}()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+				return false
+			}()
+
+			if dropped != tt.wantDrop {
+				t.Errorf("dropped = %v, want %v", dropped, tt.wantDrop)
+			}
+			if served != tt.wantServed {
+				t.Errorf("served = %v, want %v", served, tt.wantServed)
+			}
+			if !tt.wantServed && !tt.wantDrop && rec.Code < 500 {
+				t.Errorf("status = %d, want a 5xx", rec.Code)
+			}
+		})
+	}
+}
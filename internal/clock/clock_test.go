@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	if got := c.Now(); !got.Equal(start) {
+		t.Fatalf("Now() = %v, want %v", got, start)
+	}
+
+	c.Advance(90 * time.Minute)
+	if got, want := c.Now(), start.Add(90*time.Minute); !got.Equal(want) {
+		t.Errorf("after Advance, Now() = %v, want %v", got, want)
+	}
+
+	c.Set(start)
+	if got := c.Now(); !got.Equal(start) {
+		t.Errorf("after Set, Now() = %v, want %v", got, start)
+	}
+}
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	got := System.Now()
+	if got.Before(before) || got.After(time.Now()) {
+		t.Errorf("System.Now() = %v, not between calls to time.Now", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Package ctxkeys provides typed accessors for request-scoped values.
+// internal/ctxkeys/ctxkeys.go:
+package ctxkeys
+
+import (
+	"context"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// key is unexported so no other package can construct a colliding key.
+type key int
+
+const (
+	userKey key = iota
+	requestIDKey
+	traceIDKey
+	scopesKey
+)
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, user database.User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// User returns the authenticated user stored in ctx, if any.
+func User(ctx context.Context) (database.User, bool) {
+	user, ok := ctx.Value(userKey).(database.User)
+	return user, ok
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "" if none is set.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithTraceID returns a copy of ctx carrying the distributed trace ID.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey, id)
+}
+
+// TraceID returns the trace ID stored in ctx, or "" if none is set.
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey).(string)
+	return id
+}
+
+// WithScopes returns a copy of ctx carrying the scopes granted to the caller.
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// Scopes returns the scopes stored in ctx, or nil if none are set.
+func Scopes(ctx context.Context) []string {
+	scopes, _ := ctx.Value(scopesKey).([]string)
+	return scopes
+}
+
+// HasScope reports whether scope was granted to the caller.
+func HasScope(ctx context.Context, scope string) bool {
+	for _, s := range Scopes(ctx) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package ctxkeys
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// TestAccessors verifies that every getter returns what its setter stored and
+// falls back to the zero value on an empty context.
+func TestAccessors(t *testing.T) {
+	empty := context.Background()
+	if _, ok := User(empty); ok {
+		t.Errorf("User() on empty context reported ok")
+	}
+	if got := RequestID(empty); got != "" {
+		t.Errorf("RequestID() on empty context = %q, want empty", got)
+	}
+	if got := TraceID(empty); got != "" {
+		t.Errorf("TraceID() on empty context = %q, want empty", got)
+	}
+	if got := Scopes(empty); got != nil {
+		t.Errorf("Scopes() on empty context = %v, want nil", got)
+	}
+
+	ctx := WithUser(empty, database.User{ID: "user-1"})
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithTraceID(ctx, "trace-1")
+	ctx = WithScopes(ctx, []string{"notes:read"})
+
+	if user, ok := User(ctx); !ok || user.ID != "user-1" {
+		t.Errorf("User() = %#v, %v, want user-1", user, ok)
+	}
+	if got := RequestID(ctx); got != "req-1" {
+		t.Errorf("RequestID() = %q, want %q", got, "req-1")
+	}
+	if got := TraceID(ctx); got != "trace-1" {
+		t.Errorf("TraceID() = %q, want %q", got, "trace-1")
+	}
+	if !HasScope(ctx, "notes:read") || HasScope(ctx, "notes:write") {
+		t.Errorf("HasScope() mismatch for scopes %v", Scopes(ctx))
+	}
+}
+
+// otherKey mimics a key type another package might define.
+type otherKey int
+
+// TestKeysDoNotCollide makes sure values stored under another package's keys
+// are not returned by the typed getters, even with the same underlying value.
+func TestKeysDoNotCollide(t *testing.T) {
+	ctx := context.WithValue(context.Background(), otherKey(requestIDKey), "spoofed")
+	ctx = WithTraceID(ctx, "trace-1")
+	if got := RequestID(ctx); got != "" {
+		t.Errorf("RequestID() = %q, want empty", got)
+	}
+}
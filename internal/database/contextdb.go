@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// txKey is the context key for the transaction set by ContextWithTx.
+type txKey struct{}
+
+// ContextWithTx returns a copy of ctx whose statements run in tx when
+// executed through a ContextDB.
+func ContextWithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction set by ContextWithTx, if any.
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// dbKey is the context key for the database set by ContextWithDB.
+type dbKey struct{}
+
+// ContextWithDB returns a copy of ctx whose statements run on db when
+// executed through a ContextDB, unless a transaction is set too.
+func ContextWithDB(ctx context.Context, db DBTX) context.Context {
+	return context.WithValue(ctx, dbKey{}, db)
+}
+
+// ContextDB is a DBTX that runs each statement in the transaction carried
+// by its context, if any, then on the database carried by it, and on db
+// otherwise. It lets a caller run everything done for a request in one
+// transaction, e.g. to roll it all back, or on another database, e.g. the
+// one of the tenant the request is for, without the code handling the
+// request knowing. Statements run in such a transaction or database bypass
+// db.
+type ContextDB struct {
+	db DBTX
+}
+
+func NewContextDB(db DBTX) *ContextDB {
+	return &ContextDB{db: db}
+}
+
+func (c *ContextDB) conn(ctx context.Context) DBTX {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	if db, ok := ctx.Value(dbKey{}).(DBTX); ok {
+		return db
+	}
+	return c.db
+}
+
+func (c *ContextDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.conn(ctx).ExecContext(ctx, query, args...)
+}
+
+func (c *ContextDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.conn(ctx).PrepareContext(ctx, query)
+}
+
+func (c *ContextDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.conn(ctx).QueryContext(ctx, query, args...)
+}
+
+func (c *ContextDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.conn(ctx).QueryRowContext(ctx, query, args...)
+}
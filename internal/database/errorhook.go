@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// ErrorHook is a DBTX that calls a function whenever a statement fails,
+// e.g. to count errors for alerting. Failures that are the caller's doing
+// rather than the database's, namely canceled contexts and unique
+// constraint violations, aren't reported. Statements run through WithTx
+// bypass it.
+type ErrorHook struct {
+	db      DBTX
+	onError func(err error)
+}
+
+func NewErrorHook(db DBTX, onError func(err error)) *ErrorHook {
+	return &ErrorHook{db: db, onError: onError}
+}
+
+func (h *ErrorHook) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	result, err := h.db.ExecContext(ctx, query, args...)
+	h.observe(err)
+	return result, err
+}
+
+func (h *ErrorHook) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	stmt, err := h.db.PrepareContext(ctx, query)
+	h.observe(err)
+	return stmt, err
+}
+
+func (h *ErrorHook) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := h.db.QueryContext(ctx, query, args...)
+	h.observe(err)
+	return rows, err
+}
+
+// QueryRowContext reports the error running the query, not sql.ErrNoRows,
+// which only comes up when the row is scanned.
+func (h *ErrorHook) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	row := h.db.QueryRowContext(ctx, query, args...)
+	h.observe(row.Err())
+	return row
+}
+
+func (h *ErrorHook) observe(err error) {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	if strings.Contains(err.Error(), uniqueViolation) {
+		return
+	}
+	h.onError(err)
+}
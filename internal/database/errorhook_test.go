@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorHookObserve(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no error", err: nil, want: false},
+		{name: "canceled", err: fmt.Errorf("query: %w", context.Canceled), want: false},
+		{name: "deadline", err: context.DeadlineExceeded, want: false},
+		{name: "unique violation", err: errors.New("UNIQUE constraint failed: users.name"), want: false},
+		{name: "database error", err: errors.New("database is locked"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			NewErrorHook(nil, func(error) { got = true }).observe(tt.err)
+			if got != tt.want {
+				t.Errorf("observe(%v) reported = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,18 +4,249 @@
 
 package database
 
+import (
+	"database/sql"
+)
+
+type ApiKey struct {
+	ID           string
+	CreatedAt    string
+	UserID       string
+	ApiKey       string
+	ExpiresAt    sql.NullString
+	SupersededBy sql.NullString
+}
+
+type BillingCustomer struct {
+	UserID             string
+	StripeCustomerID   string
+	SubscriptionStatus sql.NullString
+	LastEventAt        int64
+	UpdatedAt          string
+}
+
+type CommentReaction struct {
+	CommentID string
+	UserID    string
+	Emoji     string
+	CreatedAt string
+}
+
+type DeviceToken struct {
+	ID        string
+	CreatedAt string
+	UserID    string
+	Provider  string
+	Token     string
+}
+
+type IndexRebuild struct {
+	ID         string
+	Derived    string
+	LastNoteID string
+	NotesDone  int64
+	StartedAt  string
+	UpdatedAt  string
+	FinishedAt sql.NullString
+}
+
+type LinkPreview struct {
+	Url         string
+	Title       sql.NullString
+	Description sql.NullString
+	ImageUrl    sql.NullString
+	SiteName    sql.NullString
+	FetchedAt   string
+}
+
+type LinkStatus struct {
+	Url        string
+	Alive      bool
+	StatusCode sql.NullInt64
+	Error      sql.NullString
+	CheckedAt  string
+}
+
 type Note struct {
+	ID             string
+	CreatedAt      string
+	UpdatedAt      string
+	Note           string
+	UserID         string
+	PublishedAt    sql.NullString
+	SourceUrl      sql.NullString
+	SourceTitle    sql.NullString
+	ExpiresAt      sql.NullString
+	ExpiryWarnedAt sql.NullString
+	Title          string
+	Noindex        bool
+	NotebookID     sql.NullString
+	DeletedAt      sql.NullString
+	ArchivedAt     sql.NullString
+	Pinned         bool
+}
+
+type NoteComment struct {
 	ID        string
 	CreatedAt string
-	UpdatedAt string
-	Note      string
+	NoteID    string
 	UserID    string
+	Body      string
 }
 
-type User struct {
+type NoteEmbedding struct {
+	NoteID    string
+	Model     string
+	Embedding []byte
+	CreatedAt string
+}
+
+type NoteLink struct {
+	NoteID   string
+	Url      string
+	Position int64
+}
+
+type NoteListEntry struct {
+	NoteID      string
+	UserID      string
+	Title       string
+	CreatedAt   string
+	UpdatedAt   string
+	PublishedAt sql.NullString
+}
+
+type NoteReaction struct {
+	NoteID    string
+	UserID    string
+	Emoji     string
+	CreatedAt string
+}
+
+type NoteSummary struct {
+	NoteID      string
+	ContentHash string
+	Model       string
+	Summary     string
+	CreatedAt   string
+}
+
+type NoteTag struct {
+	NoteID string
+	UserID string
+	Tag    string
+}
+
+type NoteTitleTrigram struct {
+	NoteID  string
+	UserID  string
+	Trigram string
+}
+
+type NoteTranslation struct {
+	NoteID      string
+	Language    string
+	ContentHash string
+	Provider    string
+	Translation string
+	CreatedAt   string
+}
+
+type NoteView struct {
+	NoteID       string
+	Views        int64
+	LastViewedAt string
+}
+
+type Notebook struct {
 	ID        string
 	CreatedAt string
 	UpdatedAt string
+	UserID    string
+	ParentID  sql.NullString
 	Name      string
-	ApiKey    string
+}
+
+type Notification struct {
+	ID        string
+	CreatedAt string
+	UserID    string
+	Type      string
+	NoteID    sql.NullString
+	CommentID sql.NullString
+	ReadAt    sql.NullString
+	DeliverAt sql.NullString
+}
+
+type NotificationPreference struct {
+	UserID    string
+	EventType string
+	Channel   string
+	Enabled   bool
+}
+
+type NotificationQuietHour struct {
+	UserID    string
+	StartTime string
+	EndTime   string
+	Timezone  string
+}
+
+type Plan struct {
+	ID                 string
+	Name               string
+	MaxNotes           sql.NullInt64
+	MaxAttachmentBytes sql.NullInt64
+	MaxCollaborators   sql.NullInt64
+}
+
+type Tenant struct {
+	ID          string
+	DatabaseUrl string
+	CreatedAt   string
+	UpdatedAt   string
+	Region      sql.NullString
+}
+
+type TenantUsage struct {
+	TenantID     string
+	Day          string
+	ApiCalls     int64
+	Notes        sql.NullInt64
+	StorageBytes sql.NullInt64
+}
+
+type User struct {
+	ID                string
+	CreatedAt         string
+	UpdatedAt         string
+	Name              string
+	ApiKey            string
+	Username          sql.NullString
+	ProfilePublic     bool
+	UsernameChangedAt sql.NullString
+}
+
+type UserPlan struct {
+	UserID        string
+	PlanID        string
+	UpdatedAt     string
+	TrialEndsAt   sql.NullString
+	TrialWarnedAt sql.NullString
+	ReadOnlySince sql.NullString
+}
+
+type VapidKey struct {
+	ID         int64
+	CreatedAt  string
+	PrivateKey string
+}
+
+type WebPushSubscription struct {
+	ID        string
+	CreatedAt string
+	UserID    string
+	Endpoint  string
+	P256dh    string
+	Auth      string
 }
@@ -7,19 +7,69 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
+const archiveNote = `-- name: ArchiveNote :execrows
+
+UPDATE notes SET archived_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type ArchiveNoteParams struct {
+	ArchivedAt sql.NullString
+	ID         string
+	UserID     string
+}
+
+func (q *Queries) ArchiveNote(ctx context.Context, arg ArchiveNoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, archiveNote, arg.ArchivedAt, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countNotesForUser = `-- name: CountNotesForUser :one
+
+SELECT COUNT(*) FROM notes WHERE user_id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) CountNotesForUser(ctx context.Context, userID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countNotesForUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUnarchivedNotesForUser = `-- name: CountUnarchivedNotesForUser :one
+
+SELECT COUNT(*) FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+`
+
+func (q *Queries) CountUnarchivedNotesForUser(ctx context.Context, userID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUnarchivedNotesForUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createNote = `-- name: CreateNote :exec
-INSERT INTO notes (id, created_at, updated_at, note, user_id)
-VALUES (?, ?, ?, ?, ?)
+INSERT INTO notes (id, created_at, updated_at, note, user_id, source_url, source_title, expires_at, title, notebook_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateNoteParams struct {
-	ID        string
-	CreatedAt string
-	UpdatedAt string
-	Note      string
-	UserID    string
+	ID          string
+	CreatedAt   string
+	UpdatedAt   string
+	Note        string
+	UserID      string
+	SourceUrl   sql.NullString
+	SourceTitle sql.NullString
+	ExpiresAt   sql.NullString
+	Title       string
+	NotebookID  sql.NullString
 }
 
 func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) error {
@@ -29,13 +79,116 @@ func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) error {
 		arg.UpdatedAt,
 		arg.Note,
 		arg.UserID,
+		arg.SourceUrl,
+		arg.SourceTitle,
+		arg.ExpiresAt,
+		arg.Title,
+		arg.NotebookID,
 	)
 	return err
 }
 
+const deleteExpiredNotes = `-- name: DeleteExpiredNotes :many
+
+DELETE FROM notes WHERE expires_at IS NOT NULL AND expires_at <= ?
+RETURNING id, user_id
+`
+
+type DeleteExpiredNotesRow struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) DeleteExpiredNotes(ctx context.Context, expiresAt sql.NullString) ([]DeleteExpiredNotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, deleteExpiredNotes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeleteExpiredNotesRow
+	for rows.Next() {
+		var i DeleteExpiredNotesRow
+		if err := rows.Scan(&i.ID, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteTrashedNote = `-- name: DeleteTrashedNote :execrows
+
+DELETE FROM notes WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+`
+
+type DeleteTrashedNoteParams struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) DeleteTrashedNote(ctx context.Context, arg DeleteTrashedNoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTrashedNote, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getArchivedNotesForUser = `-- name: GetArchivedNotesForUser :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NOT NULL
+ORDER BY archived_at DESC, id DESC
+`
+
+func (q *Queries) GetArchivedNotesForUser(ctx context.Context, userID string) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getArchivedNotesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNote = `-- name: GetNote :one
 
-SELECT id, created_at, updated_at, note, user_id FROM notes WHERE id = ?
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE id = ?
 `
 
 func (q *Queries) GetNote(ctx context.Context, id string) (Note, error) {
@@ -47,13 +200,154 @@ func (q *Queries) GetNote(ctx context.Context, id string) (Note, error) {
 		&i.UpdatedAt,
 		&i.Note,
 		&i.UserID,
+		&i.PublishedAt,
+		&i.SourceUrl,
+		&i.SourceTitle,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+		&i.Title,
+		&i.Noindex,
+		&i.NotebookID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+		&i.Pinned,
+	)
+	return i, err
+}
+
+const getNoteByID = `-- name: GetNoteByID :one
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type GetNoteByIDParams struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) GetNoteByID(ctx context.Context, arg GetNoteByIDParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, getNoteByID, arg.ID, arg.UserID)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Note,
+		&i.UserID,
+		&i.PublishedAt,
+		&i.SourceUrl,
+		&i.SourceTitle,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+		&i.Title,
+		&i.Noindex,
+		&i.NotebookID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+		&i.Pinned,
 	)
 	return i, err
 }
 
+const getNotesAfterID = `-- name: GetNotesAfterID :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE id > ? ORDER BY id LIMIT ?
+`
+
+type GetNotesAfterIDParams struct {
+	ID    string
+	Limit int64
+}
+
+func (q *Queries) GetNotesAfterID(ctx context.Context, arg GetNotesAfterIDParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesAfterID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesExpiringBefore = `-- name: GetNotesExpiringBefore :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes
+WHERE expires_at IS NOT NULL AND expires_at <= ? AND expiry_warned_at IS NULL AND deleted_at IS NULL
+`
+
+func (q *Queries) GetNotesExpiringBefore(ctx context.Context, expiresAt sql.NullString) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesExpiringBefore, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNotesForUser = `-- name: GetNotesForUser :many
 
-SELECT id, created_at, updated_at, note, user_id FROM notes WHERE user_id = ?
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
 `
 
 func (q *Queries) GetNotesForUser(ctx context.Context, userID string) ([]Note, error) {
@@ -71,6 +365,137 @@ func (q *Queries) GetNotesForUser(ctx context.Context, userID string) ([]Note, e
 			&i.UpdatedAt,
 			&i.Note,
 			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserAfterCreated = `-- name: GetNotesForUserAfterCreated :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (created_at, id) > (?, ?)
+ORDER BY created_at, id
+LIMIT ?
+`
+
+type GetNotesForUserAfterCreatedParams struct {
+	UserID         string
+	AfterCreatedAt string
+	AfterID        string
+	Limit          int64
+}
+
+func (q *Queries) GetNotesForUserAfterCreated(ctx context.Context, arg GetNotesForUserAfterCreatedParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserAfterCreated,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserAfterUpdated = `-- name: GetNotesForUserAfterUpdated :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (updated_at, id) > (?, ?)
+ORDER BY updated_at, id
+LIMIT ?
+`
+
+type GetNotesForUserAfterUpdatedParams struct {
+	UserID         string
+	AfterUpdatedAt string
+	AfterID        string
+	Limit          int64
+}
+
+func (q *Queries) GetNotesForUserAfterUpdated(ctx context.Context, arg GetNotesForUserAfterUpdatedParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserAfterUpdated,
+		arg.UserID,
+		arg.AfterUpdatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
 		); err != nil {
 			return nil, err
 		}
@@ -84,3 +509,1043 @@ func (q *Queries) GetNotesForUser(ctx context.Context, userID string) ([]Note, e
 	}
 	return items, nil
 }
+
+const getNotesForUserBefore = `-- name: GetNotesForUserBefore :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (pinned, created_at, id) < (?, ?, ?)
+ORDER BY pinned DESC, created_at DESC, id DESC
+LIMIT ?
+`
+
+type GetNotesForUserBeforeParams struct {
+	UserID          string
+	BeforePinned    bool
+	BeforeCreatedAt string
+	BeforeID        string
+	Limit           int64
+}
+
+func (q *Queries) GetNotesForUserBefore(ctx context.Context, arg GetNotesForUserBeforeParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserBefore,
+		arg.UserID,
+		arg.BeforePinned,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserBeforeCreated = `-- name: GetNotesForUserBeforeCreated :many
+
+SELECT id, created_at, created_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (created_at, id) < (?, ?)
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+type GetNotesForUserBeforeCreatedParams struct {
+	UserID          string
+	BeforeCreatedAt string
+	BeforeID        string
+	Limit           int64
+}
+
+func (q *Queries) GetNotesForUserBeforeCreated(ctx context.Context, arg GetNotesForUserBeforeCreatedParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserBeforeCreated,
+		arg.UserID,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserBeforeUpdated = `-- name: GetNotesForUserBeforeUpdated :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (updated_at, id) < (?, ?)
+ORDER BY updated_at DESC, id DESC
+LIMIT ?
+`
+
+type GetNotesForUserBeforeUpdatedParams struct {
+	UserID          string
+	BeforeUpdatedAt string
+	BeforeID        string
+	Limit           int64
+}
+
+func (q *Queries) GetNotesForUserBeforeUpdated(ctx context.Context, arg GetNotesForUserBeforeUpdatedParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserBeforeUpdated,
+		arg.UserID,
+		arg.BeforeUpdatedAt,
+		arg.BeforeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserInNotebook = `-- name: GetNotesForUserInNotebook :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND notebook_id = ?
+`
+
+type GetNotesForUserInNotebookParams struct {
+	UserID     string
+	NotebookID sql.NullString
+}
+
+func (q *Queries) GetNotesForUserInNotebook(ctx context.Context, arg GetNotesForUserInNotebookParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserInNotebook, arg.UserID, arg.NotebookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserPage = `-- name: GetNotesForUserPage :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY pinned DESC, created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetNotesForUserPageParams struct {
+	UserID string
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) GetNotesForUserPage(ctx context.Context, arg GetNotesForUserPageParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserPage, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserPageCreatedAsc = `-- name: GetNotesForUserPageCreatedAsc :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY created_at, id
+LIMIT ? OFFSET ?
+`
+
+type GetNotesForUserPageCreatedAscParams struct {
+	UserID string
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) GetNotesForUserPageCreatedAsc(ctx context.Context, arg GetNotesForUserPageCreatedAscParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserPageCreatedAsc, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserPageCreatedDesc = `-- name: GetNotesForUserPageCreatedDesc :many
+
+SELECT id, created_at, created_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetNotesForUserPageCreatedDescParams struct {
+	UserID string
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) GetNotesForUserPageCreatedDesc(ctx context.Context, arg GetNotesForUserPageCreatedDescParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserPageCreatedDesc, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserPageUpdatedAsc = `-- name: GetNotesForUserPageUpdatedAsc :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY updated_at, id
+LIMIT ? OFFSET ?
+`
+
+type GetNotesForUserPageUpdatedAscParams struct {
+	UserID string
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) GetNotesForUserPageUpdatedAsc(ctx context.Context, arg GetNotesForUserPageUpdatedAscParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserPageUpdatedAsc, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesForUserPageUpdatedDesc = `-- name: GetNotesForUserPageUpdatedDesc :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY updated_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetNotesForUserPageUpdatedDescParams struct {
+	UserID string
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) GetNotesForUserPageUpdatedDesc(ctx context.Context, arg GetNotesForUserPageUpdatedDescParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesForUserPageUpdatedDesc, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishedNote = `-- name: GetPublishedNote :one
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE id = ? AND user_id = ? AND published_at IS NOT NULL AND deleted_at IS NULL
+`
+
+type GetPublishedNoteParams struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) GetPublishedNote(ctx context.Context, arg GetPublishedNoteParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, getPublishedNote, arg.ID, arg.UserID)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Note,
+		&i.UserID,
+		&i.PublishedAt,
+		&i.SourceUrl,
+		&i.SourceTitle,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+		&i.Title,
+		&i.Noindex,
+		&i.NotebookID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+		&i.Pinned,
+	)
+	return i, err
+}
+
+const getPublishedNoteIDsForUser = `-- name: GetPublishedNoteIDsForUser :many
+
+SELECT id FROM notes WHERE user_id = ? AND published_at IS NOT NULL
+`
+
+func (q *Queries) GetPublishedNoteIDsForUser(ctx context.Context, userID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getPublishedNoteIDsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishedNotesForUser = `-- name: GetPublishedNotesForUser :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NULL AND published_at IS NOT NULL
+ORDER BY published_at DESC
+`
+
+func (q *Queries) GetPublishedNotesForUser(ctx context.Context, userID string) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getPublishedNotesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedNotesForUser = `-- name: GetTrashedNotesForUser :many
+
+SELECT id, created_at, updated_at, note, user_id, published_at, source_url, source_title, expires_at, expiry_warned_at, title, noindex, notebook_id, deleted_at, archived_at, pinned FROM notes WHERE user_id = ? AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+`
+
+func (q *Queries) GetTrashedNotesForUser(ctx context.Context, userID string) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, getTrashedNotesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Note,
+			&i.UserID,
+			&i.PublishedAt,
+			&i.SourceUrl,
+			&i.SourceTitle,
+			&i.ExpiresAt,
+			&i.ExpiryWarnedAt,
+			&i.Title,
+			&i.Noindex,
+			&i.NotebookID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.Pinned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markNoteExpiryWarned = `-- name: MarkNoteExpiryWarned :exec
+
+UPDATE notes SET expiry_warned_at = ? WHERE id = ?
+`
+
+type MarkNoteExpiryWarnedParams struct {
+	ExpiryWarnedAt sql.NullString
+	ID             string
+}
+
+func (q *Queries) MarkNoteExpiryWarned(ctx context.Context, arg MarkNoteExpiryWarnedParams) error {
+	_, err := q.db.ExecContext(ctx, markNoteExpiryWarned, arg.ExpiryWarnedAt, arg.ID)
+	return err
+}
+
+const publishNote = `-- name: PublishNote :execrows
+
+UPDATE notes SET published_at = COALESCE(published_at, ?)
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type PublishNoteParams struct {
+	PublishedAt sql.NullString
+	ID          string
+	UserID      string
+}
+
+func (q *Queries) PublishNote(ctx context.Context, arg PublishNoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, publishNote, arg.PublishedAt, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const quickRecentNotes = `-- name: QuickRecentNotes :many
+
+SELECT id, substr(note, 1, 500) AS head, updated_at FROM notes
+WHERE user_id = ? AND deleted_at IS NULL
+ORDER BY updated_at DESC
+LIMIT ?
+`
+
+type QuickRecentNotesParams struct {
+	UserID string
+	Limit  int64
+}
+
+type QuickRecentNotesRow struct {
+	ID        string
+	Head      string
+	UpdatedAt string
+}
+
+func (q *Queries) QuickRecentNotes(ctx context.Context, arg QuickRecentNotesParams) ([]QuickRecentNotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, quickRecentNotes, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuickRecentNotesRow
+	for rows.Next() {
+		var i QuickRecentNotesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Head,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const quickSearchNotes = `-- name: QuickSearchNotes :many
+
+SELECT notes.id, substr(notes.note, 1, 500) AS head, notes.updated_at FROM notes_fts
+JOIN notes ON notes.rowid = notes_fts.rowid AND notes.id = notes_fts.note_id
+WHERE notes_fts MATCH ? AND notes.user_id = ? AND notes.deleted_at IS NULL
+ORDER BY notes.updated_at DESC
+LIMIT ?
+`
+
+type QuickSearchNotesParams struct {
+	Query  string
+	UserID string
+	Limit  int64
+}
+
+type QuickSearchNotesRow struct {
+	ID        string
+	Head      string
+	UpdatedAt string
+}
+
+func (q *Queries) QuickSearchNotes(ctx context.Context, arg QuickSearchNotesParams) ([]QuickSearchNotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, quickSearchNotes, arg.Query, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuickSearchNotesRow
+	for rows.Next() {
+		var i QuickSearchNotesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Head,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreNote = `-- name: RestoreNote :execrows
+
+UPDATE notes SET deleted_at = NULL
+WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+`
+
+type RestoreNoteParams struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) RestoreNote(ctx context.Context, arg RestoreNoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreNote, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setNoteExpiration = `-- name: SetNoteExpiration :execrows
+
+UPDATE notes SET expires_at = ?, expiry_warned_at = NULL, updated_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type SetNoteExpirationParams struct {
+	ExpiresAt sql.NullString
+	UpdatedAt string
+	ID        string
+	UserID    string
+}
+
+func (q *Queries) SetNoteExpiration(ctx context.Context, arg SetNoteExpirationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setNoteExpiration,
+		arg.ExpiresAt,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setNoteNoindex = `-- name: SetNoteNoindex :execrows
+
+UPDATE notes SET noindex = ?, updated_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type SetNoteNoindexParams struct {
+	Noindex   bool
+	UpdatedAt string
+	ID        string
+	UserID    string
+}
+
+func (q *Queries) SetNoteNoindex(ctx context.Context, arg SetNoteNoindexParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setNoteNoindex,
+		arg.Noindex,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setNoteNotebook = `-- name: SetNoteNotebook :execrows
+
+UPDATE notes SET notebook_id = ?, updated_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type SetNoteNotebookParams struct {
+	NotebookID sql.NullString
+	UpdatedAt  string
+	ID         string
+	UserID     string
+}
+
+func (q *Queries) SetNoteNotebook(ctx context.Context, arg SetNoteNotebookParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setNoteNotebook,
+		arg.NotebookID,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setNotePinned = `-- name: SetNotePinned :execrows
+
+UPDATE notes SET pinned = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type SetNotePinnedParams struct {
+	Pinned bool
+	ID     string
+	UserID string
+}
+
+func (q *Queries) SetNotePinned(ctx context.Context, arg SetNotePinnedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setNotePinned, arg.Pinned, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const suggestNoteTitles = `-- name: SuggestNoteTitles :many
+
+SELECT note_id AS id, title FROM note_list_entries
+WHERE user_id = ? AND title LIKE ? ESCAPE '\'
+AND (title, note_id) > (?, ?)
+ORDER BY title, note_id
+LIMIT ?
+`
+
+type SuggestNoteTitlesParams struct {
+	UserID     string
+	Title      string
+	AfterTitle string
+	AfterID    string
+	Limit      int64
+}
+
+type SuggestNoteTitlesRow struct {
+	ID    string
+	Title string
+}
+
+func (q *Queries) SuggestNoteTitles(ctx context.Context, arg SuggestNoteTitlesParams) ([]SuggestNoteTitlesRow, error) {
+	rows, err := q.db.QueryContext(ctx, suggestNoteTitles,
+		arg.UserID,
+		arg.Title,
+		arg.AfterTitle,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SuggestNoteTitlesRow
+	for rows.Next() {
+		var i SuggestNoteTitlesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchNote = `-- name: TouchNote :execrows
+
+UPDATE notes SET updated_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type TouchNoteParams struct {
+	UpdatedAt string
+	ID        string
+	UserID    string
+}
+
+func (q *Queries) TouchNote(ctx context.Context, arg TouchNoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, touchNote, arg.UpdatedAt, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const trashNote = `-- name: TrashNote :execrows
+
+UPDATE notes SET deleted_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type TrashNoteParams struct {
+	DeletedAt sql.NullString
+	ID        string
+	UserID    string
+}
+
+func (q *Queries) TrashNote(ctx context.Context, arg TrashNoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashNote, arg.DeletedAt, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unarchiveNote = `-- name: UnarchiveNote :execrows
+
+UPDATE notes SET archived_at = NULL
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+`
+
+type UnarchiveNoteParams struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) UnarchiveNote(ctx context.Context, arg UnarchiveNoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unarchiveNote, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unpublishNote = `-- name: UnpublishNote :exec
+
+UPDATE notes SET published_at = NULL WHERE id = ? AND user_id = ?
+`
+
+type UnpublishNoteParams struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) UnpublishNote(ctx context.Context, arg UnpublishNoteParams) error {
+	_, err := q.db.ExecContext(ctx, unpublishNote, arg.ID, arg.UserID)
+	return err
+}
+
+const updateNoteTitle = `-- name: UpdateNoteTitle :exec
+
+UPDATE notes SET title = ? WHERE id = ?
+`
+
+type UpdateNoteTitleParams struct {
+	Title string
+	ID    string
+}
+
+func (q *Queries) UpdateNoteTitle(ctx context.Context, arg UpdateNoteTitleParams) error {
+	_, err := q.db.ExecContext(ctx, updateNoteTitle, arg.Title, arg.ID)
+	return err
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxUnnamedQueryLength is how much of a query without a sqlc name comment
+// is logged in place of the name.
+const maxUnnamedQueryLength = 60
+
+// SlowQueryLog is a DBTX that logs statements slower than a threshold, with
+// the query plan of a sample of them, to find queries that miss an index
+// on production data. Statements run through WithTx bypass it.
+//
+// For queries returning rows, the time measured is until the first row is
+// available, which for a local database is before the rest are read.
+type SlowQueryLog struct {
+	db        DBTX
+	threshold time.Duration
+	planEvery time.Duration
+
+	mu        sync.Mutex
+	lastPlans map[string]time.Time
+}
+
+// NewSlowQueryLog wraps db. The plan of a slow query is logged the first
+// time it's slow and then at most once every planEvery, as EXPLAIN QUERY
+// PLAN costs another round trip.
+func NewSlowQueryLog(db DBTX, threshold, planEvery time.Duration) *SlowQueryLog {
+	return &SlowQueryLog{
+		db:        db,
+		threshold: threshold,
+		planEvery: planEvery,
+		lastPlans: make(map[string]time.Time),
+	}
+}
+
+func (s *SlowQueryLog) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := s.db.ExecContext(ctx, query, args...)
+	s.observe(ctx, query, args, time.Since(start))
+	return result, err
+}
+
+func (s *SlowQueryLog) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return s.db.PrepareContext(ctx, query)
+}
+
+func (s *SlowQueryLog) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	s.observe(ctx, query, args, time.Since(start))
+	return rows, err
+}
+
+func (s *SlowQueryLog) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := s.db.QueryRowContext(ctx, query, args...)
+	s.observe(ctx, query, args, time.Since(start))
+	return row
+}
+
+func (s *SlowQueryLog) observe(ctx context.Context, query string, args []interface{}, took time.Duration) {
+	if took < s.threshold {
+		return
+	}
+	name := queryName(query)
+	if !s.samplePlan(name, time.Now()) {
+		log.Printf("Slow query %s took %s", name, took)
+		return
+	}
+	// The caller is still waiting on or reading its own results.
+	go func() {
+		plan, err := s.explain(context.WithoutCancel(ctx), query, args)
+		if err != nil {
+			log.Printf("Slow query %s took %s, couldn't get plan: %v", name, took, err)
+			return
+		}
+		log.Printf("Slow query %s took %s, plan: %s", name, took, plan)
+	}()
+}
+
+// samplePlan reports whether the plan of query name should be logged now.
+func (s *SlowQueryLog) samplePlan(name string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.lastPlans[name]; ok && now.Sub(last) < s.planEvery {
+		return false
+	}
+	s.lastPlans[name] = now
+	return true
+}
+
+// explain returns the EXPLAIN QUERY PLAN steps of query, e.g.
+// "SEARCH notes USING INDEX notes_user_id_idx (user_id=?); USE TEMP B-TREE FOR ORDER BY".
+func (s *SlowQueryLog) explain(ctx context.Context, query string, args []interface{}) (string, error) {
+	rows, err := s.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var steps []string
+	for rows.Next() {
+		var id, parent, notUsed int64
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			return "", err
+		}
+		steps = append(steps, detail)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(steps, "; "), nil
+}
+
+// queryName returns the name sqlc gives a query in its leading
+// "-- name: GetNote :one" comment, or the start of the query if it has none.
+func queryName(query string) string {
+	if rest, ok := strings.CutPrefix(query, "-- name: "); ok {
+		if name, _, ok := strings.Cut(rest, " "); ok {
+			return name
+		}
+	}
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxUnnamedQueryLength {
+		query = query[:maxUnnamedQueryLength] + "..."
+	}
+	return query
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "sqlc name comment", query: "-- name: GetNote :one\nSELECT * FROM notes WHERE id = ?", want: "GetNote"},
+		{name: "unnamed query", query: "PRAGMA freelist_count", want: "PRAGMA freelist_count"},
+		{name: "whitespace is collapsed", query: "SELECT 1\n  FROM\tnotes", want: "SELECT 1 FROM notes"},
+		{name: "long unnamed query is cut", query: "SELECT " + strings.Repeat("x", 100), want: "SELECT " + strings.Repeat("x", 53) + "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryName(tt.query); got != tt.want {
+				t.Errorf("queryName(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplePlan(t *testing.T) {
+	s := NewSlowQueryLog(nil, time.Second, 10*time.Minute)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query string
+		at    time.Duration
+		want  bool
+	}{
+		{name: "first slow call is explained", query: "GetNote", at: 0, want: true},
+		{name: "repeat within interval is not", query: "GetNote", at: time.Minute, want: false},
+		{name: "other queries are sampled separately", query: "GetNotesForUser", at: time.Minute, want: true},
+		{name: "repeat after interval is explained", query: "GetNote", at: 10 * time.Minute, want: true},
+		{name: "interval restarts from last plan", query: "GetNote", at: 15 * time.Minute, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.samplePlan(tt.query, start.Add(tt.at)); got != tt.want {
+				t.Errorf("samplePlan(%q) at +%s = %v, want %v", tt.query, tt.at, got, tt.want)
+			}
+		})
+	}
+}
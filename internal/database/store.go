@@ -0,0 +1,224 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// ErrNotFound is returned instead of sql.ErrNoRows when a lookup matches no row.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict matches any *ConflictError via errors.Is.
+var ErrConflict = errors.New("conflict")
+
+// ConflictError is returned when a write violates a unique constraint.
+type ConflictError struct {
+	Table  string
+	Column string
+}
+
+func (e *ConflictError) Error() string {
+	return "unique constraint failed: " + e.Table + "." + e.Column
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Store wraps the sqlc-generated Queries and translates database/sql errors
+// into this package's sentinel errors, so callers never see sql.ErrNoRows.
+// Only methods that can return those errors are overridden.
+type Store struct {
+	*Queries
+}
+
+func NewStore(db DBTX) *Store {
+	return &Store{Queries: New(db)}
+}
+
+func (s *Store) WithTx(tx *sql.Tx) *Store {
+	return &Store{Queries: s.Queries.WithTx(tx)}
+}
+
+func (s *Store) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) error {
+	return translateError(s.Queries.CreateAPIKey(ctx, arg))
+}
+
+func (s *Store) CreateNote(ctx context.Context, arg CreateNoteParams) error {
+	return translateError(s.Queries.CreateNote(ctx, arg))
+}
+
+func (s *Store) CreateNotebook(ctx context.Context, arg CreateNotebookParams) (Notebook, error) {
+	notebook, err := s.Queries.CreateNotebook(ctx, arg)
+	return notebook, translateError(err)
+}
+
+func (s *Store) CreateUser(ctx context.Context, arg CreateUserParams) error {
+	return translateError(s.Queries.CreateUser(ctx, arg))
+}
+
+func (s *Store) GetAPIKeyForUser(ctx context.Context, arg GetAPIKeyForUserParams) (ApiKey, error) {
+	key, err := s.Queries.GetAPIKeyForUser(ctx, arg)
+	return key, translateError(err)
+}
+
+func (s *Store) GetBillingCustomer(ctx context.Context, userID string) (BillingCustomer, error) {
+	customer, err := s.Queries.GetBillingCustomer(ctx, userID)
+	return customer, translateError(err)
+}
+
+func (s *Store) GetBillingCustomerByStripeID(ctx context.Context, stripeCustomerID string) (BillingCustomer, error) {
+	customer, err := s.Queries.GetBillingCustomerByStripeID(ctx, stripeCustomerID)
+	return customer, translateError(err)
+}
+
+func (s *Store) GetDeviceTokenByToken(ctx context.Context, token string) (DeviceToken, error) {
+	device, err := s.Queries.GetDeviceTokenByToken(ctx, token)
+	return device, translateError(err)
+}
+
+func (s *Store) GetLatestIndexRebuild(ctx context.Context) (IndexRebuild, error) {
+	rebuild, err := s.Queries.GetLatestIndexRebuild(ctx)
+	return rebuild, translateError(err)
+}
+
+func (s *Store) GetLinkPreview(ctx context.Context, url string) (LinkPreview, error) {
+	preview, err := s.Queries.GetLinkPreview(ctx, url)
+	return preview, translateError(err)
+}
+
+func (s *Store) GetNote(ctx context.Context, id string) (Note, error) {
+	note, err := s.Queries.GetNote(ctx, id)
+	return note, translateError(err)
+}
+
+func (s *Store) GetNoteByID(ctx context.Context, arg GetNoteByIDParams) (Note, error) {
+	note, err := s.Queries.GetNoteByID(ctx, arg)
+	return note, translateError(err)
+}
+
+func (s *Store) GetNoteComment(ctx context.Context, id string) (NoteComment, error) {
+	comment, err := s.Queries.GetNoteComment(ctx, id)
+	return comment, translateError(err)
+}
+
+func (s *Store) GetNoteSummary(ctx context.Context, noteID string) (NoteSummary, error) {
+	summary, err := s.Queries.GetNoteSummary(ctx, noteID)
+	return summary, translateError(err)
+}
+
+func (s *Store) GetNoteTranslation(ctx context.Context, arg GetNoteTranslationParams) (NoteTranslation, error) {
+	translation, err := s.Queries.GetNoteTranslation(ctx, arg)
+	return translation, translateError(err)
+}
+
+func (s *Store) GetNoteView(ctx context.Context, noteID string) (NoteView, error) {
+	view, err := s.Queries.GetNoteView(ctx, noteID)
+	return view, translateError(err)
+}
+
+func (s *Store) GetNotebook(ctx context.Context, arg GetNotebookParams) (Notebook, error) {
+	notebook, err := s.Queries.GetNotebook(ctx, arg)
+	return notebook, translateError(err)
+}
+
+func (s *Store) GetNotificationQuietHours(ctx context.Context, userID string) (NotificationQuietHour, error) {
+	quietHours, err := s.Queries.GetNotificationQuietHours(ctx, userID)
+	return quietHours, translateError(err)
+}
+
+func (s *Store) GetPlan(ctx context.Context, id string) (Plan, error) {
+	plan, err := s.Queries.GetPlan(ctx, id)
+	return plan, translateError(err)
+}
+
+func (s *Store) GetPlanForUser(ctx context.Context, userID string) (Plan, error) {
+	plan, err := s.Queries.GetPlanForUser(ctx, userID)
+	return plan, translateError(err)
+}
+
+func (s *Store) GetPublishedNote(ctx context.Context, arg GetPublishedNoteParams) (Note, error) {
+	note, err := s.Queries.GetPublishedNote(ctx, arg)
+	return note, translateError(err)
+}
+
+func (s *Store) GetTenantLocation(ctx context.Context, id string) (GetTenantLocationRow, error) {
+	location, err := s.Queries.GetTenantLocation(ctx, id)
+	return location, translateError(err)
+}
+
+func (s *Store) GetUnfinishedIndexRebuild(ctx context.Context) (IndexRebuild, error) {
+	rebuild, err := s.Queries.GetUnfinishedIndexRebuild(ctx)
+	return rebuild, translateError(err)
+}
+
+func (s *Store) GetUser(ctx context.Context, apiKey string) (User, error) {
+	user, err := s.Queries.GetUser(ctx, apiKey)
+	return user, translateError(err)
+}
+
+func (s *Store) GetUserByAPIKey(ctx context.Context, arg GetUserByAPIKeyParams) (User, error) {
+	user, err := s.Queries.GetUserByAPIKey(ctx, arg)
+	return user, translateError(err)
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id string) (User, error) {
+	user, err := s.Queries.GetUserByID(ctx, id)
+	return user, translateError(err)
+}
+
+func (s *Store) GetUserByUsername(ctx context.Context, username sql.NullString) (User, error) {
+	user, err := s.Queries.GetUserByUsername(ctx, username)
+	return user, translateError(err)
+}
+
+func (s *Store) GetUserPlan(ctx context.Context, userID string) (UserPlan, error) {
+	plan, err := s.Queries.GetUserPlan(ctx, userID)
+	return plan, translateError(err)
+}
+
+func (s *Store) GetVAPIDKey(ctx context.Context) (string, error) {
+	key, err := s.Queries.GetVAPIDKey(ctx)
+	return key, translateError(err)
+}
+
+func (s *Store) GetWebPushSubscriptionByEndpoint(ctx context.Context, endpoint string) (WebPushSubscription, error) {
+	subscription, err := s.Queries.GetWebPushSubscriptionByEndpoint(ctx, endpoint)
+	return subscription, translateError(err)
+}
+
+func (s *Store) SetStripeCustomer(ctx context.Context, arg SetStripeCustomerParams) error {
+	return translateError(s.Queries.SetStripeCustomer(ctx, arg))
+}
+
+func (s *Store) UpdateNotebook(ctx context.Context, arg UpdateNotebookParams) (Notebook, error) {
+	notebook, err := s.Queries.UpdateNotebook(ctx, arg)
+	return notebook, translateError(err)
+}
+
+func (s *Store) UpdateUsername(ctx context.Context, arg UpdateUsernameParams) error {
+	return translateError(s.Queries.UpdateUsername(ctx, arg))
+}
+
+// uniqueViolation is how SQLite (and libsql over the wire) reports a unique
+// constraint failure, followed by the offending "table.column" list.
+const uniqueViolation = "UNIQUE constraint failed: "
+
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if _, cols, ok := strings.Cut(err.Error(), uniqueViolation); ok {
+		// Composite constraints list several columns; report the first one.
+		col, _, _ := strings.Cut(strings.TrimSpace(cols), ",")
+		col, _, _ = strings.Cut(col, " ")
+		table, column, _ := strings.Cut(col, ".")
+		return &ConflictError{Table: table, Column: column}
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTranslateError(t *testing.T) {
+	other := errors.New("connection reset")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "nil stays nil", err: nil, want: nil},
+		{name: "no rows becomes not found", err: sql.ErrNoRows, want: ErrNotFound},
+		{name: "wrapped no rows becomes not found", err: fmt.Errorf("scan: %w", sql.ErrNoRows), want: ErrNotFound},
+		{name: "other errors pass through", err: other, want: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := translateError(tt.err); got != tt.want {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateErrorConflict(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantTable  string
+		wantColumn string
+	}{
+		{
+			name:       "single column",
+			err:        errors.New("UNIQUE constraint failed: users.api_key"),
+			wantTable:  "users",
+			wantColumn: "api_key",
+		},
+		{
+			name:       "driver suffix is ignored",
+			err:        errors.New("stepping, UNIQUE constraint failed: users.api_key (19)"),
+			wantTable:  "users",
+			wantColumn: "api_key",
+		},
+		{
+			name:       "composite constraint reports first column",
+			err:        errors.New("SQLite error: UNIQUE constraint failed: note_tags.note_id, note_tags.tag"),
+			wantTable:  "note_tags",
+			wantColumn: "note_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err)
+			if !errors.Is(got, ErrConflict) {
+				t.Fatalf("translateError(%v) = %v, want ErrConflict", tt.err, got)
+			}
+			var conflict *ConflictError
+			if !errors.As(got, &conflict) {
+				t.Fatalf("translateError(%v) is not a *ConflictError", tt.err)
+			}
+			if conflict.Table != tt.wantTable || conflict.Column != tt.wantColumn {
+				t.Errorf("got %s.%s, want %s.%s", conflict.Table, conflict.Column, tt.wantTable, tt.wantColumn)
+			}
+		})
+	}
+}
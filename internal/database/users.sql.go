@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUser = `-- name: CreateUser :exec
@@ -41,7 +42,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
 
 const getUser = `-- name: GetUser :one
 
-SELECT id, created_at, updated_at, name, api_key FROM users WHERE api_key = ?
+SELECT id, created_at, updated_at, name, api_key, username, profile_public, username_changed_at FROM users WHERE api_key = ?
 `
 
 func (q *Queries) GetUser(ctx context.Context, apiKey string) (User, error) {
@@ -53,6 +54,190 @@ func (q *Queries) GetUser(ctx context.Context, apiKey string) (User, error) {
 		&i.UpdatedAt,
 		&i.Name,
 		&i.ApiKey,
+		&i.Username,
+		&i.ProfilePublic,
+		&i.UsernameChangedAt,
 	)
 	return i, err
 }
+
+const getUserByAPIKey = `-- name: GetUserByAPIKey :one
+
+SELECT users.id, users.created_at, users.updated_at, users.name, users.api_key, users.username, users.profile_public, users.username_changed_at FROM users
+JOIN api_keys ON api_keys.user_id = users.id
+WHERE api_keys.api_key = ?
+AND (api_keys.expires_at IS NULL OR api_keys.expires_at > ?)
+`
+
+type GetUserByAPIKeyParams struct {
+	ApiKey string
+	Now    sql.NullString
+}
+
+func (q *Queries) GetUserByAPIKey(ctx context.Context, arg GetUserByAPIKeyParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByAPIKey, arg.ApiKey, arg.Now)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.ApiKey,
+		&i.Username,
+		&i.ProfilePublic,
+		&i.UsernameChangedAt,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+
+SELECT id, created_at, updated_at, name, api_key, username, profile_public, username_changed_at FROM users WHERE id = ?
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.ApiKey,
+		&i.Username,
+		&i.ProfilePublic,
+		&i.UsernameChangedAt,
+	)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+
+SELECT id, created_at, updated_at, name, api_key, username, profile_public, username_changed_at FROM users WHERE username = ?
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.ApiKey,
+		&i.Username,
+		&i.ProfilePublic,
+		&i.UsernameChangedAt,
+	)
+	return i, err
+}
+
+const setUserProfilePublic = `-- name: SetUserProfilePublic :exec
+
+UPDATE users SET profile_public = ?, updated_at = ? WHERE id = ?
+`
+
+type SetUserProfilePublicParams struct {
+	ProfilePublic bool
+	UpdatedAt     string
+	ID            string
+}
+
+func (q *Queries) SetUserProfilePublic(ctx context.Context, arg SetUserProfilePublicParams) error {
+	_, err := q.db.ExecContext(ctx, setUserProfilePublic, arg.ProfilePublic, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const updateUserAPIKey = `-- name: UpdateUserAPIKey :exec
+
+UPDATE users SET api_key = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateUserAPIKeyParams struct {
+	ApiKey    string
+	UpdatedAt string
+	ID        string
+}
+
+func (q *Queries) UpdateUserAPIKey(ctx context.Context, arg UpdateUserAPIKeyParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserAPIKey, arg.ApiKey, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const updateUsername = `-- name: UpdateUsername :exec
+
+UPDATE users SET username = ?, username_changed_at = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateUsernameParams struct {
+	Username          sql.NullString
+	UsernameChangedAt sql.NullString
+	UpdatedAt         string
+	ID                string
+}
+
+func (q *Queries) UpdateUsername(ctx context.Context, arg UpdateUsernameParams) error {
+	_, err := q.db.ExecContext(ctx, updateUsername,
+		arg.Username,
+		arg.UsernameChangedAt,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const listUsers = `-- name: ListUsers :many
+
+SELECT users.id, users.created_at, users.name, users.username,
+    (SELECT COUNT(*) FROM notes WHERE notes.user_id = users.id) AS notes,
+    (SELECT COUNT(*) FROM api_keys WHERE api_keys.user_id = users.id
+        AND (api_keys.expires_at IS NULL OR api_keys.expires_at > ?)) AS active_keys
+FROM users
+ORDER BY users.created_at DESC, users.id
+LIMIT ? OFFSET ?
+`
+
+type ListUsersParams struct {
+	Now    sql.NullString
+	Limit  int64
+	Offset int64
+}
+
+type ListUsersRow struct {
+	ID         string
+	CreatedAt  string
+	Name       string
+	Username   sql.NullString
+	Notes      int64
+	ActiveKeys int64
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers, arg.Now, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Name,
+			&i.Username,
+			&i.Notes,
+			&i.ActiveKeys,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
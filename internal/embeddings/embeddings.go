@@ -0,0 +1,42 @@
+// Package embeddings turns text into vectors for semantic search.
+package embeddings
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Embedder produces an embedding vector for a piece of text. Vectors from
+// different models aren't comparable, so callers store Model alongside them.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	Model() string
+}
+
+// requestTimeout bounds a single call to an embedding provider.
+const requestTimeout = 30 * time.Second
+
+// FromEnv builds the Embedder selected by EMBEDDINGS_PROVIDER ("openai" for
+// any OpenAI-compatible endpoint, or "ollama" for a local model). Embeddings
+// are off by default, in which case nil is returned.
+func FromEnv(getenv func(string) string) (Embedder, error) {
+	switch provider := getenv("EMBEDDINGS_PROVIDER"); provider {
+	case "":
+		return nil, nil
+	case "openai":
+		return NewOpenAI(
+			cmp.Or(getenv("EMBEDDINGS_URL"), "https://api.openai.com/v1"),
+			getenv("EMBEDDINGS_API_KEY"),
+			cmp.Or(getenv("EMBEDDINGS_MODEL"), "text-embedding-3-small"),
+		), nil
+	case "ollama":
+		return NewOllama(
+			cmp.Or(getenv("EMBEDDINGS_URL"), "http://127.0.0.1:11434"),
+			cmp.Or(getenv("EMBEDDINGS_MODEL"), "nomic-embed-text"),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown EMBEDDINGS_PROVIDER %q", provider)
+	}
+}
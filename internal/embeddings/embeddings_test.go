@@ -0,0 +1,135 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantNil   bool
+		wantModel string
+		wantErr   bool
+	}{
+		{name: "disabled by default", env: map[string]string{}, wantNil: true},
+		{name: "unknown provider", env: map[string]string{"EMBEDDINGS_PROVIDER": "magic"}, wantErr: true},
+		{name: "openai default model", env: map[string]string{"EMBEDDINGS_PROVIDER": "openai"}, wantModel: "text-embedding-3-small"},
+		{name: "ollama custom model", env: map[string]string{"EMBEDDINGS_PROVIDER": "ollama", "EMBEDDINGS_MODEL": "all-minilm"}, wantModel: "all-minilm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := FromEnv(func(k string) string { return tt.env[k] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (e == nil) != tt.wantNil {
+				t.Fatalf("FromEnv() = %v, wantNil %v", e, tt.wantNil)
+			}
+			if e != nil && e.Model() != tt.wantModel {
+				t.Errorf("Model() = %q, want %q", e.Model(), tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestProviders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/embeddings":
+			if r.Header.Get("Authorization") != "Bearer secret" || body["input"] != "hello" {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"data":[{"embedding":[1,2,3]}]}`))
+		case "/api/embeddings":
+			if body["prompt"] != "hello" {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"embedding":[1,2,3]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		embedder Embedder
+		wantErr  bool
+	}{
+		{name: "openai", embedder: NewOpenAI(srv.URL+"/v1/", "secret", "m")},
+		{name: "openai wrong key", embedder: NewOpenAI(srv.URL+"/v1", "wrong", "m"), wantErr: true},
+		{name: "ollama", embedder: NewOllama(srv.URL, "m")},
+		{name: "not found", embedder: NewOllama(srv.URL+"/missing", "m"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.embedder.Embed(context.Background(), "hello")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Embed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (len(v) != 3 || v[2] != 3) {
+				t.Errorf("Embed() = %v, want [1 2 3]", v)
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	v := []float32{0, -1.5, 3.25, float32(math.Pi)}
+	got, err := Decode(Encode(v))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(got) != len(v) {
+		t.Fatalf("Decode() = %v, want %v", got, v)
+	}
+	for i := range v {
+		if got[i] != v[i] {
+			t.Errorf("Decode()[%d] = %v, want %v", i, got[i], v[i])
+		}
+	}
+
+	if _, err := Decode([]byte{1, 2, 3}); err != ErrInvalidVector {
+		t.Errorf("Decode(3 bytes) error = %v, want ErrInvalidVector", err)
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{name: "identical", a: []float32{1, 2}, b: []float32{1, 2}, want: 1},
+		{name: "orthogonal", a: []float32{1, 0}, b: []float32{0, 1}, want: 0},
+		{name: "opposite", a: []float32{1, 1}, b: []float32{-1, -1}, want: -1},
+		{name: "different lengths", a: []float32{1}, b: []float32{1, 0}, want: 0},
+		{name: "zero vector", a: []float32{0, 0}, b: []float32{1, 0}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cosine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Cosine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
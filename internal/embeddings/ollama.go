@@ -0,0 +1,47 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/httpjson"
+)
+
+// Ollama embeds text with a model served by a local Ollama instance.
+type Ollama struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+func NewOllama(baseURL, model string) *Ollama {
+	return &Ollama{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (o *Ollama) Model() string {
+	return o.model
+}
+
+func (o *Ollama) Embed(ctx context.Context, text string) ([]float32, error) {
+	var resp struct {
+		Embedding []float32 `json:"embedding"`
+	}
+	err := httpjson.Post(ctx, o.client, o.baseURL+"/api/embeddings", nil, map[string]string{
+		"model":  o.model,
+		"prompt": text,
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	if len(resp.Embedding) == 0 {
+		return nil, errors.New("embedding response contained no vector")
+	}
+	return resp.Embedding, nil
+}
@@ -0,0 +1,58 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/httpjson"
+)
+
+// OpenAI calls the /embeddings endpoint of the OpenAI API or any server
+// implementing the same interface.
+type OpenAI struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAI creates an OpenAI-compatible embedder. apiKey may be empty for
+// servers that don't require one.
+func NewOpenAI(baseURL, apiKey, model string) *OpenAI {
+	return &OpenAI{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (o *OpenAI) Model() string {
+	return o.model
+}
+
+func (o *OpenAI) Embed(ctx context.Context, text string) ([]float32, error) {
+	header := http.Header{}
+	if o.apiKey != "" {
+		header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	var resp struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	err := httpjson.Post(ctx, o.client, o.baseURL+"/embeddings", header, map[string]string{
+		"model": o.model,
+		"input": text,
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	if len(resp.Data) == 0 || len(resp.Data[0].Embedding) == 0 {
+		return nil, errors.New("embedding response contained no vector")
+	}
+	return resp.Data[0].Embedding, nil
+}
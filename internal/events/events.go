@@ -0,0 +1,86 @@
+// Package events publishes note lifecycle events to external systems.
+// internal/events/events.go:
+package events
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Event types emitted by the API.
+const (
+	TypeNoteCreated    = "note.created"
+	TypeNotePublished  = "note.published"
+	TypeNoteExpiring   = "note.expiring"
+	TypeNoteExpired    = "note.expired"
+	TypeNoteTrashed    = "note.trashed"
+	TypeNoteRestored   = "note.restored"
+	TypeNoteDeleted    = "note.deleted"
+	TypeNoteArchived   = "note.archived"
+	TypeNoteUnarchived = "note.unarchived"
+	TypeNotePinned     = "note.pinned"
+	TypeNoteUnpinned   = "note.unpinned"
+
+	TypeCommentCreated = "comment.created"
+	TypeCommentDeleted = "comment.deleted"
+
+	TypeTrialEnding = "trial.ending"
+	TypeTrialEnded  = "trial.ended"
+)
+
+// Event is the JSON document delivered to every backend.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	UserID     string    `json:"user_id"`
+	NoteID     string    `json:"note_id,omitempty"` // Empty for trial events.
+	CommentID  string    `json:"comment_id,omitempty"`
+}
+
+// Publisher delivers events. Implementations must not block the caller on
+// network round trips; delivery failures are reported asynchronously where
+// the backend allows it.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+	Close() error
+}
+
+// Nop is the Publisher used when no backend is configured.
+type Nop struct{}
+
+func (Nop) Publish(context.Context, Event) error { return nil }
+
+func (Nop) Close() error { return nil }
+
+// FromEnv builds the Publisher selected by EVENTS_BACKEND ("nats" or "kafka").
+// Publishing is off by default, in which case Nop is returned.
+func FromEnv(getenv func(string) string) (Publisher, error) {
+	switch backend := getenv("EVENTS_BACKEND"); backend {
+	case "":
+		return Nop{}, nil
+	case "nats":
+		return NewNATS(getenv("EVENTS_NATS_URL"), cmp.Or(getenv("EVENTS_NATS_SUBJECT_PREFIX"), "notely"))
+	case "kafka":
+		brokers := splitList(getenv("EVENTS_KAFKA_BROKERS"))
+		if len(brokers) == 0 {
+			return nil, fmt.Errorf("EVENTS_KAFKA_BROKERS is required for the kafka backend")
+		}
+		return NewKafka(brokers, cmp.Or(getenv("EVENTS_KAFKA_TOPIC"), "notely.events")), nil
+	default:
+		return nil, fmt.Errorf("unknown EVENTS_BACKEND %q", backend)
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,35 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantNop bool
+		wantErr bool
+	}{
+		{name: "disabled by default", env: map[string]string{}, wantNop: true},
+		{name: "unknown backend", env: map[string]string{"EVENTS_BACKEND": "carrier-pigeon"}, wantErr: true},
+		{name: "kafka without brokers", env: map[string]string{"EVENTS_BACKEND": "kafka", "EVENTS_KAFKA_BROKERS": " , "}, wantErr: true},
+		{name: "kafka with brokers", env: map[string]string{"EVENTS_BACKEND": "kafka", "EVENTS_KAFKA_BROKERS": "localhost:9092"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := FromEnv(func(k string) string { return tt.env[k] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer p.Close()
+			if _, isNop := p.(Nop); isNop != tt.wantNop {
+				t.Errorf("FromEnv() = %T, wantNop %v", p, tt.wantNop)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka publishes events to a single topic, keyed by note ID so all events
+// for one note land on the same partition in order.
+type Kafka struct {
+	writer *kafka.Writer
+}
+
+// NewKafka returns an asynchronous writer; delivery errors are logged.
+func NewKafka(brokers []string, topic string) *Kafka {
+	return &Kafka{writer: &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		Async:                  true,
+		AllowAutoTopicCreation: true,
+		Completion: func(_ []kafka.Message, err error) {
+			if err != nil {
+				log.Printf("kafka: %v", err)
+			}
+		},
+	}}
+}
+
+func (k *Kafka) Publish(ctx context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return k.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(e.NoteID),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: "type", Value: []byte(e.Type)},
+		},
+	})
+}
+
+// Close flushes pending messages.
+func (k *Kafka) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATS publishes each event to "<prefix>.<event type>", e.g. "notely.note.created".
+type NATS struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATS connects to url (nats.DefaultURL when empty). The client buffers
+// publishes and keeps (re)connecting on its own, so neither startup nor
+// Publish waits on the network.
+func NewNATS(url, prefix string) (*NATS, error) {
+	if url == "" {
+		url = nats.DefaultURL
+	}
+	conn, err := nats.Connect(url,
+		nats.Name("notely"),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			log.Printf("nats: %v", err)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &NATS{conn: conn, prefix: prefix}, nil
+}
+
+func (n *NATS) Publish(_ context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return n.conn.Publish(n.prefix+"."+e.Type, data)
+}
+
+// Close flushes buffered events before disconnecting.
+func (n *NATS) Close() error {
+	return n.conn.Drain()
+}
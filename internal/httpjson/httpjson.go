@@ -0,0 +1,57 @@
+// Package httpjson calls the JSON APIs of external services, such as
+// embedding, completion and translation providers, and alert webhooks.
+package httpjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// StatusError is returned by Post when the service responds with a status
+// outside 2xx. Body holds the start of the response, which usually says
+// what went wrong.
+type StatusError struct {
+	Status string
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+// Post sends body to url and decodes a successful JSON response into out,
+// unless out is nil, in which case the response can be anything, such as
+// the plain "ok" of a Slack webhook. header is added to the request, which
+// may be nil.
+func Post(ctx context.Context, client *http.Client, url string, header http.Header, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{Status: resp.Status, Body: string(bytes.TrimSpace(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,72 @@
+package httpjson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		switch r.Header.Get("Authorization") {
+		case "Bearer secret":
+		case "Bearer webhook":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte("ok"))
+			return
+		default:
+			http.Error(w, "  forbidden\n", http.StatusForbidden)
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(map[string]string{"echo": body["text"]})
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		noOut      bool
+		want       string
+		wantStatus string
+		wantBody   string
+	}{
+		{name: "ok", header: http.Header{"Authorization": {"Bearer secret"}}, want: "hi"},
+		{name: "no output", header: http.Header{"Authorization": {"Bearer webhook"}}, noOut: true},
+		{name: "error status", header: nil, wantStatus: "403 Forbidden", wantBody: "forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out struct {
+				Echo string `json:"echo"`
+			}
+			var dst interface{} = &out
+			if tt.noOut {
+				dst = nil
+			}
+			err := Post(context.Background(), srv.Client(), srv.URL, tt.header, map[string]string{"text": "hi"}, dst)
+			if tt.wantStatus != "" {
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.Status != tt.wantStatus || statusErr.Body != tt.wantBody {
+					t.Fatalf("Post() error = %#v, want status %q and body %q", err, tt.wantStatus, tt.wantBody)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			if out.Echo != tt.want {
+				t.Errorf("Post() decoded %q, want %q", out.Echo, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package jsoncase renames the keys of encoded JSON objects from the API's
+// snake_case to camelCase, for clients whose conventions expect it.
+package jsoncase
+
+// Camel returns data with every snake_case object key renamed to camelCase,
+// e.g. "last_viewed_at" to "lastViewedAt". Values, including strings that
+// look like keys, and keys that aren't plain snake_case are left as they
+// are. data must be valid JSON.
+func Camel(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] != '"' {
+			out = append(out, data[i])
+			continue
+		}
+		end := stringEnd(data, i)
+		if isKey(data, end) && isSnake(data[i+1:end]) {
+			out = appendCamel(append(out, '"'), data[i+1:end])
+			out = append(out, '"')
+		} else {
+			out = append(out, data[i:end+1]...)
+		}
+		i = end
+	}
+	return out
+}
+
+// stringEnd returns the index of the quote closing the string that opens
+// at data[start].
+func stringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(data) - 1
+}
+
+// isKey reports whether the string ending at data[end] is an object key,
+// that is followed by a colon.
+func isKey(data []byte, end int) bool {
+	for i := end + 1; i < len(data); i++ {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// isSnake reports whether key consists of lowercase words joined by single
+// underscores, like the API's own field names.
+func isSnake(key []byte) bool {
+	if len(key) == 0 || key[0] == '_' || key[len(key)-1] == '_' {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '_' && key[i-1] != '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func appendCamel(out, key []byte) []byte {
+	upper := false
+	for _, c := range key {
+		if c == '_' {
+			upper = true
+			continue
+		}
+		if upper && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package jsoncase
+
+import "testing"
+
+func TestCamel(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "object", in: `{"id":"1","created_at":"x","last_viewed_at":null}`, want: `{"id":"1","createdAt":"x","lastViewedAt":null}`},
+		{name: "nested", in: `[{"link_previews":[{"image_url":"u","site_name":"s"}]}]`, want: `[{"linkPreviews":[{"imageUrl":"u","siteName":"s"}]}]`},
+		{name: "values are kept", in: `{"note":"user_id","tags":["a_b"]}`, want: `{"note":"user_id","tags":["a_b"]}`},
+		{name: "escaped quotes", in: `{"note":"say \"hi_there\": ok","source_url":"\\"}`, want: `{"note":"say \"hi_there\": ok","sourceUrl":"\\"}`},
+		{name: "whitespace before colon", in: "{\"next_cursor\" : 1}", want: "{\"nextCursor\" : 1}"},
+		{name: "keys that aren't snake case", in: `{"comment.created":{"in_app":true},"_x":1,"a__b":2,"Up_per":3}`, want: `{"comment.created":{"inApp":true},"_x":1,"a__b":2,"Up_per":3}`},
+		{name: "digits", in: `{"sha_256":"x","v2_id":1}`, want: `{"sha256":"x","v2Id":1}`},
+		{name: "scalar", in: `"created_at"`, want: `"created_at"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Camel([]byte(tt.in))); got != tt.want {
+				t.Errorf("Camel(%s) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
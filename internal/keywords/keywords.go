@@ -0,0 +1,137 @@
+// Package keywords extracts characteristic terms from notes with TF-IDF.
+package keywords
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// minTermLength drops very short tokens, which are rarely useful keywords.
+const minTermLength = 3
+
+// Keyword is a term and its TF-IDF weight within one document.
+type Keyword struct {
+	Term  string
+	Score float64
+}
+
+// Corpus holds document frequencies for a set of documents, e.g. all of a
+// user's notes, against which individual documents are weighted.
+type Corpus struct {
+	docs    int
+	docFreq map[string]int
+}
+
+func NewCorpus(docs []string) *Corpus {
+	c := &Corpus{docs: len(docs), docFreq: make(map[string]int)}
+	for _, doc := range docs {
+		seen := make(map[string]bool)
+		for _, term := range Tokenize(doc) {
+			if !seen[term] {
+				seen[term] = true
+				c.docFreq[term]++
+			}
+		}
+	}
+	return c
+}
+
+// Weights returns the TF-IDF weight of every term in doc. Terms that appear
+// in many documents of the corpus weigh less than ones specific to doc.
+func (c *Corpus) Weights(doc string) map[string]float64 {
+	terms := Tokenize(doc)
+	weights := make(map[string]float64)
+	if len(terms) == 0 {
+		return weights
+	}
+	for _, term := range terms {
+		weights[term]++
+	}
+	for term, count := range weights {
+		// Smoothed so terms missing from the corpus and corpora of one document still work.
+		idf := math.Log(float64(1+c.docs)/float64(1+c.docFreq[term])) + 1
+		weights[term] = count / float64(len(terms)) * idf
+	}
+	return weights
+}
+
+// Keywords returns the n highest-weighted terms of doc, best first.
+func (c *Corpus) Keywords(doc string, n int) []Keyword {
+	weights := c.Weights(doc)
+	keywords := make([]Keyword, 0, len(weights))
+	for term, score := range weights {
+		keywords = append(keywords, Keyword{Term: term, Score: score})
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Term < keywords[j].Term
+	})
+	if len(keywords) > n {
+		keywords = keywords[:n]
+	}
+	return keywords
+}
+
+// Tokenize lowercases text and splits it into words, dropping stop words,
+// numbers and tokens shorter than minTermLength.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) < minTermLength || stopWords[f] || isNumber(f) {
+			continue
+		}
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// stopWords are common English words that carry no topic of their own.
+var stopWords = map[string]bool{
+	"about": true, "after": true, "again": true, "all": true, "also": true, "and": true,
+	"any": true, "are": true, "because": true, "been": true, "before": true, "but": true,
+	"can": true, "could": true, "did": true, "does": true, "doing": true, "don": true,
+	"each": true, "for": true, "from": true, "get": true, "had": true, "has": true,
+	"have": true, "her": true, "here": true, "him": true, "his": true, "how": true,
+	"into": true, "its": true, "just": true, "like": true, "more": true, "most": true,
+	"not": true, "now": true, "off": true, "once": true, "only": true, "other": true,
+	"our": true, "out": true, "over": true, "same": true, "she": true, "should": true,
+	"some": true, "such": true, "than": true, "that": true, "the": true, "their": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true, "this": true,
+	"those": true, "through": true, "too": true, "under": true, "until": true, "very": true,
+	"was": true, "were": true, "what": true, "when": true, "where": true, "which": true,
+	"while": true, "who": true, "why": true, "will": true, "with": true, "would": true,
+	"you": true, "your": true,
+}
+
+// Similarity is the cosine similarity of two weight vectors from Weights,
+// 0 when they share no terms.
+func Similarity(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, wa := range a {
+		dot += wa * b[term]
+		normA += wa * wa
+	}
+	for _, wb := range b {
+		normB += wb * wb
+	}
+	if dot == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
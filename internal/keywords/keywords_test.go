@@ -0,0 +1,88 @@
+package keywords
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: []string{}},
+		{name: "lowercases and splits punctuation", text: "Go, Kubernetes!", want: []string{"kubernetes"}},
+		{name: "drops stop words and numbers", text: "the deploy for 2024 was fine", want: []string{"deploy", "fine"}},
+		{name: "keeps letters outside ASCII", text: "Größe über café", want: []string{"größe", "über", "café"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	corpus := NewCorpus([]string{
+		"meeting notes: budget and finance",
+		"meeting notes: hiring plan",
+		"meeting notes: roadmap",
+	})
+
+	tests := []struct {
+		name string
+		doc  string
+		n    int
+		want []string
+	}{
+		{name: "specific terms beat common ones", doc: "meeting notes: budget and finance", n: 2, want: []string{"budget", "finance"}},
+		{name: "repeated terms rank higher", doc: "roadmap roadmap hiring", n: 1, want: []string{"roadmap"}},
+		{name: "fewer terms than n", doc: "roadmap", n: 5, want: []string{"roadmap"}},
+		{name: "no terms", doc: "the and", n: 5, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, k := range corpus.Keywords(tt.doc, tt.n) {
+				got = append(got, k.Term)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Keywords() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	corpus := NewCorpus([]string{"golang generics", "golang channels", "sourdough bread"})
+
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "identical", a: "golang channels", b: "golang channels", want: 1},
+		{name: "disjoint", a: "golang channels", b: "sourdough bread", want: 0},
+		{name: "empty", a: "", b: "golang", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(corpus.Weights(tt.a), corpus.Weights(tt.b))
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	partial := Similarity(corpus.Weights("golang generics"), corpus.Weights("golang channels"))
+	if partial <= 0 || partial >= 1 {
+		t.Errorf("Similarity() of overlapping notes = %v, want between 0 and 1", partial)
+	}
+}
@@ -0,0 +1,126 @@
+// Package languagetool is a client for LanguageTool-compatible spelling and
+// grammar checking services.
+package languagetool
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requestTimeout bounds a single check against the service.
+const requestTimeout = 30 * time.Second
+
+// Client calls the /v2/check endpoint and caches responses in memory.
+type Client struct {
+	baseURL  string
+	username string
+	apiKey   string
+	client   *http.Client
+
+	mu        sync.Mutex
+	cacheSize int
+	cache     map[string]*list.Element
+	order     *list.List // Most recently used first.
+}
+
+type cacheEntry struct {
+	key  string
+	resp json.RawMessage
+}
+
+// New creates a client for the service at baseURL, e.g.
+// "https://api.languagetool.org". username and apiKey are only needed for
+// premium accounts. Up to cacheSize responses are kept.
+func New(baseURL, username, apiKey string, cacheSize int) *Client {
+	return &Client{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		username:  username,
+		apiKey:    apiKey,
+		client:    &http.Client{Timeout: requestTimeout},
+		cacheSize: cacheSize,
+		cache:     make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// Cached returns the stored response for text in language, if any.
+func (c *Client) Cached(text, language string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.cache[cacheKey(text, language)]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).resp, true
+}
+
+// Check returns the service's JSON response for text in language ("auto"
+// lets the service detect it), from the cache when possible.
+func (c *Client) Check(ctx context.Context, text, language string) (json.RawMessage, error) {
+	if resp, ok := c.Cached(text, language); ok {
+		return resp, nil
+	}
+
+	form := url.Values{"text": {text}, "language": {language}}
+	if c.username != "" && c.apiKey != "" {
+		form.Set("username", c.username)
+		form.Set("apiKey", c.apiKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v2/check", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("check request failed: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	c.store(cacheKey(text, language), body)
+	return body, nil
+}
+
+func (c *Client) store(key string, resp json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.cache[key]; ok {
+		el.Value.(*cacheEntry).resp = resp
+		c.order.MoveToFront(el)
+		return
+	}
+	c.cache[key] = c.order.PushFront(&cacheEntry{key: key, resp: resp})
+	for c.order.Len() > c.cacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.cache, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cacheKey hashes the request so cached texts aren't kept in memory twice.
+func cacheKey(text, language string) string {
+	sum := sha256.Sum256([]byte(language + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
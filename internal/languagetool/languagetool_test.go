@@ -0,0 +1,68 @@
+package languagetool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/v2/check" || r.FormValue("text") == "" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("apiKey") != "" && r.FormValue("username") == "" {
+			http.Error(w, "api key without username", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"matches":[{"message":"Possible typo","offset":0,"length":4}],"language":{"code":"` + r.FormValue("language") + `"}}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL+"/", "user", "key", 2)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		text      string
+		language  string
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "first request goes upstream", text: "helo", language: "en-US", wantCalls: 1},
+		{name: "repeat is served from cache", text: "helo", language: "en-US", wantCalls: 1},
+		{name: "language is part of the key", text: "helo", language: "auto", wantCalls: 2},
+		{name: "third entry evicts the oldest", text: "wrld", language: "auto", wantCalls: 3},
+		{name: "evicted entry goes upstream again", text: "helo", language: "en-US", wantCalls: 4},
+		{name: "errors aren't cached", text: "", language: "auto", wantCalls: 5, wantErr: true},
+		{name: "errors are retried", text: "", language: "auto", wantCalls: 6, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := c.Check(ctx, tt.text, tt.language)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(resp) == 0 {
+				t.Error("Check() returned an empty response")
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("upstream calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+
+	if _, ok := c.Cached("wrld", "auto"); !ok {
+		t.Error("Cached() = false for a recent entry, want true")
+	}
+	if _, ok := c.Cached("helo", "auto"); ok {
+		t.Error("Cached() = true for an evicted entry, want false")
+	}
+}
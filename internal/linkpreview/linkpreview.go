@@ -0,0 +1,138 @@
+// Package linkpreview finds links in note text and reads the metadata used
+// to render them as rich cards.
+package linkpreview
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Preview is the card metadata of a page, mostly from its OpenGraph tags.
+type Preview struct {
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// ExtractURLs returns the distinct http(s) URLs in text, in order of first
+// appearance. Punctuation that usually ends a sentence or closes Markdown
+// link syntax is trimmed.
+func ExtractURLs(text string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, match := range urlPattern.FindAllString(text, -1) {
+		match = trimURL(match)
+		u, err := url.Parse(match)
+		if err != nil || u.Host == "" || seen[match] {
+			continue
+		}
+		seen[match] = true
+		urls = append(urls, match)
+	}
+	return urls
+}
+
+func trimURL(s string) string {
+	for {
+		trimmed := strings.TrimRight(s, ".,;:!?*_")
+		// Drop closing brackets only if unbalanced, so URLs like
+		// https://en.wikipedia.org/wiki/Go_(programming_language) survive.
+		for _, pair := range []string{"()", "[]"} {
+			if strings.HasSuffix(trimmed, pair[1:]) && strings.Count(trimmed, pair[:1]) < strings.Count(trimmed, pair[1:]) {
+				trimmed = trimmed[:len(trimmed)-1]
+			}
+		}
+		if trimmed == s {
+			return s
+		}
+		s = trimmed
+	}
+}
+
+// Parse reads preview metadata from an HTML page, falling back to the
+// <title> and description meta tags when OpenGraph tags are missing.
+// Relative image URLs are resolved against base.
+func Parse(page []byte, base *url.URL) (Preview, error) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return Preview{}, err
+	}
+
+	var p Preview
+	var title, description string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Body:
+				return // Metadata lives in the head.
+			case atom.Title:
+				if title == "" && n.FirstChild != nil {
+					title = n.FirstChild.Data
+				}
+			case atom.Meta:
+				content := strings.TrimSpace(attr(n, "content"))
+				switch key := attr(n, "property") + attr(n, "name"); key {
+				case "og:title":
+					p.Title = content
+				case "og:description":
+					p.Description = content
+				case "og:image":
+					p.ImageURL = content
+				case "og:site_name":
+					p.SiteName = content
+				case "description":
+					description = content
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if p.Title == "" {
+		p.Title = strings.Join(strings.Fields(title), " ")
+	}
+	if p.Description == "" {
+		p.Description = description
+	}
+	p.ImageURL = resolveHTTP(base, p.ImageURL)
+	return p, nil
+}
+
+// resolveHTTP makes ref absolute, returning "" unless it's an http(s) URL.
+func resolveHTTP(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
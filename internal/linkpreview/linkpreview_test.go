@@ -0,0 +1,70 @@
+package linkpreview
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestExtractURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "none", text: "no links here", want: nil},
+		{name: "sentence punctuation", text: "See https://go.dev/doc. Also http://example.com/a?b=c, ok?", want: []string{"https://go.dev/doc", "http://example.com/a?b=c"}},
+		{name: "markdown link", text: "[Go](https://go.dev/) and <https://pkg.go.dev>", want: []string{"https://go.dev/", "https://pkg.go.dev"}},
+		{name: "balanced parentheses", text: "(https://en.wikipedia.org/wiki/Go_(programming_language))", want: []string{"https://en.wikipedia.org/wiki/Go_(programming_language)"}},
+		{name: "duplicates", text: "https://go.dev https://go.dev", want: []string{"https://go.dev"}},
+		{name: "other schemes", text: "ftp://example.com javascript:alert(1) https://", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractURLs(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractURLs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/1")
+
+	tests := []struct {
+		name string
+		html string
+		want Preview
+	}{
+		{
+			name: "opengraph",
+			html: `<head><title>Ignored</title><meta property="og:title" content="Post"><meta property="og:description" content="About it">` +
+				`<meta property="og:image" content="/img.png"><meta property="og:site_name" content="Example"></head>`,
+			want: Preview{Title: "Post", Description: "About it", ImageURL: "https://example.com/img.png", SiteName: "Example"},
+		},
+		{
+			name: "fallbacks",
+			html: `<head><title>  Plain
+				title </title><meta name="description" content="Desc"></head><body><meta property="og:title" content="in body"></body>`,
+			want: Preview{Title: "Plain title", Description: "Desc"},
+		},
+		{
+			name: "unsafe image",
+			html: `<meta property="og:image" content="javascript:alert(1)">`,
+			want: Preview{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.html), base)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Package llm talks to large language model providers.
+package llm
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Provider completes a prompt given system instructions.
+type Provider interface {
+	Complete(ctx context.Context, system, prompt string) (string, error)
+	Model() string
+}
+
+// requestTimeout bounds a single completion; generation is much slower than embedding.
+const requestTimeout = 2 * time.Minute
+
+// FromEnv builds the Provider selected by LLM_PROVIDER ("openai" for any
+// OpenAI-compatible endpoint, or "ollama" for a local model). LLM features
+// are off by default, in which case nil is returned.
+func FromEnv(getenv func(string) string) (Provider, error) {
+	switch provider := getenv("LLM_PROVIDER"); provider {
+	case "":
+		return nil, nil
+	case "openai":
+		return NewOpenAI(
+			cmp.Or(getenv("LLM_URL"), "https://api.openai.com/v1"),
+			getenv("LLM_API_KEY"),
+			cmp.Or(getenv("LLM_MODEL"), "gpt-4o-mini"),
+		), nil
+	case "ollama":
+		return NewOllama(
+			cmp.Or(getenv("LLM_URL"), "http://127.0.0.1:11434"),
+			cmp.Or(getenv("LLM_MODEL"), "llama3.2"),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM_PROVIDER %q", provider)
+	}
+}
+
+// summarizePrompt keeps summaries short enough to show in a list of notes.
+const summarizePrompt = "Summarize the user's note in at most three sentences. " +
+	"Reply with the summary only, in the language of the note."
+
+// Summarize asks p for a short summary of text.
+func Summarize(ctx context.Context, p Provider, text string) (string, error) {
+	summary, err := p.Complete(ctx, summarizePrompt, text)
+	if err != nil {
+		return "", err
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", fmt.Errorf("%s returned an empty summary", p.Model())
+	}
+	return summary, nil
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
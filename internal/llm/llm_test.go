@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantNil   bool
+		wantModel string
+		wantErr   bool
+	}{
+		{name: "disabled by default", env: map[string]string{}, wantNil: true},
+		{name: "unknown provider", env: map[string]string{"LLM_PROVIDER": "oracle"}, wantErr: true},
+		{name: "openai default model", env: map[string]string{"LLM_PROVIDER": "openai"}, wantModel: "gpt-4o-mini"},
+		{name: "ollama custom model", env: map[string]string{"LLM_PROVIDER": "ollama", "LLM_MODEL": "mistral"}, wantModel: "mistral"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := FromEnv(func(k string) string { return tt.env[k] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (p == nil) != tt.wantNil {
+				t.Fatalf("FromEnv() = %v, wantNil %v", p, tt.wantNil)
+			}
+			if p != nil && p.Model() != tt.wantModel {
+				t.Errorf("Model() = %q, want %q", p.Model(), tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []message `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Messages) != 2 || body.Messages[1].Content == "" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		reply := "  A short summary.\n"
+		if body.Messages[1].Content == "empty" {
+			reply = " "
+		}
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"choices": []map[string]interface{}{{"message": message{Role: "assistant", Content: reply}}},
+			})
+		case "/api/chat":
+			json.NewEncoder(w).Encode(map[string]interface{}{"message": message{Role: "assistant", Content: reply}})
+		default:
+			io.WriteString(w, "{}")
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		provider Provider
+		text     string
+		want     string
+		wantErr  bool
+	}{
+		{name: "openai", provider: NewOpenAI(srv.URL+"/v1", "secret", "m"), text: "note", want: "A short summary."},
+		{name: "openai unauthorized", provider: NewOpenAI(srv.URL+"/v1", "", "m"), text: "note", wantErr: true},
+		{name: "ollama", provider: NewOllama(srv.URL+"/", "m"), text: "note", want: "A short summary."},
+		{name: "empty summary", provider: NewOllama(srv.URL, "m"), text: "empty", wantErr: true},
+		{name: "no choices", provider: NewOpenAI(srv.URL+"/other", "secret", "m"), text: "note", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Summarize(context.Background(), tt.provider, tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Summarize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Summarize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
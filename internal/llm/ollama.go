@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/httpjson"
+)
+
+// Ollama completes prompts with a model served by a local Ollama instance.
+type Ollama struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+func NewOllama(baseURL, model string) *Ollama {
+	return &Ollama{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (o *Ollama) Model() string {
+	return o.model
+}
+
+func (o *Ollama) Complete(ctx context.Context, system, prompt string) (string, error) {
+	var resp struct {
+		Message message `json:"message"`
+	}
+	err := httpjson.Post(ctx, o.client, o.baseURL+"/api/chat", nil, map[string]interface{}{
+		"model": o.model,
+		"messages": []message{
+			{Role: "system", Content: system},
+			{Role: "user", Content: prompt},
+		},
+		"stream": false,
+	}, &resp)
+	if err != nil {
+		return "", fmt.Errorf("completion request failed: %w", err)
+	}
+	return resp.Message.Content, nil
+}
@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/httpjson"
+)
+
+// OpenAI calls the /chat/completions endpoint of the OpenAI API or any
+// server implementing the same interface.
+type OpenAI struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAI creates an OpenAI-compatible provider. apiKey may be empty for
+// servers that don't require one.
+func NewOpenAI(baseURL, apiKey, model string) *OpenAI {
+	return &OpenAI{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (o *OpenAI) Model() string {
+	return o.model
+}
+
+func (o *OpenAI) Complete(ctx context.Context, system, prompt string) (string, error) {
+	header := http.Header{}
+	if o.apiKey != "" {
+		header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	var resp struct {
+		Choices []struct {
+			Message message `json:"message"`
+		} `json:"choices"`
+	}
+	err := httpjson.Post(ctx, o.client, o.baseURL+"/chat/completions", header, map[string]interface{}{
+		"model": o.model,
+		"messages": []message{
+			{Role: "system", Content: system},
+			{Role: "user", Content: prompt},
+		},
+	}, &resp)
+	if err != nil {
+		return "", fmt.Errorf("completion request failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("completion response contained no choices")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
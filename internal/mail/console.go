@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"context"
+	"log"
+)
+
+// Console logs email instead of sending it, for development.
+type Console struct {
+	from string
+}
+
+func NewConsole(from string) *Console {
+	return &Console{from: from}
+}
+
+func (c *Console) Name() string {
+	return "console"
+}
+
+func (c *Console) Send(ctx context.Context, msg Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+	log.Printf("Email from %s to %s: %s\n%s", c.from, msg.To, msg.Subject, msg.Text)
+	return nil
+}
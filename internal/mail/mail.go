@@ -0,0 +1,123 @@
+// Package mail sends email through SMTP or a provider's API, or logs it
+// during development.
+package mail
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Message is an email to send. Text is required; HTML, if set, is sent as
+// an alternative that mail clients show instead.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends email from the address it was configured with.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+	Name() string
+}
+
+// requestTimeout bounds a single send.
+const requestTimeout = 30 * time.Second
+
+// FromEnv builds the Mailer selected by MAIL_PROVIDER ("console", "smtp",
+// "sendgrid" or "ses"). Email is off by default, in which case nil is
+// returned. Every provider but console needs MAIL_FROM, the sender address.
+func FromEnv(getenv func(string) string) (Mailer, error) {
+	provider := getenv("MAIL_PROVIDER")
+	switch provider {
+	case "":
+		return nil, nil
+	case "console":
+		return NewConsole(cmp.Or(getenv("MAIL_FROM"), "notely@localhost")), nil
+	}
+
+	from := getenv("MAIL_FROM")
+	if from == "" {
+		return nil, fmt.Errorf("MAIL_FROM is required for the %s provider", provider)
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("MAIL_FROM: %w", err)
+	}
+	switch provider {
+	case "smtp":
+		if getenv("SMTP_HOST") == "" {
+			return nil, errors.New("SMTP_HOST is required for the smtp provider")
+		}
+		return NewSMTP(getenv("SMTP_HOST"), cmp.Or(getenv("SMTP_PORT"), "587"), getenv("SMTP_USERNAME"), getenv("SMTP_PASSWORD"), from), nil
+	case "sendgrid":
+		if getenv("SENDGRID_API_KEY") == "" {
+			return nil, errors.New("SENDGRID_API_KEY is required for the sendgrid provider")
+		}
+		return NewSendGrid(cmp.Or(getenv("SENDGRID_URL"), "https://api.sendgrid.com"), getenv("SENDGRID_API_KEY"), from), nil
+	case "ses":
+		region := getenv("AWS_REGION")
+		creds := Credentials{
+			AccessKeyID:     getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    getenv("AWS_SESSION_TOKEN"),
+		}
+		if region == "" || creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return nil, errors.New("AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for the ses provider")
+		}
+		return NewSES(cmp.Or(getenv("SES_URL"), "https://email."+region+".amazonaws.com"), region, creds, from), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_PROVIDER %q", provider)
+	}
+}
+
+// validate checks what every mailer needs of a message. Line breaks in the
+// recipient or subject would let them add headers of their own.
+func validate(msg Message) error {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return errors.New("recipient and subject must be on one line")
+	}
+	if msg.Text == "" {
+		return errors.New("message has no text")
+	}
+	return nil
+}
+
+// send sends req and fails unless the response is a 2xx.
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sending email failed: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func newJSONRequest(ctx context.Context, url string, body any) (*http.Request, []byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, data, nil
+}
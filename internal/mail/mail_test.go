@@ -0,0 +1,213 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	ses := map[string]string{"MAIL_PROVIDER": "ses", "MAIL_FROM": "a@example.com", "AWS_REGION": "eu-west-1", "AWS_ACCESS_KEY_ID": "id", "AWS_SECRET_ACCESS_KEY": "secret"}
+	tests := []struct {
+		name     string
+		env      map[string]string
+		wantNil  bool
+		wantName string
+		wantErr  bool
+	}{
+		{name: "disabled by default", env: map[string]string{}, wantNil: true},
+		{name: "unknown provider", env: map[string]string{"MAIL_PROVIDER": "pigeon", "MAIL_FROM": "a@example.com"}, wantErr: true},
+		{name: "console needs no sender", env: map[string]string{"MAIL_PROVIDER": "console"}, wantName: "console"},
+		{name: "smtp without sender", env: map[string]string{"MAIL_PROVIDER": "smtp", "SMTP_HOST": "mail"}, wantErr: true},
+		{name: "smtp with a bad sender", env: map[string]string{"MAIL_PROVIDER": "smtp", "SMTP_HOST": "mail", "MAIL_FROM": "not an address"}, wantErr: true},
+		{name: "smtp without host", env: map[string]string{"MAIL_PROVIDER": "smtp", "MAIL_FROM": "a@example.com"}, wantErr: true},
+		{name: "smtp", env: map[string]string{"MAIL_PROVIDER": "smtp", "SMTP_HOST": "mail", "MAIL_FROM": "Notely <a@example.com>"}, wantName: "smtp"},
+		{name: "sendgrid without key", env: map[string]string{"MAIL_PROVIDER": "sendgrid", "MAIL_FROM": "a@example.com"}, wantErr: true},
+		{name: "sendgrid", env: map[string]string{"MAIL_PROVIDER": "sendgrid", "MAIL_FROM": "a@example.com", "SENDGRID_API_KEY": "k"}, wantName: "sendgrid"},
+		{name: "ses without region", env: map[string]string{"MAIL_PROVIDER": "ses", "MAIL_FROM": "a@example.com", "AWS_ACCESS_KEY_ID": "id", "AWS_SECRET_ACCESS_KEY": "secret"}, wantErr: true},
+		{name: "ses", env: ses, wantName: "ses"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := FromEnv(func(k string) string { return tt.env[k] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (m == nil) != tt.wantNil {
+				t.Fatalf("FromEnv() = %v, wantNil %v", m, tt.wantNil)
+			}
+			if m != nil && m.Name() != tt.wantName {
+				t.Errorf("Name() = %q, want %q", m.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/mail/send":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+		case "/v2/email/outbound-emails":
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=id/") {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	msg := Message{To: "b@example.com", Subject: "Hi", Text: "Hello", HTML: "<p>Hello</p>"}
+	tests := []struct {
+		name    string
+		mailer  Mailer
+		msg     Message
+		wantErr bool
+	}{
+		{name: "sendgrid", mailer: NewSendGrid(srv.URL, "secret", "Notely <a@example.com>"), msg: msg},
+		{name: "sendgrid wrong key", mailer: NewSendGrid(srv.URL, "wrong", "a@example.com"), msg: msg, wantErr: true},
+		{name: "ses", mailer: NewSES(srv.URL, "eu-west-1", Credentials{AccessKeyID: "id", SecretAccessKey: "secret"}, "a@example.com"), msg: msg},
+		{name: "console", mailer: NewConsole("a@example.com"), msg: msg},
+		{name: "bad recipient", mailer: NewConsole("a@example.com"), msg: Message{To: "nobody", Text: "x"}, wantErr: true},
+		{name: "header injection", mailer: NewConsole("a@example.com"), msg: Message{To: "b@example.com", Subject: "Hi\r\nBcc: c@example.com", Text: "x"}, wantErr: true},
+		{name: "no text", mailer: NewConsole("a@example.com"), msg: Message{To: "b@example.com", Subject: "Hi"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			err := tt.mailer.Send(context.Background(), tt.msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.name != "console" && !strings.Contains(toJSON(got), "b@example.com") {
+				t.Errorf("request %s doesn't name the recipient", toJSON(got))
+			}
+		})
+	}
+}
+
+func toJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// TestSignV4 checks the signer against the get-vanilla case of the AWS
+// Signature Version 4 test suite.
+func TestSignV4(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	creds := Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signV4(req, nil, "service", "us-east-1", creds, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		msg   Message
+		want  []string
+		avoid []string
+	}{
+		{
+			name:  "text only",
+			msg:   Message{To: "b@example.com", Subject: "Hi", Text: "Hello"},
+			want:  []string{"To: b@example.com\r\n", "Subject: Hi\r\n", "Content-Type: text/plain; charset=utf-8", "\r\n\r\nHello"},
+			avoid: []string{"multipart"},
+		},
+		{
+			name: "with HTML",
+			msg:  Message{To: "b@example.com", Subject: "Grüße", Text: "Hello", HTML: "<p>Hello</p>"},
+			want: []string{"Subject: =?utf-8?q?Gr=C3=BC=C3=9Fe?=\r\n", "multipart/alternative", "text/plain", "text/html", "<p>Hello</p>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := buildMessage("a@example.com", tt.msg, date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(string(data), s) {
+					t.Errorf("message lacks %q:\n%s", s, data)
+				}
+			}
+			for _, s := range tt.avoid {
+				if strings.Contains(string(data), s) {
+					t.Errorf("message has %q:\n%s", s, data)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range templates.Names() {
+		if _, ok := PreviewData[name]; !ok {
+			t.Errorf("no PreviewData for email %q", name)
+		}
+	}
+
+	data := TestEmail{Name: "<b>Ada</b>", Provider: "smtp"}
+	tests := []struct {
+		name        string
+		email       string
+		locale      string
+		wantSubject string
+		wantLang    string
+		wantErr     bool
+	}{
+		{name: "default", email: "test", locale: "", wantSubject: "Test email from Notely", wantLang: `lang="en"`},
+		{name: "translated", email: "test", locale: "de", wantSubject: "Test-E-Mail von Notely", wantLang: `lang="de"`},
+		{name: "region falls back to language", email: "test", locale: "de_AT", wantSubject: "Test-E-Mail von Notely", wantLang: `lang="de"`},
+		{name: "untranslated falls back to default", email: "test", locale: "fr", wantSubject: "Test email from Notely", wantLang: `lang="en"`},
+		{name: "unknown email", email: "nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := templates.Render(tt.email, tt.locale, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if msg.Subject != tt.wantSubject {
+				t.Errorf("Subject = %q, want %q", msg.Subject, tt.wantSubject)
+			}
+			if !strings.HasPrefix(msg.Text, "H") || !strings.Contains(msg.Text, "<b>Ada</b>") {
+				t.Errorf("Text = %q, want the unescaped name without a leading blank line", msg.Text)
+			}
+			if !strings.Contains(msg.HTML, tt.wantLang) || !strings.Contains(msg.HTML, "&lt;b&gt;Ada&lt;/b&gt;") {
+				t.Errorf("HTML = %q, want %s and the escaped name", msg.HTML, tt.wantLang)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package mail
+
+import (
+	"context"
+	"net/http"
+	"net/mail"
+	"strings"
+)
+
+// SendGrid sends email through the SendGrid v3 API.
+type SendGrid struct {
+	baseURL string
+	apiKey  string
+	from    string
+	client  *http.Client
+}
+
+func NewSendGrid(baseURL, apiKey, from string) *SendGrid {
+	return &SendGrid{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		from:    from,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (s *SendGrid) Name() string {
+	return "sendgrid"
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (s *SendGrid) Send(ctx context.Context, msg Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+	// The plain text part has to come first.
+	content := []sendGridContent{{Type: "text/plain", Value: msg.Text}}
+	if msg.HTML != "" {
+		content = append(content, sendGridContent{Type: "text/html", Value: msg.HTML})
+	}
+	req, _, err := newJSONRequest(ctx, s.baseURL+"/v3/mail/send", map[string]any{
+		"personalizations": []map[string]any{{"to": []sendGridAddress{parseAddress(msg.To)}}},
+		"from":             parseAddress(s.from),
+		"subject":          msg.Subject,
+		"content":          content,
+	})
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	return send(s.client, req)
+}
+
+// parseAddress splits an address that has been validated, such as
+// "Notely <notely@example.com>", into its parts.
+func parseAddress(address string) sendGridAddress {
+	a, err := mail.ParseAddress(address)
+	if err != nil {
+		return sendGridAddress{Email: address}
+	}
+	return sendGridAddress{Email: a.Address, Name: a.Name}
+}
@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Credentials are AWS access keys. SessionToken is only set for temporary
+// credentials.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// SES sends email through the Amazon SES v2 API.
+type SES struct {
+	baseURL string
+	region  string
+	creds   Credentials
+	from    string
+	client  *http.Client
+	now     func() time.Time // Signing time; replaced in tests.
+}
+
+func NewSES(baseURL, region string, creds Credentials, from string) *SES {
+	return &SES{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		region:  region,
+		creds:   creds,
+		from:    from,
+		client:  &http.Client{Timeout: requestTimeout},
+		now:     time.Now,
+	}
+}
+
+func (s *SES) Name() string {
+	return "ses"
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+func (s *SES) Send(ctx context.Context, msg Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+	body := map[string]sesContent{"Text": {Data: msg.Text, Charset: "UTF-8"}}
+	if msg.HTML != "" {
+		body["Html"] = sesContent{Data: msg.HTML, Charset: "UTF-8"}
+	}
+	req, payload, err := newJSONRequest(ctx, s.baseURL+"/v2/email/outbound-emails", map[string]any{
+		"FromEmailAddress": s.from,
+		"Destination":      map[string]any{"ToAddresses": []string{msg.To}},
+		"Content": map[string]any{
+			"Simple": map[string]any{
+				"Subject": sesContent{Data: msg.Subject, Charset: "UTF-8"},
+				"Body":    body,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	signV4(req, payload, "ses", s.region, s.creds, s.now())
+	return send(s.client, req)
+}
+
+// signV4 adds an AWS Signature Version 4 Authorization header to req, whose
+// body is payload. Only the host and X-Amz-* headers are signed.
+func signV4(req *http.Request, payload []byte, service, region string, creds Credentials, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		if name := strings.ToLower(name); strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
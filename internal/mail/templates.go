@@ -0,0 +1,154 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Email templates, embedded in the binary. Each email is a NAME.txt with the
+// plain text body and an optional NAME.html, both defining a "subject"
+// template; the HTML body goes in a "content" template that layout.html
+// wraps. Translations sit next to them as NAME.LOCALE.txt and
+// NAME.LOCALE.html, e.g. test.de.txt.
+//
+//go:embed templates/*
+var templateFiles embed.FS
+
+// defaultLang is the language of the templates without a locale.
+const defaultLang = "en"
+
+// Templates renders the embedded email templates into messages.
+type Templates struct {
+	text map[string]*texttemplate.Template // By NAME or NAME.LOCALE.
+	html map[string]*htmltemplate.Template
+}
+
+// LoadTemplates parses the embedded templates.
+func LoadTemplates() (*Templates, error) {
+	layout, err := htmltemplate.ParseFS(templateFiles, "templates/layout.html")
+	if err != nil {
+		return nil, err
+	}
+	t := &Templates{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+	files, err := fs.Glob(templateFiles, "templates/*")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		base := path.Base(file)
+		key, ext := strings.TrimSuffix(base, path.Ext(base)), path.Ext(base)
+		switch {
+		case base == "layout.html":
+		case ext == ".txt":
+			tmpl, err := texttemplate.ParseFS(templateFiles, file)
+			if err != nil {
+				return nil, err
+			}
+			t.text[key] = tmpl
+		case ext == ".html":
+			tmpl, err := htmltemplate.Must(layout.Clone()).ParseFS(templateFiles, file)
+			if err != nil {
+				return nil, err
+			}
+			t.html[key] = tmpl
+		default:
+			return nil, fmt.Errorf("unexpected email template file %s", base)
+		}
+	}
+	for key := range t.html {
+		if t.text[key] == nil {
+			return nil, fmt.Errorf("email template %s.html has no %s.txt", key, key)
+		}
+	}
+	return t, nil
+}
+
+// Names lists the emails there are templates for, without translations.
+func (t *Templates) Names() []string {
+	var names []string
+	for key := range t.text {
+		if !strings.Contains(key, ".") {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render fills in the template for the email name with data, in the
+// language of locale if there's a translation. Locales such as "de-AT"
+// fall back to "de", then to the untranslated template. The message's
+// recipient is left to the caller.
+func (t *Templates) Render(name, locale string, data any) (Message, error) {
+	key, lang := t.lookup(name, locale)
+	text := t.text[key]
+	if text == nil {
+		return Message{}, fmt.Errorf("no email template %q", name)
+	}
+
+	var msg Message
+	var buf bytes.Buffer
+	if err := text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return Message{}, err
+	}
+	// A subject must be on one line, however the template spans it.
+	msg.Subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := text.Execute(&buf, data); err != nil {
+		return Message{}, err
+	}
+	msg.Text = strings.TrimSpace(buf.String()) + "\n"
+
+	if html := t.html[key]; html != nil {
+		buf.Reset()
+		err := html.ExecuteTemplate(&buf, "layout", struct {
+			Lang string
+			Data any
+		}{lang, data})
+		if err != nil {
+			return Message{}, err
+		}
+		msg.HTML = buf.String()
+	}
+	return msg, nil
+}
+
+// lookup returns the most specific template key for name in locale and
+// the language it's written in.
+func (t *Templates) lookup(name, locale string) (string, string) {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	for locale != "" {
+		if t.text[name+"."+locale] != nil {
+			return name + "." + locale, locale
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return name, defaultLang
+}
+
+// TestEmail is the data of the "test" email, sent to check that email is
+// set up.
+type TestEmail struct {
+	Name     string // Who the email is addressed to.
+	Provider string // Name of the mailer that sent it.
+}
+
+// PreviewData is sample data for each email, to preview its templates with.
+var PreviewData = map[string]any{
+	"test": TestEmail{Name: "Ada", Provider: "console"},
+}
@@ -0,0 +1,188 @@
+// Package mcp implements the tool-serving subset of the Model Context Protocol
+// over its streamable HTTP transport (plain JSON responses, no SSE streams).
+// internal/mcp/mcp.go:
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+)
+
+// ProtocolVersion is the MCP revision this server implements.
+const ProtocolVersion = "2025-03-26"
+
+// maxMessageBytes bounds the size of a single JSON-RPC message.
+const maxMessageBytes = 1 << 20
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a callable exposed to MCP clients. Handler receives the raw
+// "arguments" object; its result is returned to the client as JSON text.
+// So is the text of its errors, which mustn't reveal internals.
+type Tool struct {
+	Name        string                                                               `json:"name"`
+	Description string                                                               `json:"description"`
+	InputSchema json.RawMessage                                                      `json:"inputSchema"`
+	Handler     func(ctx context.Context, args json.RawMessage) (interface{}, error) `json:"-"`
+}
+
+// Server dispatches JSON-RPC messages to its tools.
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+}
+
+func NewServer(name, version string, tools ...Tool) *Server {
+	return &Server{name: name, version: version, tools: tools}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError"`
+}
+
+// ServeHTTP handles one POSTed JSON-RPC message. Notifications are
+// acknowledged with 202 and no body, as the transport requires.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageBytes))
+	if err != nil {
+		http.Error(w, "couldn't read body", http.StatusBadRequest)
+		return
+	}
+
+	resp, ok := s.Handle(r.Context(), body)
+	if !ok {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// Handle processes a single message and returns the encoded response, or
+// false if the message was a notification that gets no response.
+func (s *Server) Handle(ctx context.Context, msg []byte) ([]byte, bool) {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return encode(response{ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: "parse error"}}), true
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return encode(response{ID: idOrNull(req.ID), Error: &rpcError{Code: codeInvalidRequest, Message: "invalid request"}}), true
+	}
+	if len(req.ID) == 0 {
+		// Notifications such as notifications/initialized need no handling.
+		return nil, false
+	}
+
+	result, rpcErr := s.dispatch(ctx, req)
+	return encode(response{ID: req.ID, Result: result, Error: rpcErr}), true
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, *rpcError) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "invalid params"}
+	}
+	if len(call.Arguments) == 0 {
+		call.Arguments = json.RawMessage("{}")
+	}
+
+	for _, tool := range s.tools {
+		if tool.Name != call.Name {
+			continue
+		}
+		// Tool failures are results the model can read, not protocol errors.
+		out, err := tool.Handler(ctx, call.Arguments)
+		if err != nil {
+			return callResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+		}
+		text, err := json.Marshal(out)
+		if err != nil {
+			return callResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+		}
+		return callResult{Content: []textContent{{Type: "text", Text: string(text)}}}, nil
+	}
+	return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + call.Name}
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func encode(resp response) []byte {
+	resp.JSONRPC = "2.0"
+	data, err := json.Marshal(resp)
+	if err != nil {
+		// Only tool results can fail to marshal, and those are encoded in callTool.
+		log.Printf("Error marshalling JSON: %s", err)
+		return []byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32603,"message":"internal error"}}`)
+	}
+	return data
+}
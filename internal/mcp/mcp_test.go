@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer() *Server {
+	return NewServer("test", "0.0.1",
+		Tool{
+			Name:        "echo",
+			Description: "Echoes its input.",
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+			Handler: func(_ context.Context, args json.RawMessage) (interface{}, error) {
+				return args, nil
+			},
+		},
+		Tool{
+			Name:        "fail",
+			Description: "Always fails.",
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+			Handler: func(context.Context, json.RawMessage) (interface{}, error) {
+				return nil, errors.New("boom")
+			},
+		},
+	)
+}
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        string
+		wantNoResp bool
+		wantInResp string
+	}{
+		{name: "initialize", msg: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`, wantInResp: `"protocolVersion":"` + ProtocolVersion + `"`},
+		{name: "notification", msg: `{"jsonrpc":"2.0","method":"notifications/initialized"}`, wantNoResp: true},
+		{name: "list tools", msg: `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`, wantInResp: `"name":"echo"`},
+		{name: "call tool", msg: `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"a":1}}}`, wantInResp: `"text":"{\"a\":1}"`},
+		{name: "tool error is a result", msg: `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"fail"}}`, wantInResp: `"isError":true`},
+		{name: "unknown tool", msg: `{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"nope"}}`, wantInResp: `"code":-32602`},
+		{name: "unknown method", msg: `{"jsonrpc":"2.0","id":6,"method":"nope"}`, wantInResp: `"code":-32601`},
+		{name: "parse error", msg: `{`, wantInResp: `"code":-32700`},
+		{name: "wrong version", msg: `{"jsonrpc":"1.0","id":7,"method":"ping"}`, wantInResp: `"code":-32600`},
+	}
+
+	s := newTestServer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, ok := s.Handle(context.Background(), []byte(tt.msg))
+			if ok == tt.wantNoResp {
+				t.Fatalf("Handle() responded = %v, want %v", ok, !tt.wantNoResp)
+			}
+			if !strings.Contains(string(resp), tt.wantInResp) {
+				t.Errorf("Handle() = %s, want it to contain %s", resp, tt.wantInResp)
+			}
+		})
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	s := newTestServer()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("notification status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,112 @@
+// Package metering counts what each tenant of a hosted deployment uses, per
+// day, so it can be exported per billing period to an external billing
+// system.
+// internal/metering/metering.go:
+package metering
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+)
+
+// dayLayout is how days are stored; they sort as strings.
+const dayLayout = "2006-01-02"
+
+// periodLayout names a billing period, a calendar month in UTC.
+const periodLayout = "2006-01"
+
+// ErrInvalidPeriod is returned by ParsePeriod for anything but YYYY-MM.
+var ErrInvalidPeriod = errors.New("period must be a month as YYYY-MM")
+
+// Calls is the number of API calls a tenant made on a day (UTC, as
+// YYYY-MM-DD). The default database is tenant "".
+type Calls struct {
+	Tenant string
+	Day    string
+	Calls  int64
+}
+
+// Counter counts API calls in memory until they're taken to be written to
+// the database. A nil *Counter ignores them, so callers needn't check
+// whether metering is on.
+type Counter struct {
+	clock clock.Clock
+
+	mu     sync.Mutex
+	counts map[Calls]int64 // Keyed by tenant and day, with Calls left 0.
+}
+
+func NewCounter(clock clock.Clock) *Counter {
+	return &Counter{clock: clock, counts: make(map[Calls]int64)}
+}
+
+// Record counts an API call by tenant.
+func (c *Counter) Record(tenant string) {
+	if c == nil {
+		return
+	}
+	day := c.clock.Now().UTC().Format(dayLayout)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[Calls{Tenant: tenant, Day: day}]++
+}
+
+// Take removes and returns the counted calls.
+func (c *Counter) Take() []Calls {
+	c.mu.Lock()
+	counts := c.counts
+	c.counts = make(map[Calls]int64)
+	c.mu.Unlock()
+
+	calls := make([]Calls, 0, len(counts))
+	for key, n := range counts {
+		key.Calls = n
+		calls = append(calls, key)
+	}
+	return calls
+}
+
+// Restore puts back calls that couldn't be written, to be tried again.
+func (c *Counter) Restore(calls []Calls) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, call := range calls {
+		n := call.Calls
+		call.Calls = 0
+		c.counts[call] += n
+	}
+}
+
+// Today returns the day usage measured at now is counted towards.
+func Today(now time.Time) string {
+	return now.UTC().Format(dayLayout)
+}
+
+// Period is a billing period: the days from Start up to but not including
+// End, both as YYYY-MM-DD.
+type Period struct {
+	Name  string
+	Start string
+	End   string
+}
+
+// ParsePeriod returns the calendar month named by name, e.g. "2024-03", or
+// the one now is in if name is empty.
+func ParsePeriod(name string, now time.Time) (Period, error) {
+	start := time.Date(now.UTC().Year(), now.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	if name != "" {
+		var err error
+		start, err = time.Parse(periodLayout, name)
+		if err != nil {
+			return Period{}, ErrInvalidPeriod
+		}
+	}
+	return Period{
+		Name:  start.Format(periodLayout),
+		Start: start.Format(dayLayout),
+		End:   start.AddDate(0, 1, 0).Format(dayLayout),
+	}, nil
+}
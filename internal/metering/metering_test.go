@@ -0,0 +1,81 @@
+package metering
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+)
+
+func sortedCalls(calls []Calls) []Calls {
+	slices.SortFunc(calls, func(a, b Calls) int {
+		return strings.Compare(a.Tenant+"/"+a.Day, b.Tenant+"/"+b.Day)
+	})
+	return calls
+}
+
+func TestCounter(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC))
+	c := NewCounter(fake)
+
+	c.Record("")
+	c.Record("acme")
+	c.Record("acme")
+	fake.Advance(2 * time.Minute)
+	c.Record("acme")
+
+	want := []Calls{
+		{Tenant: "", Day: "2024-01-31", Calls: 1},
+		{Tenant: "acme", Day: "2024-01-31", Calls: 2},
+		{Tenant: "acme", Day: "2024-02-01", Calls: 1},
+	}
+	got := sortedCalls(c.Take())
+	if !slices.Equal(got, want) {
+		t.Fatalf("Take() = %v, want %v", got, want)
+	}
+	if again := c.Take(); len(again) != 0 {
+		t.Fatalf("second Take() = %v, want nothing", again)
+	}
+
+	// Restored calls are merged with those counted since.
+	c.Record("acme")
+	c.Restore(got)
+	want[2].Calls = 2
+	if got := sortedCalls(c.Take()); !slices.Equal(got, want) {
+		t.Errorf("Take() after Restore() = %v, want %v", got, want)
+	}
+}
+
+func TestNilCounter(t *testing.T) {
+	var c *Counter
+	c.Record("acme") // Mustn't panic.
+}
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		want    Period
+		wantErr bool
+	}{
+		{name: "", want: Period{Name: "2024-02", Start: "2024-02-01", End: "2024-03-01"}},
+		{name: "2023-12", want: Period{Name: "2023-12", Start: "2023-12-01", End: "2024-01-01"}},
+		{name: "2024-13", wantErr: true},
+		{name: "2024-02-01", wantErr: true},
+		{name: "February", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePeriod(tt.name, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriod(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePeriod(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
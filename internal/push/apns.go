@@ -0,0 +1,154 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+
+	// apnsTokenLifetime is how long a provider token is reused. Apple
+	// rejects tokens older than an hour and throttles refreshing more often
+	// than every 20 minutes.
+	apnsTokenLifetime = 45 * time.Minute
+)
+
+// APNs sends through Apple's HTTP/2 provider API, authenticating with a
+// token signing key (.p8) rather than a certificate.
+type APNs struct {
+	baseURL string
+	key     *ecdsa.PrivateKey
+	keyID   string
+	teamID  string
+	topic   string
+	client  *http.Client
+
+	mu     sync.Mutex
+	jwt    string
+	issued time.Time
+}
+
+// NewAPNs builds an APNs sender. topic is the app's bundle ID.
+func NewAPNs(baseURL string, signingKey []byte, keyID, teamID, topic string) (*APNs, error) {
+	block, _ := pem.Decode(signingKey)
+	if block == nil {
+		return nil, errors.New("signing key isn't PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("signing key isn't an ECDSA key")
+	}
+	return &APNs{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		key:     key,
+		keyID:   keyID,
+		teamID:  teamID,
+		topic:   topic,
+		client:  &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func (a *APNs) Send(ctx context.Context, token string, msg Message) error {
+	jwt, err := a.token()
+	if err != nil {
+		return fmt.Errorf("apns: signing token: %w", err)
+	}
+
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+		},
+	}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+jwt)
+	req.Header.Set("apns-topic", a.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(data, &apnsErr)
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		apnsErr.Reason == "BadDeviceToken",
+		apnsErr.Reason == "DeviceTokenNotForTopic":
+		return ErrUnregistered
+	}
+	return fmt.Errorf("apns: send failed: %s: %s", resp.Status, bytes.TrimSpace(data))
+}
+
+// token returns the current provider token, signing a new one once it's
+// older than apnsTokenLifetime.
+func (a *APNs) token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	if a.jwt != "" && now.Sub(a.issued) < apnsTokenLifetime {
+		return a.jwt, nil
+	}
+	jwt, err := signJWT(
+		map[string]string{"alg": "ES256", "kid": a.keyID},
+		map[string]interface{}{"iss": a.teamID, "iat": now.Unix()},
+		a.key, ecdsaRawSignature,
+	)
+	if err != nil {
+		return "", err
+	}
+	a.jwt, a.issued = jwt, now
+	return jwt, nil
+}
+
+// ecdsaRawSignature converts an ASN.1 ECDSA signature into the fixed-size
+// r||s form JWS requires for ES256.
+func ecdsaRawSignature(sig []byte) ([]byte, error) {
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 64)
+	rs.R.FillBytes(out[:32])
+	rs.S.FillBytes(out[32:])
+	return out, nil
+}
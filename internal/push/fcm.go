@@ -0,0 +1,183 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmBaseURL = "https://fcm.googleapis.com"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// FCM sends through the Firebase Cloud Messaging HTTP v1 API, authenticating
+// as a service account.
+type FCM struct {
+	baseURL     string
+	projectID   string
+	clientEmail string
+	tokenURL    string
+	key         *rsa.PrivateKey
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expires     time.Time
+}
+
+// NewFCM builds an FCM sender from a service account key file as downloaded
+// from the Firebase console.
+func NewFCM(credentials []byte) (*FCM, error) {
+	var sa struct {
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(credentials, &sa); err != nil {
+		return nil, err
+	}
+	if sa.ProjectID == "" || sa.ClientEmail == "" || sa.TokenURI == "" {
+		return nil, errors.New("service account key lacks project_id, client_email or token_uri")
+	}
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if block == nil {
+		return nil, errors.New("service account key has no PEM private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key isn't an RSA key")
+	}
+	return &FCM{
+		baseURL:     fcmBaseURL,
+		projectID:   sa.ProjectID,
+		clientEmail: sa.ClientEmail,
+		tokenURL:    sa.TokenURI,
+		key:         key,
+		client:      &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func (f *FCM) Send(ctx context.Context, token string, msg Message) error {
+	accessToken, err := f.token(ctx)
+	if err != nil {
+		return fmt.Errorf("fcm: getting access token: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token":        token,
+			"notification": map[string]string{"title": msg.Title, "body": msg.Body},
+			"data":         msg.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+"/v1/projects/"+f.projectID+"/messages:send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var fcmErr struct {
+		Error struct {
+			Status  string `json:"status"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &fcmErr) == nil {
+		for _, detail := range fcmErr.Error.Details {
+			if detail.ErrorCode == "UNREGISTERED" {
+				return ErrUnregistered
+			}
+		}
+	}
+	return fmt.Errorf("fcm: send failed: %s: %s", resp.Status, bytes.TrimSpace(data))
+}
+
+// token returns a cached OAuth access token, exchanging a freshly signed
+// assertion for a new one shortly before the old one expires.
+func (f *FCM) token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	if f.accessToken != "" && now.Before(f.expires.Add(-time.Minute)) {
+		return f.accessToken, nil
+	}
+
+	assertion, err := signJWT(
+		map[string]string{"alg": "RS256", "typ": "JWT"},
+		map[string]interface{}{
+			"iss":   f.clientEmail,
+			"scope": fcmScope,
+			"aud":   f.tokenURL,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		},
+		f.key, nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", err
+	}
+	if tok.AccessToken == "" {
+		return "", errors.New("token response contained no access_token")
+	}
+	f.accessToken = tok.AccessToken
+	f.expires = now.Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return f.accessToken, nil
+}
@@ -0,0 +1,115 @@
+// Package push delivers notifications to mobile devices through Firebase
+// Cloud Messaging and the Apple Push Notification service.
+package push
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Provider names, which are also what clients register device tokens under.
+const (
+	ProviderFCM  = "fcm"
+	ProviderAPNs = "apns"
+)
+
+// ErrUnregistered is returned when the provider reports that a device token
+// is permanently invalid, e.g. because the app was uninstalled. Such tokens
+// should be forgotten.
+var ErrUnregistered = errors.New("push: device token is no longer registered")
+
+// Message is the notification shown on the device. Data is passed to the
+// app alongside it.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender delivers messages to device tokens issued by one provider.
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// Senders maps a provider name to its Sender.
+type Senders map[string]Sender
+
+// requestTimeout bounds a single request to a provider.
+const requestTimeout = 30 * time.Second
+
+// FromEnv builds a Sender for every configured provider: FCM when
+// FCM_CREDENTIALS_FILE names a service account key, APNs when APNS_KEY_FILE
+// names a token signing key. Push is off by default, in which case the
+// result is empty.
+func FromEnv(getenv func(string) string) (Senders, error) {
+	senders := Senders{}
+	if path := getenv("FCM_CREDENTIALS_FILE"); path != "" {
+		data, err := os.ReadFile(path) // #nosec G304 -- path comes from operator configuration.
+		if err != nil {
+			return nil, err
+		}
+		fcm, err := NewFCM(data)
+		if err != nil {
+			return nil, fmt.Errorf("FCM_CREDENTIALS_FILE: %w", err)
+		}
+		senders[ProviderFCM] = fcm
+	}
+	if path := getenv("APNS_KEY_FILE"); path != "" {
+		keyID, teamID, topic := getenv("APNS_KEY_ID"), getenv("APNS_TEAM_ID"), getenv("APNS_TOPIC")
+		if keyID == "" || teamID == "" || topic == "" {
+			return nil, errors.New("APNS_KEY_ID, APNS_TEAM_ID and APNS_TOPIC are required with APNS_KEY_FILE")
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- path comes from operator configuration.
+		if err != nil {
+			return nil, err
+		}
+		baseURL := apnsProductionURL
+		switch env := getenv("APNS_ENVIRONMENT"); env {
+		case "", "production":
+		case "sandbox":
+			baseURL = apnsSandboxURL
+		default:
+			return nil, fmt.Errorf("unknown APNS_ENVIRONMENT %q", env)
+		}
+		apns, err := NewAPNs(baseURL, data, keyID, teamID, topic)
+		if err != nil {
+			return nil, fmt.Errorf("APNS_KEY_FILE: %w", err)
+		}
+		senders[ProviderAPNs] = apns
+	}
+	return senders, nil
+}
+
+// signJWT builds a compact JWT from header and claims, signing the SHA-256
+// digest of its first two parts with key. encode, if set, converts the
+// signature into the form the JWT algorithm expects.
+func signJWT(header, claims interface{}, key crypto.Signer, encode func(sig []byte) ([]byte, error)) (string, error) {
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(unsigned))
+	sig, err := key.Sign(rand.Reader, digest.Sum(nil), crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+	if encode != nil {
+		if sig, err = encode(sig); err != nil {
+			return "", err
+		}
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
@@ -0,0 +1,465 @@
+package push
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	apnsKey := filepath.Join(dir, "AuthKey.p8")
+	writeFile(t, apnsKey, ecKeyPEM(t, mustECKey(t)))
+	fcmCreds := filepath.Join(dir, "service-account.json")
+	writeFile(t, fcmCreds, serviceAccount(t, mustRSAKey(t), "https://oauth2.googleapis.com/token"))
+	badCreds := filepath.Join(dir, "bad.json")
+	writeFile(t, badCreds, []byte(`{"project_id": "p"}`))
+
+	apnsEnv := func(extra map[string]string) map[string]string {
+		env := map[string]string{"APNS_KEY_FILE": apnsKey, "APNS_KEY_ID": "KEY", "APNS_TEAM_ID": "TEAM", "APNS_TOPIC": "com.example.notely"}
+		for k, v := range extra {
+			env[k] = v
+		}
+		return env
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    []string
+		wantErr bool
+	}{
+		{name: "disabled by default", env: map[string]string{}},
+		{name: "fcm", env: map[string]string{"FCM_CREDENTIALS_FILE": fcmCreds}, want: []string{ProviderFCM}},
+		{name: "fcm with incomplete credentials", env: map[string]string{"FCM_CREDENTIALS_FILE": badCreds}, wantErr: true},
+		{name: "fcm with missing file", env: map[string]string{"FCM_CREDENTIALS_FILE": filepath.Join(dir, "nope.json")}, wantErr: true},
+		{name: "apns", env: apnsEnv(nil), want: []string{ProviderAPNs}},
+		{name: "apns sandbox", env: apnsEnv(map[string]string{"APNS_ENVIRONMENT": "sandbox"}), want: []string{ProviderAPNs}},
+		{name: "apns unknown environment", env: apnsEnv(map[string]string{"APNS_ENVIRONMENT": "staging"}), wantErr: true},
+		{name: "apns without team", env: apnsEnv(map[string]string{"APNS_TEAM_ID": ""}), wantErr: true},
+		{name: "both", env: apnsEnv(map[string]string{"FCM_CREDENTIALS_FILE": fcmCreds}), want: []string{ProviderFCM, ProviderAPNs}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			senders, err := FromEnv(func(k string) string { return tt.env[k] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(senders) != len(tt.want) {
+				t.Fatalf("FromEnv() configured %d providers, want %v", len(senders), tt.want)
+			}
+			for _, provider := range tt.want {
+				if senders[provider] == nil {
+					t.Errorf("FromEnv() didn't configure %s", provider)
+				}
+			}
+		})
+	}
+}
+
+func TestFCMSend(t *testing.T) {
+	key := mustRSAKey(t)
+	tokenRequests := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+				http.Error(w, "bad grant", http.StatusBadRequest)
+				return
+			}
+			claims := verifyJWT(t, r.FormValue("assertion"), func(digest, sig []byte) bool {
+				return rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest, sig) == nil
+			})
+			if claims["iss"] != "push@example.iam.gserviceaccount.com" || claims["aud"] != srv.URL+"/token" || claims["scope"] != fcmScope {
+				http.Error(w, "bad assertion", http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access", "expires_in": 3600})
+		case "/v1/projects/notely-test/messages:send":
+			if r.Header.Get("Authorization") != "Bearer access" {
+				http.Error(w, "unauthenticated", http.StatusUnauthorized)
+				return
+			}
+			var body struct {
+				Message struct {
+					Token        string            `json:"token"`
+					Notification map[string]string `json:"notification"`
+				} `json:"message"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			switch body.Message.Token {
+			case "good":
+				if body.Message.Notification["title"] != "Hello" {
+					http.Error(w, "missing title", http.StatusBadRequest)
+					return
+				}
+				w.Write([]byte(`{"name": "projects/notely-test/messages/1"}`))
+			case "gone":
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error": {"code": 404, "status": "NOT_FOUND", "details": [{"@type": "type.googleapis.com/google.firebase.fcm.v1.FcmError", "errorCode": "UNREGISTERED"}]}}`))
+			default:
+				http.Error(w, `{"error": {"code": 500, "status": "INTERNAL"}}`, http.StatusInternalServerError)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	fcm, err := NewFCM(serviceAccount(t, key, srv.URL+"/token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fcm.baseURL = srv.URL
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "delivered", token: "good"},
+		{name: "unregistered token", token: "gone", wantErr: ErrUnregistered},
+		{name: "server error", token: "broken", wantErr: errors.New("any")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fcm.Send(context.Background(), tt.token, Message{Title: "Hello", Body: "World"})
+			checkSendError(t, err, tt.wantErr)
+		})
+	}
+	if tokenRequests != 1 {
+		t.Errorf("requested %d access tokens, want 1", tokenRequests)
+	}
+}
+
+func TestAPNsSend(t *testing.T) {
+	key := mustECKey(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "bearer ")
+		if !ok || r.Header.Get("apns-topic") != "com.example.notely" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"reason": "MissingProviderToken"}`))
+			return
+		}
+		claims := verifyJWT(t, jwt, func(digest, sig []byte) bool {
+			r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+			return len(sig) == 64 && ecdsa.Verify(&key.PublicKey, digest, r, s)
+		})
+		if claims["iss"] != "TEAM" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"reason": "InvalidProviderToken"}`))
+			return
+		}
+
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "good":
+			var payload struct {
+				Aps struct {
+					Alert map[string]string `json:"alert"`
+				} `json:"aps"`
+				NoteID string `json:"note_id"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if payload.Aps.Alert["title"] != "Hello" || payload.NoteID != "n1" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"reason": "PayloadEmpty"}`))
+			}
+		case "gone":
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"reason": "Unregistered", "timestamp": 1700000000000}`))
+		case "malformed":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"reason": "BadDeviceToken"}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"reason": "ServiceUnavailable"}`))
+		}
+	}))
+	defer srv.Close()
+
+	apns, err := NewAPNs(srv.URL, ecKeyPEM(t, key), "KEY", "TEAM", "com.example.notely")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "delivered", token: "good"},
+		{name: "unregistered token", token: "gone", wantErr: ErrUnregistered},
+		{name: "malformed token", token: "malformed", wantErr: ErrUnregistered},
+		{name: "unavailable", token: "other", wantErr: errors.New("any")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := apns.Send(context.Background(), tt.token, Message{Title: "Hello", Body: "World", Data: map[string]string{"note_id": "n1"}})
+			checkSendError(t, err, tt.wantErr)
+		})
+	}
+}
+
+// checkSendError expects no error for a nil want, ErrUnregistered exactly,
+// and any other error otherwise.
+func checkSendError(t *testing.T, err, want error) {
+	t.Helper()
+	switch {
+	case want == nil && err != nil:
+		t.Errorf("Send() error = %v, want nil", err)
+	case want == ErrUnregistered && !errors.Is(err, ErrUnregistered):
+		t.Errorf("Send() error = %v, want ErrUnregistered", err)
+	case want != nil && want != ErrUnregistered && (err == nil || errors.Is(err, ErrUnregistered)):
+		t.Errorf("Send() error = %v, want a delivery error", err)
+	}
+}
+
+// verifyJWT checks a compact JWT's signature with verify and returns its claims.
+func verifyJWT(t *testing.T, jwt string, verify func(digest, sig []byte) bool) map[string]interface{} {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Errorf("malformed JWT %q", jwt)
+		return nil
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Errorf("decoding signature: %v", err)
+		return nil
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !verify(digest[:], sig) {
+		t.Errorf("JWT signature doesn't verify")
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Errorf("decoding claims: %v", err)
+		return nil
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Errorf("parsing claims: %v", err)
+	}
+	return claims
+}
+
+func mustRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func mustECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func ecKeyPEM(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func serviceAccount(t *testing.T, key *rsa.PrivateKey, tokenURI string) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "notely-test",
+		"client_email": "push@example.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    tokenURI,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestEncryptWebPush checks against the example in RFC 8291, Appendix A.
+func TestEncryptWebPush(t *testing.T) {
+	b64 := base64.RawURLEncoding
+	asPrivate, err := b64.DecodeString("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ephemeral, err := ecdh.P256().NewPrivateKey(asPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt, err := b64.DecodeString("DGv6ra1nlYgDCS1FRnbzlw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := Subscription{
+		Endpoint: "https://push.example.net/push/JzLQ3raZJfFBR0aqvOMsLrt54w4rJUsV",
+		P256dh:   "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		Auth:     "BTBZMqHH6r4Tts7J_aSIgg",
+	}
+
+	got, err := encryptWebPush(sub, []byte("When I grow up, I want to be a watermelon"), ephemeral, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	if b64.EncodeToString(got) != want {
+		t.Errorf("encryptWebPush() = %s, want %s", b64.EncodeToString(got), want)
+	}
+}
+
+func TestWebPushSend(t *testing.T) {
+	privateKey, err := GenerateVAPIDKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var srv *httptest.Server
+	var wp *WebPush
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwt, k, ok := parseVAPIDAuthorization(r.Header.Get("Authorization"))
+		if !ok || k != wp.PublicKey() || r.Header.Get("Content-Encoding") != "aes128gcm" || r.Header.Get("TTL") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		public, _ := base64.RawURLEncoding.DecodeString(k)
+		claims := verifyJWT(t, jwt, func(digest, sig []byte) bool {
+			key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(public[1:33]), Y: new(big.Int).SetBytes(public[33:])}
+			r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+			return len(sig) == 64 && ecdsa.Verify(key, digest, r, s)
+		})
+		if claims["aud"] != srv.URL || claims["sub"] != "mailto:ops@example.com" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/push/good":
+			w.WriteHeader(http.StatusCreated)
+		case "/push/gone":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	wp, err = NewWebPush(privateKey, "mailto:ops@example.com", srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	browser, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256dh := base64.RawURLEncoding.EncodeToString(browser.PublicKey().Bytes())
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "delivered", path: "/push/good"},
+		{name: "expired subscription", path: "/push/gone", wantErr: ErrUnregistered},
+		{name: "throttled", path: "/push/busy", wantErr: errors.New("any")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := Subscription{Endpoint: srv.URL + tt.path, P256dh: p256dh, Auth: "BTBZMqHH6r4Tts7J_aSIgg"}
+			err := wp.Send(context.Background(), sub, Message{Title: "Hello", Body: "World"})
+			checkSendError(t, err, tt.wantErr)
+		})
+	}
+}
+
+// parseVAPIDAuthorization splits a "vapid t=..., k=..." header.
+func parseVAPIDAuthorization(header string) (jwt, key string, ok bool) {
+	params, ok := strings.CutPrefix(header, "vapid ")
+	if !ok {
+		return "", "", false
+	}
+	for _, param := range strings.Split(params, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch name {
+		case "t":
+			jwt = value
+		case "k":
+			key = value
+		}
+	}
+	return jwt, key, jwt != "" && key != ""
+}
+
+func TestSubscriptionValidate(t *testing.T) {
+	valid := Subscription{
+		Endpoint: "https://push.example.net/push/abc",
+		P256dh:   "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		Auth:     "BTBZMqHH6r4Tts7J_aSIgg",
+	}
+	tests := []struct {
+		name    string
+		modify  func(*Subscription)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(*Subscription) {}},
+		{name: "plain http", modify: func(s *Subscription) { s.Endpoint = "http://push.example.net/push/abc" }, wantErr: true},
+		{name: "not a URL", modify: func(s *Subscription) { s.Endpoint = "push" }, wantErr: true},
+		{name: "key not on the curve", modify: func(s *Subscription) { s.P256dh = "BAAA" + s.P256dh[4:] }, wantErr: true},
+		{name: "padded key", modify: func(s *Subscription) { s.P256dh += "=" }, wantErr: true},
+		{name: "short auth", modify: func(s *Subscription) { s.Auth = "BTBZMqHH6r4T" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := valid
+			tt.modify(&sub)
+			if err := sub.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
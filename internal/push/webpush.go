@@ -0,0 +1,253 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// webPushTTL is how long a push service keeps trying to deliver a
+	// message to a browser that is offline.
+	webPushTTL = 24 * time.Hour
+
+	// vapidTokenLifetime is how long the VAPID JWT sent with a message is
+	// valid. Push services reject anything over 24 hours.
+	vapidTokenLifetime = 12 * time.Hour
+
+	// webPushRecordSize is the aes128gcm record size. Messages are sent as a
+	// single record, so it only has to exceed the encrypted payload.
+	webPushRecordSize = 4096
+)
+
+// Subscription is a browser's PushSubscription: the push service endpoint
+// to deliver to and the keys to encrypt messages for it, base64url encoded.
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Validate checks that sub came from a browser: an https endpoint, a P-256
+// public key and a 16-byte auth secret.
+func (sub Subscription) Validate() error {
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return errors.New("endpoint must be an https URL")
+	}
+	p256dh, err := base64.RawURLEncoding.DecodeString(sub.P256dh)
+	if err != nil {
+		return errors.New("p256dh must be base64url encoded")
+	}
+	if _, err := ecdh.P256().NewPublicKey(p256dh); err != nil {
+		return errors.New("p256dh isn't a P-256 public key")
+	}
+	auth, err := base64.RawURLEncoding.DecodeString(sub.Auth)
+	if err != nil || len(auth) != 16 {
+		return errors.New("auth must be 16 base64url encoded bytes")
+	}
+	return nil
+}
+
+// WebPush sends to browsers through their push service (RFC 8030),
+// encrypting messages as RFC 8291 requires and identifying the application
+// server with VAPID (RFC 8292).
+type WebPush struct {
+	key       *ecdsa.PrivateKey
+	publicKey []byte
+	subject   string
+	client    *http.Client
+}
+
+// NewWebPush builds a Web Push sender from a VAPID private key as produced
+// by GenerateVAPIDKey. subject is a mailto: or https: URL push services can
+// contact the operator at. Endpoints are chosen by browsers, i.e. users, so
+// client should refuse to connect to internal addresses.
+func NewWebPush(privateKey, subject string, client *http.Client) (*WebPush, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding VAPID key: %w", err)
+	}
+	ecdhKey, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing VAPID key: %w", err)
+	}
+	publicKey := ecdhKey.PublicKey().Bytes()
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(publicKey[1:33]),
+			Y:     new(big.Int).SetBytes(publicKey[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+	return &WebPush{
+		key:       key,
+		publicKey: publicKey,
+		subject:   subject,
+		client:    client,
+	}, nil
+}
+
+// GenerateVAPIDKey returns a new P-256 private key in the base64url form
+// NewWebPush accepts, which is also what other Web Push libraries use.
+func GenerateVAPIDKey() (string, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key.Bytes()), nil
+}
+
+// PublicKey is the application server key browsers subscribe with.
+func (w *WebPush) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(w.publicKey)
+}
+
+// Send delivers msg to sub as JSON with title, body and data fields, for the
+// web client's service worker to display.
+func (w *WebPush) Send(ctx context.Context, sub Subscription, msg Message) error {
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil {
+		return fmt.Errorf("webpush: parsing endpoint: %w", err)
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"title": msg.Title,
+		"body":  msg.Body,
+		"data":  msg.Data,
+	})
+	if err != nil {
+		return err
+	}
+	body, err := encryptWebPush(sub, payload, nil, nil)
+	if err != nil {
+		return fmt.Errorf("webpush: encrypting message: %w", err)
+	}
+	jwt, err := signJWT(
+		map[string]string{"typ": "JWT", "alg": "ES256"},
+		map[string]interface{}{
+			"aud": endpoint.Scheme + "://" + endpoint.Host,
+			"exp": time.Now().Add(vapidTokenLifetime).Unix(),
+			"sub": w.subject,
+		},
+		w.key, ecdsaRawSignature,
+	)
+	if err != nil {
+		return fmt.Errorf("webpush: signing token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "vapid t="+jwt+", k="+w.PublicKey())
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(webPushTTL.Seconds())))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return ErrUnregistered
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("webpush: send failed: %s: %s", resp.Status, bytes.TrimSpace(data))
+}
+
+// encryptWebPush encrypts payload for sub using the aes128gcm content
+// encoding (RFC 8188) with keys derived as RFC 8291 describes. ephemeral
+// and salt are generated when nil; tests pass fixed ones.
+func encryptWebPush(sub Subscription, payload []byte, ephemeral *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	uaPublicRaw, err := base64.RawURLEncoding.DecodeString(sub.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("decoding p256dh: %w", err)
+	}
+	authSecret, err := base64.RawURLEncoding.DecodeString(sub.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("decoding auth: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicRaw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing p256dh: %w", err)
+	}
+	if ephemeral == nil {
+		if ephemeral, err = ecdh.P256().GenerateKey(rand.Reader); err != nil {
+			return nil, err
+		}
+	}
+	if salt == nil {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+	}
+	asPublic := ephemeral.PublicKey().Bytes()
+	if len(payload)+1+aes.BlockSize+len(salt)+len(asPublic)+5 > webPushRecordSize {
+		return nil, errors.New("payload too large")
+	}
+
+	ecdhSecret, err := ephemeral.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublicRaw...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := hkdf(authSecret, ecdhSecret, keyInfo, 32)
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header: salt, record size, key ID length and the key ID, which for
+	// Web Push is the ephemeral public key.
+	header := make([]byte, 0, len(salt)+5+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, webPushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+	// 0x02 marks the last (and only) record.
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// hkdf is HKDF-SHA-256 (RFC 5869) for outputs of at most one hash length,
+// which is all Web Push needs.
+func hkdf(salt, ikm, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(ikm)
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	expand.Write(info)
+	expand.Write([]byte{0x01})
+	return expand.Sum(nil)[:length]
+}
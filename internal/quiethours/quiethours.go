@@ -0,0 +1,90 @@
+// Package quiethours decides when notifications may be delivered to a user
+// who has set up a daily do-not-disturb period in their own time zone.
+package quiethours
+
+import (
+	"fmt"
+	"time"
+
+	// Time zones come from users, and the container image ships without a
+	// zoneinfo database.
+	_ "time/tzdata"
+)
+
+// Clock is a time of day in minutes since midnight.
+type Clock int
+
+// ParseClock parses a 24-hour "HH:MM" time of day.
+func ParseClock(s string) (Clock, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, want HH:MM", s)
+	}
+	return Clock(t.Hour()*60 + t.Minute()), nil
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// Schedule is a daily quiet period from Start to End in Location. A period
+// whose End is earlier than its Start runs overnight; one whose Start and
+// End are equal is never active.
+type Schedule struct {
+	Start    Clock
+	End      Clock
+	Location *time.Location
+}
+
+// New builds a Schedule from "HH:MM" times and an IANA time zone name.
+func New(start, end, timezone string) (Schedule, error) {
+	s, err := ParseClock(start)
+	if err != nil {
+		return Schedule{}, err
+	}
+	e, err := ParseClock(end)
+	if err != nil {
+		return Schedule{}, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("unknown time zone %q", timezone)
+	}
+	return Schedule{Start: s, End: e, Location: loc}, nil
+}
+
+// Active reports whether t falls within quiet hours.
+func (s Schedule) Active(t time.Time) bool {
+	local := t.In(s.Location)
+	now := Clock(local.Hour()*60 + local.Minute())
+	switch {
+	case s.Start == s.End:
+		return false
+	case s.Start < s.End:
+		return now >= s.Start && now < s.End
+	default:
+		return now >= s.Start || now < s.End
+	}
+}
+
+// Next returns the earliest time at or after t outside quiet hours: t itself
+// if quiet hours aren't active, otherwise the moment they end. When the end
+// falls into a daylight saving gap, quiet hours end as the clocks jump.
+func (s Schedule) Next(t time.Time) time.Time {
+	if !s.Active(t) {
+		return t
+	}
+	local := t.In(s.Location)
+	y, m, d := local.Date()
+	if s.Start > s.End && Clock(local.Hour()*60+local.Minute()) >= s.Start {
+		// Overnight quiet hours end the following morning.
+		d++
+	}
+	end := time.Date(y, m, d, int(s.End)/60, int(s.End)%60, 0, 0, s.Location)
+	// time.Date may resolve a wall clock time skipped by a DST transition to
+	// an instant before t; step forward to the first one outside quiet hours.
+	for !end.After(t) || s.Active(end) {
+		end = end.Add(time.Minute).Truncate(time.Minute)
+	}
+	return end
+}
@@ -0,0 +1,143 @@
+package quiethours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		timezone string
+		wantErr  bool
+	}{
+		{name: "valid", start: "22:00", end: "07:30", timezone: "Europe/Berlin"},
+		{name: "UTC", start: "00:00", end: "06:00", timezone: "UTC"},
+		{name: "bad start", start: "10pm", end: "07:00", timezone: "UTC", wantErr: true},
+		{name: "out of range end", start: "22:00", end: "24:00", timezone: "UTC", wantErr: true},
+		{name: "unknown time zone", start: "22:00", end: "07:00", timezone: "Mars/Olympus_Mons", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.start, tt.end, tt.timezone)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New(%q, %q, %q) error = %v, wantErr %v", tt.start, tt.end, tt.timezone, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	newYork := mustSchedule(t, "22:00", "07:00", "America/New_York")
+	berlin := mustSchedule(t, "22:00", "07:00", "Europe/Berlin")
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		at       string
+		want     string
+	}{
+		{
+			name:     "outside quiet hours",
+			schedule: newYork,
+			at:       "2024-06-01T16:00:00Z", // 12:00 EDT
+			want:     "2024-06-01T16:00:00Z",
+		},
+		{
+			name:     "before midnight ends next morning",
+			schedule: newYork,
+			at:       "2024-06-02T03:00:00Z", // 23:00 EDT
+			want:     "2024-06-02T11:00:00Z", // 07:00 EDT
+		},
+		{
+			name:     "after midnight ends same morning",
+			schedule: newYork,
+			at:       "2024-06-02T06:00:00Z", // 02:00 EDT
+			want:     "2024-06-02T11:00:00Z",
+		},
+		{
+			name:     "end is exclusive",
+			schedule: newYork,
+			at:       "2024-06-02T11:00:00Z",
+			want:     "2024-06-02T11:00:00Z",
+		},
+		{
+			name:     "uses the user's time zone",
+			schedule: berlin,
+			at:       "2024-06-01T21:30:00Z", // 23:30 CEST
+			want:     "2024-06-02T05:00:00Z", // 07:00 CEST
+		},
+		{
+			name:     "daytime quiet hours",
+			schedule: mustSchedule(t, "09:00", "17:00", "UTC"),
+			at:       "2024-06-01T12:00:00Z",
+			want:     "2024-06-01T17:00:00Z",
+		},
+		{
+			name:     "equal start and end are never active",
+			schedule: mustSchedule(t, "07:00", "07:00", "UTC"),
+			at:       "2024-06-01T07:00:00Z",
+			want:     "2024-06-01T07:00:00Z",
+		},
+		{
+			name:     "spring forward night is an hour shorter",
+			schedule: newYork,
+			at:       "2024-03-10T04:00:00Z", // 23:00 EST on March 9
+			want:     "2024-03-10T11:00:00Z", // 07:00 EDT
+		},
+		{
+			name:     "end skipped by spring forward is when clocks jump",
+			schedule: mustSchedule(t, "22:00", "02:30", "America/New_York"),
+			at:       "2024-03-10T05:00:00Z", // 00:00 EST
+			want:     "2024-03-10T07:00:00Z", // 03:00 EDT
+		},
+		{
+			name:     "fall back night is an hour longer",
+			schedule: newYork,
+			at:       "2024-11-03T02:00:00Z", // 22:00 EDT on November 2
+			want:     "2024-11-03T12:00:00Z", // 07:00 EST
+		},
+		{
+			name:     "repeated hour after fall back is still quiet",
+			schedule: newYork,
+			at:       "2024-11-03T06:30:00Z", // second 01:30, EST
+			want:     "2024-11-03T12:00:00Z",
+		},
+		{
+			name:     "end in the repeated hour is its first occurrence",
+			schedule: mustSchedule(t, "22:00", "01:30", "America/New_York"),
+			at:       "2024-11-03T03:00:00Z", // 23:00 EDT on November 2
+			want:     "2024-11-03T05:30:00Z", // first 01:30, EDT
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := mustTime(t, tt.at)
+			if got := tt.schedule.Next(at); !got.Equal(mustTime(t, tt.want)) {
+				t.Errorf("Next(%s) = %s, want %s", tt.at, got.UTC().Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func mustSchedule(t *testing.T, start, end, timezone string) Schedule {
+	t.Helper()
+	s, err := New(start, end, timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return at
+}
@@ -0,0 +1,260 @@
+// Package readable extracts the main content of an HTML page as Markdown.
+package readable
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Page is the readable part of an HTML document.
+type Page struct {
+	Title    string
+	Markdown string
+}
+
+// skipped elements never contain readable content.
+var skipped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Form: true, atom.Button: true, atom.Iframe: true, atom.Svg: true,
+}
+
+// Extract parses an HTML document and converts its main content to
+// Markdown. The content root is the first <article>, else <main>, else
+// <body>; navigation, scripts and similar chrome are dropped. Relative links
+// are resolved against base, which may be nil.
+func Extract(r io.Reader, base *url.URL) (Page, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Title: title(doc)}
+	root := find(doc, atom.Article)
+	if root == nil {
+		root = find(doc, atom.Main)
+	}
+	if root == nil {
+		root = find(doc, atom.Body)
+	}
+	if root == nil {
+		return page, nil
+	}
+
+	w := &writer{base: base}
+	w.children(root)
+	page.Markdown = tidy(w.String())
+	return page, nil
+}
+
+var (
+	blankLines = regexp.MustCompile(`\n{3,}`)
+	listItem   = regexp.MustCompile(`^ *(- |\d+\. )`)
+)
+
+// tidy strips the stray whitespace left by HTML formatting, keeping the
+// indentation of nested list items, and collapses runs of blank lines.
+func tidy(md string) string {
+	lines := strings.Split(md, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if !listItem.MatchString(line) {
+			line = strings.TrimLeft(line, " \t")
+		}
+		lines[i] = line
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// title prefers og:title, which is usually free of site-name suffixes.
+func title(doc *html.Node) string {
+	var og, plain string
+	walk(doc, func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.Meta:
+			if attr(n, "property") == "og:title" && og == "" {
+				og = strings.TrimSpace(attr(n, "content"))
+			}
+		case atom.Title:
+			if plain == "" {
+				plain = strings.TrimSpace(text(n))
+			}
+		}
+	})
+	if og != "" {
+		return og
+	}
+	return plain
+}
+
+type writer struct {
+	strings.Builder
+	base *url.URL
+	list []listState
+}
+
+type listState struct {
+	ordered bool
+	n       int
+}
+
+func (w *writer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+}
+
+func (w *writer) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.WriteString(collapseSpace(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if skipped[n.DataAtom] {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		w.WriteString("\n\n" + strings.Repeat("#", level) + " " + w.inline(n) + "\n\n")
+	case atom.P, atom.Div, atom.Section:
+		w.WriteString("\n\n")
+		w.children(n)
+		w.WriteString("\n\n")
+	case atom.Br:
+		w.WriteString("\\\n")
+	case atom.Hr:
+		w.WriteString("\n\n---\n\n")
+	case atom.Strong, atom.B:
+		w.wrap(n, "**")
+	case atom.Em, atom.I:
+		w.wrap(n, "_")
+	case atom.Code:
+		w.WriteString("`" + text(n) + "`")
+	case atom.Pre:
+		w.WriteString("\n\n```\n" + strings.Trim(text(n), "\n") + "\n```\n\n")
+	case atom.Blockquote:
+		w.WriteString("\n\n> " + w.inline(n) + "\n\n")
+	case atom.A:
+		label := w.inline(n)
+		href := w.resolve(attr(n, "href"))
+		if href == "" || label == "" {
+			w.WriteString(label)
+			return
+		}
+		w.WriteString("[" + label + "](" + href + ")")
+	case atom.Img:
+		if src := w.resolve(attr(n, "src")); src != "" {
+			w.WriteString("![" + attr(n, "alt") + "](" + src + ")")
+		}
+	case atom.Ul, atom.Ol:
+		if len(w.list) == 0 {
+			w.WriteString("\n\n")
+		}
+		w.list = append(w.list, listState{ordered: n.DataAtom == atom.Ol})
+		w.children(n)
+		w.list = w.list[:len(w.list)-1]
+		if len(w.list) == 0 {
+			w.WriteString("\n\n")
+		}
+	case atom.Li:
+		depth := len(w.list)
+		marker := "- "
+		if depth > 0 {
+			state := &w.list[depth-1]
+			state.n++
+			if state.ordered {
+				marker = strconv.Itoa(state.n) + ". "
+			}
+		} else {
+			depth = 1
+		}
+		w.WriteString("\n" + strings.Repeat("   ", depth-1) + marker)
+		w.children(n)
+	default:
+		w.children(n)
+	}
+}
+
+func (w *writer) wrap(n *html.Node, marker string) {
+	if s := w.inline(n); s != "" {
+		w.WriteString(marker + s + marker)
+	}
+}
+
+// resolve returns an absolute http(s) URL for ref, or "" for anything else
+// (e.g. javascript: links).
+func (w *writer) resolve(ref string) string {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || ref == "" {
+		return ""
+	}
+	if w.base != nil {
+		u = w.base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+// inline renders n's children on a single line, for use inside other markup.
+func (w *writer) inline(n *html.Node) string {
+	sub := &writer{base: w.base}
+	sub.children(n)
+	return strings.Join(strings.Fields(sub.String()), " ")
+}
+
+func text(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+	})
+	return b.String()
+}
+
+func walk(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+func collapseSpace(s string) string {
+	return whitespace.ReplaceAllString(s, " ")
+}
@@ -0,0 +1,62 @@
+package readable
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/post")
+
+	tests := []struct {
+		name      string
+		html      string
+		wantTitle string
+		want      string
+	}{
+		{
+			name:      "prefers og:title and the article element",
+			html:      `<html><head><title>Post | Example</title><meta property="og:title" content="Post"></head><body><nav>Home</nav><article><h1>Post</h1><p>Hello <b>world</b>.</p></article><footer>(c)</footer></body></html>`,
+			wantTitle: "Post",
+			want:      "# Post\n\nHello **world**.",
+		},
+		{
+			name: "falls back to body and drops scripts",
+			html: `<title> Plain </title><body><script>alert(1)</script><p>One</p>
+
+<p>Two</p></body>`,
+			wantTitle: "Plain",
+			want:      "One\n\nTwo",
+		},
+		{
+			name: "resolves links and drops unsafe ones",
+			html: `<main><p><a href="../other">relative</a> <a href="javascript:alert(1)">bad</a> <img src="/i.png" alt="pic"></p></main>`,
+			want: "[relative](https://example.com/other) bad ![pic](https://example.com/i.png)",
+		},
+		{
+			name: "lists, quotes and code",
+			html: `<body><ul>
+				<li>a</li>
+				<li>b<ol><li>one</li><li>two</li></ol></li>
+			</ul><blockquote><p>quoted</p></blockquote><pre><code>x := 1
+y := 2</code></pre><p>use <code>go test</code></p></body>`,
+			want: "- a\n- b\n   1. one\n   2. two\n\n> quoted\n\n```\nx := 1\ny := 2\n```\n\nuse `go test`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Extract(strings.NewReader(tt.html), base)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if page.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", page.Title, tt.wantTitle)
+			}
+			if page.Markdown != tt.want {
+				t.Errorf("Markdown = %q, want %q", page.Markdown, tt.want)
+			}
+		})
+	}
+}
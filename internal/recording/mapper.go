@@ -0,0 +1,75 @@
+package recording
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Mapper translates IDs and API key pseudonyms from a recording to the
+// values a replay got for the same requests. A note created during the
+// replay gets a new ID, so later requests that used the recorded one have
+// to use the new one instead.
+type Mapper struct {
+	values map[string]string
+}
+
+func NewMapper() *Mapper {
+	return &Mapper{values: make(map[string]string)}
+}
+
+// Learn pairs the IDs and keys in a recorded response body with those at
+// the same place in the replayed one. Fields named id, ending in _id, or
+// holding keys are paired; bodies that aren't both JSON are ignored.
+func (m *Mapper) Learn(recorded, replayed string) {
+	var a, b any
+	if json.Unmarshal([]byte(recorded), &a) != nil || json.Unmarshal([]byte(replayed), &b) != nil {
+		return
+	}
+	m.learn(a, b)
+}
+
+func (m *Mapper) learn(recorded, replayed any) {
+	switch a := recorded.(type) {
+	case map[string]any:
+		b, ok := replayed.(map[string]any)
+		if !ok {
+			return
+		}
+		for k, av := range a {
+			as, aIsString := av.(string)
+			bs, bIsString := b[k].(string)
+			if aIsString && bIsString && mappedField(k) {
+				if as != "" && as != bs {
+					m.values[as] = bs
+				}
+				continue
+			}
+			m.learn(av, b[k])
+		}
+	case []any:
+		b, ok := replayed.([]any)
+		if !ok {
+			return
+		}
+		for i := range min(len(a), len(b)) {
+			m.learn(a[i], b[i])
+		}
+	}
+}
+
+func mappedField(name string) bool {
+	return name == "id" || strings.HasSuffix(name, "_id") || keyFields[name]
+}
+
+// Apply replaces every recorded value Learn has paired in s with its
+// replayed counterpart.
+func (m *Mapper) Apply(s string) string {
+	if len(m.values) == 0 {
+		return s
+	}
+	pairs := make([]string, 0, 2*len(m.values))
+	for from, to := range m.values {
+		pairs = append(pairs, from, to)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
@@ -0,0 +1,270 @@
+// Package recording captures API requests and their responses to a file,
+// with credentials removed, and helps replay them against another server to
+// reproduce a bug report.
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBody is how much of each request and response body is recorded.
+const maxBody = 64 << 10
+
+// pseudonymPrefix starts the stand-ins recorded in place of API keys.
+const pseudonymPrefix = "redacted_"
+
+// Exchange is a recorded request and its response.
+type Exchange struct {
+	Time         time.Time   `json:"time"`
+	Method       string      `json:"method"`
+	URL          string      `json:"url"` // Path and query.
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	Status       int         `json:"status"`
+	ResponseBody string      `json:"response_body,omitempty"`
+	Truncated    bool        `json:"truncated,omitempty"` // A body was longer than what was recorded.
+	Dropped      bool        `json:"dropped,omitempty"`   // A body was left out; see sanitizeBody.
+}
+
+// recordedHeaders are the request headers kept in recordings. Others, such
+// as cookies, are dropped; Authorization is kept with its key replaced.
+var recordedHeaders = []string{"Accept", "Content-Type", "X-Dry-Run"}
+
+// Fields of JSON bodies that hold credentials, by their name as folded by
+// fieldName. API keys are replaced with a pseudonym, the same for every
+// occurrence of a key, so a replay can tell which requests were made with
+// which key; other secrets are replaced with a fixed string.
+var (
+	keyFields    = map[string]bool{"apikey": true, "key": true}
+	secretFields = map[string]bool{"auth": true, "p256dh": true, "password": true, "secret": true, "token": true}
+)
+
+// fieldNameFolder folds the naming styles of JSON field names together.
+var fieldNameFolder = strings.NewReplacer("_", "", "-", "")
+
+// fieldName folds a JSON field name so that api_key, apiKey and API-Key are
+// all looked up as apikey.
+func fieldName(k string) string {
+	return strings.ToLower(fieldNameFolder.Replace(k))
+}
+
+// Recorder appends exchanges to a file as JSON lines.
+type Recorder struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *bufio.Writer
+}
+
+// Open appends recordings to the file at path, creating it if needed. The
+// file is only readable by its owner, as recorded bodies hold user data.
+func Open(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// Close flushes and closes the file.
+func (rec *Recorder) Close() error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if err := rec.w.Flush(); err != nil {
+		rec.f.Close()
+		return err
+	}
+	return rec.f.Close()
+}
+
+func (rec *Recorder) write(e Exchange) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.w.Write(line)
+	rec.w.WriteByte('\n')
+	return rec.w.Flush()
+}
+
+// Middleware records every request passing through it once it has been
+// answered. Requests whose handler aborted aren't recorded.
+func (rec *Recorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := Exchange{
+			Time:   time.Now().UTC(),
+			Method: r.Method,
+			URL:    r.URL.RequestURI(),
+			Header: sanitizeHeader(r.Header),
+		}
+		if r.Body != nil {
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxBody+1))
+			if err == nil {
+				// The handler still gets the whole body.
+				r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+				e.Body, e.Truncated = truncate(body)
+			}
+		}
+
+		cw := &captureWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(cw, r)
+
+		e.Status = cw.status
+		var truncated bool
+		e.ResponseBody, truncated = truncate(cw.body.Bytes())
+		e.Truncated = e.Truncated || truncated || cw.overflow
+		var bodyOK, responseOK bool
+		e.Body, bodyOK = sanitizeBody(e.Body)
+		e.ResponseBody, responseOK = sanitizeBody(e.ResponseBody)
+		e.Dropped = !bodyOK || !responseOK
+		rec.write(e) // Best effort: a full disk mustn't fail requests.
+	})
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func truncate(body []byte) (string, bool) {
+	if len(body) > maxBody {
+		return string(body[:maxBody]), true
+	}
+	return string(body), false
+}
+
+// captureWriter keeps the status and the first maxBody bytes of a response.
+type captureWriter struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+	wrote    bool
+}
+
+func (w *captureWriter) WriteHeader(code int) {
+	if !w.wrote {
+		w.status, w.wrote = code, true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	w.wrote = true
+	room := maxBody - w.body.Len()
+	if len(p) > room {
+		w.overflow = true
+	}
+	if room > 0 {
+		w.body.Write(p[:min(len(p), room)])
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *captureWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func sanitizeHeader(h http.Header) http.Header {
+	out := http.Header{}
+	for _, name := range recordedHeaders {
+		if values := h.Values(name); len(values) > 0 {
+			out[name] = values
+		}
+	}
+	if auth := h.Get("Authorization"); auth != "" {
+		scheme, key, _ := strings.Cut(strings.TrimSpace(auth), " ")
+		out.Set("Authorization", scheme+" "+pseudonym(strings.TrimSpace(key)))
+	}
+	return out
+}
+
+// sanitizeBody replaces the credentials in a JSON body. Other bodies,
+// including JSON cut short by maxBody, can't be checked for credentials, so
+// they're dropped: it returns "" and false.
+func sanitizeBody(body string) (string, bool) {
+	if body == "" {
+		return body, true
+	}
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return "", false
+	}
+	out, err := json.Marshal(sanitizeValue(v))
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+func sanitizeValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			s, isString := field.(string)
+			switch name := fieldName(k); {
+			case isString && keyFields[name]:
+				v[k] = pseudonym(s)
+			case isString && secretFields[name]:
+				v[k] = "REDACTED"
+			default:
+				v[k] = sanitizeValue(field)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = sanitizeValue(v[i])
+		}
+	}
+	return v
+}
+
+// pseudonym stands in for an API key in recordings.
+func pseudonym(key string) string {
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return pseudonymPrefix + hex.EncodeToString(sum[:8])
+}
+
+// IsPseudonym reports whether s is a recorded stand-in for an API key.
+func IsPseudonym(s string) bool {
+	return strings.HasPrefix(s, pseudonymPrefix)
+}
+
+// Read returns the exchanges recorded in r, in order.
+func Read(r io.Reader) ([]Exchange, error) {
+	var exchanges []Exchange
+	dec := json.NewDecoder(r)
+	for {
+		var e Exchange
+		err := dec.Decode(&e)
+		if err == io.EOF {
+			return exchanges, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		exchanges = append(exchanges, e)
+	}
+}
@@ -0,0 +1,163 @@
+package recording
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+		drop bool
+	}{
+		{name: "empty", body: "", want: ""},
+		{name: "not JSON", body: "plain text", drop: true},
+		{name: "cut short", body: `{"api_key":"abc","note":"hi`, drop: true},
+		{name: "trailing data", body: `{"note":"hi"} {"api_key":"abc"}`, drop: true},
+		{name: "no credentials", body: `{"note":"hi"}`, want: `{"note":"hi"}`},
+		{name: "api key", body: `{"api_key":"abc","name":"n"}`, want: `{"api_key":"` + pseudonym("abc") + `","name":"n"}`},
+		{name: "camelCase api key", body: `{"apiKey":"abc","name":"n"}`, want: `{"apiKey":"` + pseudonym("abc") + `","name":"n"}`},
+		{name: "capitalized secret", body: `{"Password":"p"}`, want: `{"Password":"REDACTED"}`},
+		{name: "nested secrets", body: `[{"keys":{"auth":"a","p256dh":"p"}}]`, want: `[{"keys":{"auth":"REDACTED","p256dh":"REDACTED"}}]`},
+		{name: "numbers kept", body: `{"count":12345678901234567890}`, want: `{"count":12345678901234567890}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sanitizeBody(tt.body)
+			if got != tt.want || ok == tt.drop {
+				t.Errorf("sanitizeBody(%q) = %q, %v, want %q, %v", tt.body, got, ok, tt.want, !tt.drop)
+			}
+		})
+	}
+}
+
+func TestSanitizeHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "ApiKey secret")
+	h.Set("Cookie", "session=1")
+	h.Set("Content-Type", "application/json")
+
+	got := sanitizeHeader(h)
+	if got.Get("Cookie") != "" {
+		t.Errorf("Cookie was recorded")
+	}
+	if got.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got.Get("Content-Type"))
+	}
+	if want := "ApiKey " + pseudonym("secret"); got.Get("Authorization") != want {
+		t.Errorf("Authorization = %q, want %q", got.Get("Authorization"), want)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	path := t.TempDir() + "/rec.jsonl"
+	rec, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := rec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1","api_key":"k"}`))
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/users?x=1", strings.NewReader(`{"name":"n"}`)))
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	exchanges, err := Read(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exchanges) != 1 {
+		t.Fatalf("recorded %d exchanges, want 1", len(exchanges))
+	}
+	e := exchanges[0]
+	if e.Method != http.MethodPost || e.URL != "/v1/users?x=1" || e.Status != http.StatusCreated || e.Body != `{"name":"n"}` {
+		t.Errorf("recorded %+v", e)
+	}
+	if strings.Contains(e.ResponseBody, `"k"`) {
+		t.Errorf("response body %q holds the API key", e.ResponseBody)
+	}
+}
+
+func TestMiddlewareLargeResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		writes    []int
+		wantDrop  bool
+		wantTrunc bool
+	}{
+		{name: "one write under the limit", writes: []int{40 << 10}},
+		{name: "writes up to the limit", writes: []int{32 << 10, 32<<10 - 10}},
+		{name: "one write over the limit", writes: []int{maxBody + 1}, wantDrop: true, wantTrunc: true},
+		{name: "writes over the limit", writes: []int{40 << 10, 40 << 10}, wantDrop: true, wantTrunc: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + "/rec.jsonl"
+			rec, err := Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := rec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// A JSON string holding a secret, split across the writes.
+				total := 0
+				for _, n := range tt.writes {
+					total += n
+				}
+				body := []byte(`{"token":"` + strings.Repeat("s", total-len(`{"token":""}`)) + `"}`)
+				for _, n := range tt.writes {
+					w.Write(body[:n])
+					body = body[n:]
+				}
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/notes", nil))
+			if err := rec.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			exchanges, err := Read(f)
+			if err != nil || len(exchanges) != 1 {
+				t.Fatalf("Read() = %d exchanges, %v", len(exchanges), err)
+			}
+			e := exchanges[0]
+			if e.Truncated != tt.wantTrunc || e.Dropped != tt.wantDrop {
+				t.Errorf("Truncated, Dropped = %v, %v, want %v, %v", e.Truncated, e.Dropped, tt.wantTrunc, tt.wantDrop)
+			}
+			if strings.Contains(e.ResponseBody, "sss") {
+				t.Errorf("recorded the secret: %.40q...", e.ResponseBody)
+			}
+		})
+	}
+}
+
+func TestMapper(t *testing.T) {
+	m := NewMapper()
+	m.Learn(`{"id":"n1","user_id":"u1","note":"same"}`, `{"id":"n2","user_id":"u2","note":"same"}`)
+	m.Learn(`[{"id":"c1"}]`, `[{"id":"c2"}]`)
+	m.Learn(`not JSON`, `{"id":"x"}`)
+
+	if got, want := m.Apply("/v1/notes/n1/comments/c1?user=u1"), "/v1/notes/n2/comments/c2?user=u2"; got != want {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+	if got := m.Apply("same"); got != "same" {
+		t.Errorf("Apply() mapped a field that isn't an ID: %q", got)
+	}
+}
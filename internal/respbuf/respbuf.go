@@ -0,0 +1,126 @@
+// Package respbuf holds HTTP responses in memory only up to a limit. Small
+// responses are sent in one go with a Content-Length; larger ones spill to
+// chunked transfer encoding and are streamed, so a big list costs a bounded
+// amount of memory however long it gets.
+package respbuf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Writer buffers a response body up to a limit before streaming it.
+type Writer struct {
+	w         http.ResponseWriter
+	code      int
+	max       int
+	buf       bytes.Buffer
+	streaming bool
+}
+
+// New returns a Writer that responds to w with code once Close is called or
+// more than max bytes have been written, whichever comes first. Headers
+// must be set on w before then.
+func New(w http.ResponseWriter, code, max int) *Writer {
+	return &Writer{w: w, code: code, max: max}
+}
+
+// Write buffers p, or sends it on if the response is already streaming or
+// would outgrow the buffer.
+func (b *Writer) Write(p []byte) (int, error) {
+	if !b.streaming {
+		if b.buf.Len()+len(p) <= b.max {
+			return b.buf.Write(p)
+		}
+		if err := b.spill(); err != nil {
+			return 0, err
+		}
+	}
+	return b.w.Write(p)
+}
+
+// Flush sends everything written so far to the client if the response is
+// streaming; buffered responses are only sent by Close.
+func (b *Writer) Flush() {
+	if !b.streaming {
+		return
+	}
+	if f, ok := b.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Streaming reports whether the status line has been sent. Until then the
+// caller can still discard the body and respond with an error instead.
+func (b *Writer) Streaming() bool {
+	return b.streaming
+}
+
+// Close sends a buffered response with its Content-Length, or flushes the
+// rest of a streaming one.
+func (b *Writer) Close() error {
+	if b.streaming {
+		b.Flush()
+		return nil
+	}
+	b.w.Header().Set("Content-Length", strconv.Itoa(b.buf.Len()))
+	b.w.WriteHeader(b.code)
+	_, err := b.w.Write(b.buf.Bytes())
+	return err
+}
+
+// spill sends the status line and the buffered body, and releases the buffer.
+func (b *Writer) spill() error {
+	b.streaming = true
+	b.w.WriteHeader(b.code)
+	_, err := b.w.Write(b.buf.Bytes())
+	b.buf = bytes.Buffer{}
+	return err
+}
+
+// maxPooledBuffer caps the buffers kept for reuse, so one unusually large
+// response doesn't pin its memory for the life of the process.
+const maxPooledBuffer = 64 << 10
+
+// ErrEncoding is returned by WriteJSON when the value can't be encoded.
+var ErrEncoding = errors.New("respbuf: encoding response")
+
+// jsonBuffer is a reusable buffer with an encoder writing into it.
+type jsonBuffer struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+var jsonBuffers = sync.Pool{New: func() any {
+	b := new(jsonBuffer)
+	b.enc = json.NewEncoder(&b.buf)
+	return b
+}}
+
+// WriteJSON responds to w with code and v encoded as JSON, exactly as
+// json.Marshal would encode it, but in a pooled buffer instead of one
+// allocated per response. If v can't be encoded, nothing is written and the
+// error wraps ErrEncoding, so the caller can still respond with an error.
+func WriteJSON(w http.ResponseWriter, code int, v any) error {
+	b := jsonBuffers.Get().(*jsonBuffer)
+	defer func() {
+		if b.buf.Cap() <= maxPooledBuffer {
+			b.buf.Reset()
+			jsonBuffers.Put(b)
+		}
+	}()
+	if err := b.enc.Encode(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrEncoding, err)
+	}
+	// Unlike json.Marshal, the encoder ends the value with a newline.
+	body := bytes.TrimSuffix(b.buf.Bytes(), []byte("\n"))
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(code)
+	_, err := w.Write(body)
+	return err
+}
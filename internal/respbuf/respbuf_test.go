@@ -0,0 +1,148 @@
+package respbuf
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name              string
+		writes            []string
+		max               int
+		wantStreaming     bool
+		wantContentLength string
+	}{
+		{name: "small response is buffered", writes: []string{"[", "1", "]"}, max: 16, wantContentLength: "3"},
+		{name: "exactly the limit is buffered", writes: []string{"abcd", "efgh"}, max: 8, wantContentLength: "8"},
+		{name: "large response spills", writes: []string{"abcd", "efgh", "ijkl"}, max: 8, wantStreaming: true},
+		{name: "single oversized write spills", writes: []string{strings.Repeat("x", 100)}, max: 8, wantStreaming: true},
+		{name: "empty body", max: 8, wantContentLength: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			w := New(rec, http.StatusCreated, tt.max)
+			for _, s := range tt.writes {
+				if _, err := w.Write([]byte(s)); err != nil {
+					t.Fatalf("Write: %v", err)
+				}
+			}
+			if w.Streaming() != tt.wantStreaming {
+				t.Errorf("Streaming() = %v, want %v", w.Streaming(), tt.wantStreaming)
+			}
+			if !tt.wantStreaming && rec.Body.Len() != 0 {
+				t.Errorf("body sent before Close: %q", rec.Body.String())
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			if rec.Code != http.StatusCreated {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusCreated)
+			}
+			if got := rec.Body.String(); got != strings.Join(tt.writes, "") {
+				t.Errorf("body = %q, want %q", got, strings.Join(tt.writes, ""))
+			}
+			if got := rec.Header().Get("Content-Length"); got != tt.wantContentLength {
+				t.Errorf("Content-Length = %q, want %q", got, tt.wantContentLength)
+			}
+			if tt.wantStreaming && !rec.Flushed {
+				t.Error("streaming response wasn't flushed")
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		wantErr bool
+	}{
+		{name: "object", v: map[string]string{"error": "<nope> & more"}},
+		{name: "list", v: []int{1, 2, 3}},
+		{name: "null", v: nil},
+		{name: "unencodable", v: map[string]any{"f": func() {}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := WriteJSON(rec, http.StatusCreated, tt.v)
+			if tt.wantErr {
+				if !errors.Is(err, ErrEncoding) {
+					t.Fatalf("WriteJSON() error = %v, want ErrEncoding", err)
+				}
+				if rec.Body.Len() != 0 || rec.Header().Get("Content-Length") != "" {
+					t.Errorf("wrote a response before failing: %q", rec.Body.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteJSON() error = %v", err)
+			}
+			want, _ := json.Marshal(tt.v)
+			if rec.Code != http.StatusCreated || rec.Body.String() != string(want) {
+				t.Errorf("got %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusCreated, want)
+			}
+			if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(want)) {
+				t.Errorf("Content-Length = %q, want %d", got, len(want))
+			}
+		})
+	}
+}
+
+// discardWriter is a ResponseWriter that allocates nothing per response,
+// so the benchmarks below only count the encoding.
+type discardWriter struct{ header http.Header }
+
+func (w discardWriter) Header() http.Header         { return w.header }
+func (w discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w discardWriter) WriteHeader(int)             {}
+
+type benchNote struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+}
+
+var benchPayload = []benchNote{
+	{ID: "0b7f8a52-4d0c-4a55-9a84-5d6c7e2f1a90", Title: "Groceries", Body: strings.Repeat("milk, eggs, ", 40), CreatedAt: "2024-01-01T12:00:00Z"},
+	{ID: "5e0c1d43-8f3b-4b7e-a1f2-6c9d0e4b2a17", Title: "Ideas", Body: strings.Repeat("something clever ", 60), CreatedAt: "2024-01-02T08:30:00Z"},
+}
+
+// BenchmarkWriteJSON's allocations, for the Content-Length header, stay the
+// same however big the response; BenchmarkMarshalJSON allocates a fresh
+// copy of every body.
+func BenchmarkWriteJSON(b *testing.B) {
+	w := discardWriter{header: http.Header{}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := WriteJSON(w, http.StatusOK, benchPayload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	w := discardWriter{header: http.Header{}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dat, err := json.Marshal(benchPayload)
+		if err != nil {
+			b.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(dat); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package rolling counts events over a window of time that rolls forward,
+// split into buckets that are reused as it moves on.
+package rolling
+
+import "time"
+
+// Window holds counts of type C, typically a struct of counters, for each
+// bucket of a rolling window. It isn't safe for concurrent use.
+type Window[C any] struct {
+	bucketSize time.Duration
+	buckets    []bucket[C]
+}
+
+// bucket counts a slice of the window, identified by its index since the
+// epoch so stale buckets can be recognized and reset.
+type bucket[C any] struct {
+	index  int64
+	counts C
+}
+
+// New returns a window of length split into n buckets.
+func New[C any](length time.Duration, n int) *Window[C] {
+	return &Window[C]{bucketSize: length / time.Duration(n), buckets: make([]bucket[C], n)}
+}
+
+// Current returns the counts of the bucket now falls in, to be added to.
+// They start from zero once the bucket is reused for a later slice.
+func (w *Window[C]) Current(now time.Time) *C {
+	index := w.index(now)
+	b := &w.buckets[index%int64(len(w.buckets))]
+	if b.index != index {
+		*b = bucket[C]{index: index}
+	}
+	return &b.counts
+}
+
+// Each calls f with the counts of every bucket in the window ending at now,
+// along with its age in buckets: 0 for the current one.
+func (w *Window[C]) Each(now time.Time, f func(age int, counts C)) {
+	index := w.index(now)
+	for _, b := range w.buckets {
+		if age := index - b.index; age >= 0 && age < int64(len(w.buckets)) {
+			f(int(age), b.counts)
+		}
+	}
+}
+
+func (w *Window[C]) index(now time.Time) int64 {
+	return now.UnixNano() / int64(w.bucketSize)
+}
@@ -0,0 +1,49 @@
+package rolling
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w := New[int](10*time.Second, 10)
+	for _, offset := range []time.Duration{0, 500 * time.Millisecond, 3 * time.Second, 9 * time.Second} {
+		*w.Current(start.Add(offset))++
+	}
+
+	tests := []struct {
+		name    string
+		at      time.Duration
+		want    int
+		wantAge []int
+	}{
+		{name: "all in window", at: 9 * time.Second, want: 4, wantAge: []int{0, 6, 9}},
+		{name: "oldest rolled out", at: 10 * time.Second, want: 2, wantAge: []int{1, 7}},
+		{name: "all rolled out", at: 20 * time.Second, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := 0
+			var ages []int
+			w.Each(start.Add(tt.at), func(age, n int) {
+				sum += n
+				ages = append(ages, age)
+			})
+			if sum != tt.want {
+				t.Errorf("sum = %d, want %d", sum, tt.want)
+			}
+			slices.Sort(ages)
+			if !slices.Equal(ages, tt.wantAge) {
+				t.Errorf("ages = %v, want %v", ages, tt.wantAge)
+			}
+		})
+	}
+
+	// A reused bucket starts over.
+	*w.Current(start.Add(10 * time.Second))++
+	if got := *w.Current(start.Add(10 * time.Second)); got != 1 {
+		t.Errorf("reused bucket = %d, want 1", got)
+	}
+}
@@ -0,0 +1,246 @@
+// Package safefetch downloads user-supplied URLs without letting them reach
+// internal services (server-side request forgery).
+package safefetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrForbiddenAddress   = errors.New("address is not publicly routable")
+	ErrUnsupportedScheme  = errors.New("only http and https URLs can be fetched")
+	ErrTooManyRedirects   = errors.New("too many redirects")
+	ErrTooLarge           = errors.New("response is too large")
+	ErrUnsupportedContent = errors.New("unsupported content type")
+)
+
+// Options limit what a Fetcher downloads. Zero values select the defaults.
+type Options struct {
+	MaxSize      int64         // Largest accepted body in bytes (default 5 MiB).
+	Timeout      time.Duration // Limit for the whole request, body included (default 15s).
+	MaxRedirects int           // Redirects followed before giving up (default 5).
+	ContentTypes []string      // Accepted media types; any when empty.
+}
+
+const (
+	defaultMaxSize      = 5 << 20
+	defaultTimeout      = 15 * time.Second
+	defaultMaxRedirects = 5
+)
+
+const userAgent = "Notely (+https://github.com/DanielSiebert-dev/learn-cicd-starter)"
+
+// Response is a successfully fetched document.
+type Response struct {
+	URL         *url.URL // Final URL, after redirects.
+	ContentType string   // Media type without parameters, e.g. "text/html".
+	Body        []byte
+}
+
+// Fetcher is an HTTP client that only connects to public addresses. The
+// check runs on the resolved IP of every connection, so DNS rebinding and
+// redirects to internal hosts are refused too.
+type Fetcher struct {
+	client *http.Client
+	opts   Options
+}
+
+func New(opts Options) *Fetcher {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = defaultMaxSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.MaxRedirects <= 0 {
+		opts.MaxRedirects = defaultMaxRedirects
+	}
+	return newFetcher(opts, IsPublic)
+}
+
+// newFetcher lets tests allow the loopback addresses httptest listens on.
+func newFetcher(opts Options, allow func(net.IP) bool) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: opts.Timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allow(ip) {
+				return fmt.Errorf("%s: %w", host, ErrForbiddenAddress)
+			}
+			return nil
+		},
+	}
+	return &Fetcher{
+		opts: opts,
+		client: &http.Client{
+			Timeout: opts.Timeout,
+			Transport: &http.Transport{
+				// No proxy: it would connect on our behalf and bypass the address check.
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: opts.Timeout,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > opts.MaxRedirects {
+					return ErrTooManyRedirects
+				}
+				return checkScheme(req.URL)
+			},
+		},
+	}
+}
+
+// HTTPClient returns the underlying client for requests Get doesn't cover,
+// such as POSTs to user-supplied URLs. It refuses internal addresses and
+// applies the timeout, but not the size or content type limits.
+func (f *Fetcher) HTTPClient() *http.Client {
+	return f.client
+}
+
+// Get fetches rawURL, enforcing the Fetcher's limits. accept is sent as the
+// Accept header and may be empty.
+func (f *Fetcher) Get(ctx context.Context, rawURL, accept string) (*Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScheme(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", u, resp.Status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !f.acceptsContentType(mediaType) {
+		return nil, fmt.Errorf("fetching %s: %w %q", u, ErrUnsupportedContent, mediaType)
+	}
+	if resp.ContentLength > f.opts.MaxSize {
+		return nil, fmt.Errorf("fetching %s: %w", u, ErrTooLarge)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.opts.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > f.opts.MaxSize {
+		return nil, fmt.Errorf("fetching %s: %w", u, ErrTooLarge)
+	}
+	return &Response{URL: resp.Request.URL, ContentType: mediaType, Body: body}, nil
+}
+
+// Check reports the final HTTP status of rawURL without downloading its
+// body. It tries HEAD first and falls back to GET for servers that don't
+// support HEAD. An error means the URL couldn't be reached at all.
+func (f *Fetcher) Check(ctx context.Context, rawURL string) (int, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkScheme(u); err != nil {
+		return 0, err
+	}
+
+	status, err := f.status(ctx, http.MethodHead, u)
+	if err != nil || (status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented) {
+		return status, err
+	}
+	return f.status(ctx, http.MethodGet, u)
+}
+
+func (f *Fetcher) status(ctx context.Context, method string, u *url.URL) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (f *Fetcher) acceptsContentType(mediaType string) bool {
+	if len(f.opts.ContentTypes) == 0 {
+		return true
+	}
+	for _, t := range f.opts.ContentTypes {
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func checkScheme(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrUnsupportedScheme
+	}
+	return nil
+}
+
+// reservedNets are ranges that aren't reachable on the public internet but
+// aren't covered by the net.IP predicates.
+var reservedNets = mustParseCIDRs(
+	"0.0.0.0/8",     // "This" network.
+	"100.64.0.0/10", // Carrier-grade NAT.
+	"192.0.0.0/24",  // IETF protocol assignments.
+	"198.18.0.0/15", // Benchmarking.
+	"240.0.0.0/4",   // Reserved, including broadcast.
+	"64:ff9b::/96",  // NAT64, which can embed internal IPv4 addresses.
+	"2001:db8::/32", // Documentation.
+)
+
+// IsPublic reports whether ip is a globally routable unicast address.
+func IsPublic(ip net.IP) bool {
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, n := range reservedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
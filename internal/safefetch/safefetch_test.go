@@ -0,0 +1,179 @@
+package safefetch
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.216.34", want: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{ip: "127.0.0.1", want: false},
+		{ip: "::1", want: false},
+		{ip: "10.1.2.3", want: false},
+		{ip: "172.16.0.1", want: false},
+		{ip: "192.168.1.1", want: false},
+		{ip: "169.254.169.254", want: false},
+		{ip: "fe80::1", want: false},
+		{ip: "fd00::1", want: false},
+		{ip: "0.0.0.0", want: false},
+		{ip: "0.1.2.3", want: false},
+		{ip: "100.64.0.1", want: false},
+		{ip: "255.255.255.255", want: false},
+		{ip: "224.0.0.1", want: false},
+		{ip: "::ffff:127.0.0.1", want: false},
+		{ip: "::ffff:10.0.0.1", want: false},
+		{ip: "64:ff9b::a00:1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := IsPublic(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("IsPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRefusesInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer srv.Close()
+
+	_, err := New(Options{}).Get(context.Background(), srv.URL, "")
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Get(loopback) error = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<p>hello</p>"))
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(strings.Repeat("x", 100)))
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/redirect/", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/redirect/"))
+		if n == 0 {
+			http.Redirect(w, r, "/page", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/redirect/"+strconv.Itoa(n-1), http.StatusFound)
+	})
+	mux.HandleFunc("/to-file", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	allowAll := func(net.IP) bool { return true }
+	f := newFetcher(Options{
+		MaxSize:      50,
+		Timeout:      defaultTimeout,
+		MaxRedirects: 2,
+		ContentTypes: []string{"text/html"},
+	}, allowAll)
+
+	tests := []struct {
+		name     string
+		url      string
+		wantPath string
+		wantErr  error
+	}{
+		{name: "html page", url: srv.URL + "/page", wantPath: "/page"},
+		{name: "redirects within the limit", url: srv.URL + "/redirect/1", wantPath: "/page"},
+		{name: "too many redirects", url: srv.URL + "/redirect/5", wantErr: ErrTooManyRedirects},
+		{name: "redirect to another scheme", url: srv.URL + "/to-file", wantErr: ErrUnsupportedScheme},
+		{name: "unexpected content type", url: srv.URL + "/json", wantErr: ErrUnsupportedContent},
+		{name: "body over the size limit", url: srv.URL + "/big", wantErr: ErrTooLarge},
+		{name: "unsupported scheme", url: "gopher://example.com", wantErr: ErrUnsupportedScheme},
+		{name: "relative url", url: "/page", wantErr: ErrUnsupportedScheme},
+		{name: "error status", url: srv.URL + "/missing", wantErr: errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := f.Get(context.Background(), tt.url, "text/html")
+			if tt.wantErr != nil {
+				if err == nil || (tt.wantErr != errAny && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("Get() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if resp.URL.Path != tt.wantPath || resp.ContentType != "text/html" || string(resp.Body) != "<p>hello</p>" {
+				t.Errorf("Get() = %s %q %q, want %s text/html <p>hello</p>", resp.URL.Path, resp.ContentType, resp.Body, tt.wantPath)
+			}
+		})
+	}
+}
+
+// errAny marks test cases where any error is expected.
+var errAny = errors.New("any error")
+
+func TestCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/gone", http.StatusMovedPermanently)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newFetcher(Options{Timeout: defaultTimeout, MaxRedirects: defaultMaxRedirects}, func(net.IP) bool { return true })
+
+	tests := []struct {
+		name    string
+		url     string
+		want    int
+		wantErr bool
+	}{
+		{name: "ok", url: srv.URL + "/ok", want: http.StatusOK},
+		{name: "gone", url: srv.URL + "/gone", want: http.StatusGone},
+		{name: "falls back to GET", url: srv.URL + "/get-only", want: http.StatusOK},
+		{name: "follows redirects", url: srv.URL + "/moved", want: http.StatusGone},
+		{name: "unsupported scheme", url: "mailto:a@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.Check(context.Background(), tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Check() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package slo tracks latency and error objectives per route over a rolling
+// window, and detects when a route spends its error budget too fast.
+package slo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/rolling"
+)
+
+const (
+	// DefaultWindow is the rolling window compliance is computed over.
+	DefaultWindow = time.Hour
+	// DefaultAlertBurnRate is the burn rate that triggers an alert: at 14.4
+	// a 30-day budget is gone in about two days.
+	DefaultAlertBurnRate = 14.4
+
+	// bucketsPerWindow is how finely the window rolls.
+	bucketsPerWindow = 60
+	// shortWindowBuckets is the recent part of the window that must also be
+	// burning for an alert, so one that has already stopped doesn't fire.
+	shortWindowBuckets = bucketsPerWindow / 12
+	// minAlertRequests is how many requests the short window needs before a
+	// few failures can raise an alert.
+	minAlertRequests = 20
+)
+
+// CatchAll is the route of an objective that applies to every route without
+// one of its own.
+const CatchAll = "*"
+
+// Objective is a route's SLO: Target of its requests should be good, that
+// is neither fail with a 5xx status nor take longer than Latency.
+type Objective struct {
+	Route   string        // "METHOD /pattern", e.g. "GET /v1/notes/{noteID}", or CatchAll.
+	Latency time.Duration // Slower requests count against the budget.
+	Target  float64       // E.g. 0.99; the budget is the remaining 0.01.
+}
+
+// Config is the document read from SLO_FILE, e.g.
+//
+//	{"window": "1h", "alert_burn_rate": 14.4, "objectives": [
+//	    {"route": "GET /v1/notes", "latency": "300ms", "target": 0.99},
+//	    {"route": "*", "latency": "1s", "target": 0.95}]}
+type Config struct {
+	Window        time.Duration
+	AlertBurnRate float64
+	Objectives    []Objective
+}
+
+// Load reads and validates a config file, filling in defaults.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from operator configuration.
+	if err != nil {
+		return Config{}, err
+	}
+	var file struct {
+		Window        string  `json:"window"`
+		AlertBurnRate float64 `json:"alert_burn_rate"`
+		Objectives    []struct {
+			Route   string  `json:"route"`
+			Latency string  `json:"latency"`
+			Target  float64 `json:"target"`
+		} `json:"objectives"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Config{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	cfg := Config{Window: DefaultWindow, AlertBurnRate: file.AlertBurnRate}
+	if file.Window != "" {
+		if cfg.Window, err = time.ParseDuration(file.Window); err != nil || cfg.Window < bucketsPerWindow*time.Second {
+			return Config{}, fmt.Errorf("window must be a duration of at least %s", bucketsPerWindow*time.Second)
+		}
+	}
+	if cfg.AlertBurnRate == 0 {
+		cfg.AlertBurnRate = DefaultAlertBurnRate
+	}
+	if cfg.AlertBurnRate < 1 {
+		return Config{}, errors.New("alert_burn_rate must be at least 1")
+	}
+	seen := make(map[string]bool)
+	for i, o := range file.Objectives {
+		if o.Route == "" || seen[o.Route] {
+			return Config{}, fmt.Errorf("objectives[%d]: route is required and must be unique", i)
+		}
+		seen[o.Route] = true
+		latency, err := time.ParseDuration(o.Latency)
+		if err != nil || latency <= 0 {
+			return Config{}, fmt.Errorf("objectives[%d]: latency must be a positive duration", i)
+		}
+		if o.Target <= 0 || o.Target >= 1 {
+			return Config{}, fmt.Errorf("objectives[%d]: target must be between 0 and 1", i)
+		}
+		cfg.Objectives = append(cfg.Objectives, Objective{Route: o.Route, Latency: latency, Target: o.Target})
+	}
+	if len(cfg.Objectives) == 0 {
+		return Config{}, errors.New("at least one objective is required")
+	}
+	return cfg, nil
+}
+
+// Status is an objective's compliance over the current window.
+type Status struct {
+	Route           string  `json:"route"`
+	Latency         string  `json:"latency"`
+	Target          float64 `json:"target"`
+	Window          string  `json:"window"`
+	Requests        int64   `json:"requests"`
+	Good            int64   `json:"good"`
+	Compliance      float64 `json:"compliance"`       // Good/Requests; 1 without requests.
+	BudgetRemaining float64 `json:"budget_remaining"` // Share of the error budget left; negative once overspent.
+	BurnRate        float64 `json:"burn_rate"`        // How many times faster than sustainable the budget is spent.
+	ShortBurnRate   float64 `json:"short_burn_rate"`  // The same over the last twelfth of the window.
+}
+
+// Alert is raised when a route's burn rate exceeds the configured one over
+// both the whole window and its recent part.
+type Alert struct {
+	Status
+	AlertBurnRate float64 `json:"alert_burn_rate"`
+}
+
+// counts are the requests to a route in a slice of the window.
+type counts struct {
+	total int64
+	bad   int64
+}
+
+type tracked struct {
+	objective Objective
+	window    *rolling.Window[counts]
+	alertedAt time.Time
+}
+
+// Tracker records requests against their route's objective.
+type Tracker struct {
+	window        time.Duration
+	alertBurnRate float64
+	alert         func(Alert)
+	now           func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*tracked
+	order  []string
+}
+
+// NewTracker tracks cfg's objectives, calling alert (in its own goroutine)
+// at most once per window and route when the budget burns too fast.
+func NewTracker(cfg Config, alert func(Alert)) *Tracker {
+	t := &Tracker{
+		window:        cfg.Window,
+		alertBurnRate: cfg.AlertBurnRate,
+		alert:         alert,
+		now:           time.Now,
+		routes:        make(map[string]*tracked),
+	}
+	for _, o := range cfg.Objectives {
+		t.routes[o.Route] = &tracked{objective: o, window: rolling.New[counts](cfg.Window, bucketsPerWindow)}
+		t.order = append(t.order, o.Route)
+	}
+	return t
+}
+
+// Record counts a request to route, answered with status after took.
+// Routes without an objective count towards the CatchAll one, if any.
+func (t *Tracker) Record(route string, status int, took time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tr := t.routes[route]
+	if tr == nil {
+		if tr = t.routes[CatchAll]; tr == nil {
+			return
+		}
+	}
+
+	now := t.now()
+	c := tr.window.Current(now)
+	c.total++
+	if status < 500 && took <= tr.objective.Latency {
+		return
+	}
+	c.bad++
+
+	if t.alert == nil || (!tr.alertedAt.IsZero() && now.Sub(tr.alertedAt) < t.window) {
+		return
+	}
+	s, shortTotal := t.status(tr, now)
+	if shortTotal >= minAlertRequests && s.BurnRate >= t.alertBurnRate && s.ShortBurnRate >= t.alertBurnRate {
+		tr.alertedAt = now
+		go t.alert(Alert{Status: s, AlertBurnRate: t.alertBurnRate})
+	}
+}
+
+// Report returns the status of every objective, in configuration order.
+func (t *Tracker) Report() []Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	statuses := make([]Status, 0, len(t.order))
+	for _, route := range t.order {
+		s, _ := t.status(t.routes[route], now)
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// status sums tr's requests within the window ending at now. It also
+// returns the number of requests in the short window.
+func (t *Tracker) status(tr *tracked, now time.Time) (Status, int64) {
+	var total, bad, shortTotal, shortBad int64
+	tr.window.Each(now, func(age int, c counts) {
+		total += c.total
+		bad += c.bad
+		if age < shortWindowBuckets {
+			shortTotal += c.total
+			shortBad += c.bad
+		}
+	})
+
+	budget := 1 - tr.objective.Target
+	s := Status{
+		Route:           tr.objective.Route,
+		Latency:         tr.objective.Latency.String(),
+		Target:          tr.objective.Target,
+		Window:          t.window.String(),
+		Requests:        total,
+		Good:            total - bad,
+		Compliance:      1,
+		BudgetRemaining: 1,
+	}
+	if total > 0 {
+		s.Compliance = float64(total-bad) / float64(total)
+		s.BurnRate = float64(bad) / float64(total) / budget
+		s.BudgetRemaining = 1 - s.BurnRate
+	}
+	if shortTotal > 0 {
+		s.ShortBurnRate = float64(shortBad) / float64(shortTotal) / budget
+	}
+	return s, shortTotal
+}
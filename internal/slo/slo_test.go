@@ -0,0 +1,165 @@
+package slo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestTracker(alert func(Alert)) (*Tracker, *time.Time) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	t := NewTracker(Config{
+		Window:        time.Hour,
+		AlertBurnRate: DefaultAlertBurnRate,
+		Objectives: []Objective{
+			{Route: "GET /v1/notes", Latency: 300 * time.Millisecond, Target: 0.99},
+			{Route: CatchAll, Latency: time.Second, Target: 0.9},
+		},
+	}, alert)
+	t.now = func() time.Time { return now }
+	return t, &now
+}
+
+func TestTrackerReport(t *testing.T) {
+	tracker, now := newTestTracker(nil)
+	for i := 0; i < 96; i++ {
+		tracker.Record("GET /v1/notes", 200, 100*time.Millisecond)
+	}
+	tracker.Record("GET /v1/notes", 500, 100*time.Millisecond)
+	tracker.Record("GET /v1/notes", 200, 400*time.Millisecond)
+	tracker.Record("GET /v1/notes", 404, 100*time.Millisecond)
+	tracker.Record("GET /v1/notes", 200, 300*time.Millisecond)
+	tracker.Record("POST /v1/notes", 503, 0)
+
+	*now = now.Add(30 * time.Minute)
+	tracker.Record("POST /v1/notes", 201, 0)
+
+	tests := []struct {
+		name           string
+		route          string
+		wantRequests   int64
+		wantGood       int64
+		wantCompliance float64
+		wantBurnRate   float64
+	}{
+		{name: "5xx and slow requests are bad", route: "GET /v1/notes", wantRequests: 100, wantGood: 98, wantCompliance: 0.98, wantBurnRate: 2},
+		{name: "other routes count towards the catch-all", route: CatchAll, wantRequests: 2, wantGood: 1, wantCompliance: 0.5, wantBurnRate: 5},
+	}
+	statuses := tracker.Report()
+	if len(statuses) != len(tests) {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := statuses[i]
+			if s.Route != tt.route || s.Requests != tt.wantRequests || s.Good != tt.wantGood {
+				t.Errorf("got %s with %d/%d good, want %s with %d/%d", s.Route, s.Good, s.Requests, tt.route, tt.wantGood, tt.wantRequests)
+			}
+			if !approx(s.Compliance, tt.wantCompliance) || !approx(s.BurnRate, tt.wantBurnRate) {
+				t.Errorf("got compliance %v, burn rate %v, want %v, %v", s.Compliance, s.BurnRate, tt.wantCompliance, tt.wantBurnRate)
+			}
+		})
+	}
+}
+
+func TestTrackerWindowRolls(t *testing.T) {
+	tracker, now := newTestTracker(nil)
+	tracker.Record("GET /v1/notes", 500, 0)
+	*now = now.Add(59 * time.Minute)
+	tracker.Record("GET /v1/notes", 200, 0)
+
+	if s := tracker.Report()[0]; s.Requests != 2 {
+		t.Fatalf("within the window: got %d requests, want 2", s.Requests)
+	}
+	*now = now.Add(2 * time.Minute)
+	s := tracker.Report()[0]
+	if s.Requests != 1 || s.Good != 1 {
+		t.Errorf("after the window: got %d/%d good, want 1/1", s.Good, s.Requests)
+	}
+	if s.ShortBurnRate != 0 || s.BudgetRemaining != 1 {
+		t.Errorf("got short burn rate %v, budget remaining %v, want 0, 1", s.ShortBurnRate, s.BudgetRemaining)
+	}
+}
+
+func TestTrackerAlerts(t *testing.T) {
+	tests := []struct {
+		name      string
+		good, bad int
+		wantAlert bool
+	}{
+		{name: "fast burn alerts", good: 80, bad: 20, wantAlert: true},
+		{name: "slow burn doesn't", good: 990, bad: 10, wantAlert: false},
+		{name: "too few requests don't", good: 0, bad: 10, wantAlert: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := make(chan Alert, 10)
+			tracker, _ := newTestTracker(func(a Alert) { alerts <- a })
+			for i := 0; i < tt.good; i++ {
+				tracker.Record("GET /v1/notes", 200, 0)
+			}
+			for i := 0; i < tt.bad; i++ {
+				tracker.Record("GET /v1/notes", 500, 0)
+			}
+
+			select {
+			case a := <-alerts:
+				if !tt.wantAlert {
+					t.Fatalf("got alert %+v, want none", a)
+				}
+				if a.Route != "GET /v1/notes" || a.BurnRate < DefaultAlertBurnRate {
+					t.Errorf("got alert for %s at burn rate %v", a.Route, a.BurnRate)
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tt.wantAlert {
+					t.Fatal("got no alert")
+				}
+			}
+			select {
+			case a := <-alerts:
+				t.Errorf("got second alert %+v within the window", a)
+			case <-time.After(20 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+	}{
+		{name: "defaults", file: `{"objectives": [{"route": "*", "latency": "1s", "target": 0.99}]}`},
+		{name: "custom window", file: `{"window": "6h", "alert_burn_rate": 6, "objectives": [{"route": "*", "latency": "1s", "target": 0.99}]}`},
+		{name: "no objectives", file: `{}`, wantErr: true},
+		{name: "duplicate route", file: `{"objectives": [{"route": "*", "latency": "1s", "target": 0.9}, {"route": "*", "latency": "2s", "target": 0.9}]}`, wantErr: true},
+		{name: "bad latency", file: `{"objectives": [{"route": "*", "latency": "fast", "target": 0.99}]}`, wantErr: true},
+		{name: "target of 1", file: `{"objectives": [{"route": "*", "latency": "1s", "target": 1}]}`, wantErr: true},
+		{name: "window too short", file: `{"window": "10s", "objectives": [{"route": "*", "latency": "1s", "target": 0.99}]}`, wantErr: true},
+		{name: "burn rate below 1", file: `{"alert_burn_rate": 0.5, "objectives": [{"route": "*", "latency": "1s", "target": 0.99}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "slo.json")
+			if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (cfg.Window <= 0 || cfg.AlertBurnRate < 1) {
+				t.Errorf("got window %s, alert burn rate %v", cfg.Window, cfg.AlertBurnRate)
+			}
+		})
+	}
+}
+
+func approx(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
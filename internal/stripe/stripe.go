@@ -0,0 +1,250 @@
+// Package stripe handles the parts of Stripe that paid plans need: webhook
+// events about subscriptions, and customer portal sessions where users
+// manage them.
+package stripe
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds a single call to the Stripe API.
+const requestTimeout = 30 * time.Second
+
+// signatureTolerance is how old a webhook signature may be, so captured
+// requests can't be replayed later.
+const signatureTolerance = 5 * time.Minute
+
+// Webhook event types the API acts on.
+const (
+	EventCheckoutCompleted   = "checkout.session.completed"
+	EventSubscriptionCreated = "customer.subscription.created"
+	EventSubscriptionUpdated = "customer.subscription.updated"
+	EventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// ErrInvalidSignature is returned by ParseEvent for payloads that weren't
+// signed with the webhook secret within the tolerance.
+var ErrInvalidSignature = errors.New("invalid Stripe signature")
+
+// Client calls the Stripe API and checks webhook signatures.
+type Client struct {
+	baseURL       string
+	secretKey     string
+	webhookSecret string
+	plans         map[string]string // Plan IDs by price ID.
+	client        *http.Client
+	now           func() time.Time // Signature checking time; replaced in tests.
+}
+
+// New creates a client for the API at baseURL, normally
+// "https://api.stripe.com". plans maps the price IDs of subscriptions to
+// the plans they put users on.
+func New(baseURL, secretKey, webhookSecret string, plans map[string]string) *Client {
+	return &Client{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		plans:         plans,
+		client:        &http.Client{Timeout: requestTimeout},
+		now:           time.Now,
+	}
+}
+
+// FromEnv returns the client configured by STRIPE_SECRET_KEY,
+// STRIPE_WEBHOOK_SECRET and STRIPE_PRICE_PLANS, a comma-separated list of
+// price=plan pairs, or nil if STRIPE_SECRET_KEY is unset.
+func FromEnv(getenv func(string) string) (*Client, error) {
+	if getenv("STRIPE_SECRET_KEY") == "" {
+		return nil, nil
+	}
+	if getenv("STRIPE_WEBHOOK_SECRET") == "" {
+		return nil, fmt.Errorf("STRIPE_WEBHOOK_SECRET is required with STRIPE_SECRET_KEY")
+	}
+	plans := make(map[string]string)
+	for _, pair := range strings.Split(getenv("STRIPE_PRICE_PLANS"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		price, plan, ok := strings.Cut(pair, "=")
+		price, plan = strings.TrimSpace(price), strings.TrimSpace(plan)
+		if !ok || price == "" || plan == "" {
+			return nil, fmt.Errorf("STRIPE_PRICE_PLANS entries must be price=plan, got %q", pair)
+		}
+		plans[price] = plan
+	}
+	baseURL := getenv("STRIPE_API_URL")
+	if baseURL == "" {
+		baseURL = "https://api.stripe.com"
+	}
+	return New(baseURL, getenv("STRIPE_SECRET_KEY"), getenv("STRIPE_WEBHOOK_SECRET"), plans), nil
+}
+
+// Event is a webhook event. Data.Object is the object it's about, such as
+// a Subscription or CheckoutSession depending on Type.
+type Event struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"` // Unix seconds.
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// Subscription is the object of customer.subscription.* events.
+type Subscription struct {
+	ID       string            `json:"id"`
+	Customer string            `json:"customer"`
+	Status   string            `json:"status"`
+	Metadata map[string]string `json:"metadata"`
+	Items    struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// Entitled reports whether the subscription should keep its plan: it's
+// active, in a trial, or has a payment being retried.
+func (s Subscription) Entitled() bool {
+	switch s.Status {
+	case "active", "trialing", "past_due":
+		return true
+	}
+	return false
+}
+
+// CheckoutSession is the object of checkout.session.completed events.
+// ClientReferenceID is whatever the checkout link was created with, here
+// the ID of the user who's subscribing.
+type CheckoutSession struct {
+	ID                string `json:"id"`
+	Customer          string `json:"customer"`
+	ClientReferenceID string `json:"client_reference_id"`
+	Subscription      string `json:"subscription"`
+}
+
+// ParseEvent checks that payload was signed with the webhook secret, as
+// given in the Stripe-Signature header sig, and decodes it.
+func (c *Client) ParseEvent(payload []byte, sig string) (Event, error) {
+	if err := verifySignature(payload, sig, c.webhookSecret, c.now(), signatureTolerance); err != nil {
+		return Event{}, err
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+// verifySignature checks a Stripe-Signature header of the form
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "t.payload">,...". Any of the
+// v1 signatures may match, as there are several while a secret is rolled.
+func verifySignature(payload []byte, header, secret string, now time.Time, tolerance time.Duration) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	secs, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(secs, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Plan returns the plan a subscription puts its customer on: that of the
+// first of its prices with one. ok is false if none of them has a plan.
+func (c *Client) Plan(sub Subscription) (plan string, ok bool) {
+	for _, item := range sub.Items.Data {
+		if plan, ok := c.plans[item.Price.ID]; ok {
+			return plan, true
+		}
+	}
+	return "", false
+}
+
+// GetSubscription returns the subscription id as it is now.
+func (c *Client) GetSubscription(ctx context.Context, id string) (Subscription, error) {
+	var sub Subscription
+	err := c.call(ctx, http.MethodGet, "/v1/subscriptions/"+url.PathEscape(id), nil, &sub)
+	return sub, err
+}
+
+// CreatePortalSession starts a customer portal session, where customerID
+// can change or cancel their subscription and update how they pay, and
+// returns its URL. The portal links back to returnURL.
+func (c *Client) CreatePortalSession(ctx context.Context, customerID, returnURL string) (string, error) {
+	form := url.Values{"customer": {customerID}}
+	if returnURL != "" {
+		form.Set("return_url", returnURL)
+	}
+	var session struct {
+		URL string `json:"url"`
+	}
+	err := c.call(ctx, http.MethodPost, "/v1/billing_portal/sessions", form, &session)
+	return session.URL, err
+}
+
+// call sends form, if any, to the API endpoint path and decodes the JSON
+// response into out.
+func (c *Client) call(ctx context.Context, method, path string, form url.Values, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.secretKey, "")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s failed: %s: %s", method, path, resp.Status, bytes.TrimSpace(msg))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
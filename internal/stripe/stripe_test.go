@@ -0,0 +1,144 @@
+package stripe
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func sign(payload, secret string, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantNil   bool
+		wantErr   bool
+		wantPlans map[string]string
+	}{
+		{name: "disabled by default", env: map[string]string{}, wantNil: true},
+		{name: "no webhook secret", env: map[string]string{"STRIPE_SECRET_KEY": "sk"}, wantErr: true},
+		{name: "bad price plans", env: map[string]string{"STRIPE_SECRET_KEY": "sk", "STRIPE_WEBHOOK_SECRET": "wh", "STRIPE_PRICE_PLANS": "price_1"}, wantErr: true},
+		{
+			name:      "price plans",
+			env:       map[string]string{"STRIPE_SECRET_KEY": "sk", "STRIPE_WEBHOOK_SECRET": "wh", "STRIPE_PRICE_PLANS": "price_1=pro, price_2 = pro,"},
+			wantPlans: map[string]string{"price_1": "pro", "price_2": "pro"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := FromEnv(func(k string) string { return tt.env[k] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (c == nil) != tt.wantNil {
+				t.Fatalf("FromEnv() = %v, wantNil %v", c, tt.wantNil)
+			}
+			for price, want := range tt.wantPlans {
+				if got := c.plans[price]; got != want {
+					t.Errorf("plans[%q] = %q, want %q", price, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := `{"id":"evt_1","type":"customer.subscription.updated","created":1700000000,"data":{"object":{"id":"sub_1"}}}`
+	valid := sign(payload, "whsec", now)
+	tests := []struct {
+		name    string
+		header  string
+		wantErr bool
+	}{
+		{name: "valid", header: "t=1700000000,v1=" + valid},
+		{name: "one of several signatures", header: "t=1700000000,v1=00ff,v1=" + valid + ",v0=abc"},
+		{name: "wrong secret", header: "t=1700000000,v1=" + sign(payload, "other", now), wantErr: true},
+		{name: "too old", header: "t=1699999000,v1=" + sign(payload, "whsec", now.Add(-1000*time.Second)), wantErr: true},
+		{name: "no timestamp", header: "v1=" + valid, wantErr: true},
+		{name: "no signature", header: "t=1700000000", wantErr: true},
+		{name: "empty", header: "", wantErr: true},
+	}
+
+	c := New("http://stripe.invalid", "sk", "whsec", nil)
+	c.now = func() time.Time { return now }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := c.ParseEvent([]byte(payload), tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (event.ID != "evt_1" || event.Type != EventSubscriptionUpdated) {
+				t.Errorf("ParseEvent() = %+v", event)
+			}
+		})
+	}
+
+	// A signed payload that has been changed doesn't verify.
+	if _, err := c.ParseEvent([]byte(payload+" "), "t=1700000000,v1="+valid); err == nil {
+		t.Error("ParseEvent() of a changed payload succeeded")
+	}
+}
+
+func TestAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "sk_test" {
+			http.Error(w, `{"error":{"message":"Invalid API Key"}}`, http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/billing_portal/sessions":
+			if r.FormValue("customer") != "cus_1" || r.FormValue("return_url") != "https://notes.example.com" {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"id":"bps_1","url":"https://billing.stripe.com/session/abc"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/subscriptions/sub_1":
+			w.Write([]byte(`{"id":"sub_1","customer":"cus_1","status":"active","items":{"data":[{"price":{"id":"price_other"}},{"price":{"id":"price_pro"}}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	c := New(srv.URL, "sk_test", "whsec", map[string]string{"price_pro": "pro"})
+	sub, err := c.GetSubscription(ctx, "sub_1")
+	if err != nil {
+		t.Fatalf("GetSubscription() error = %v", err)
+	}
+	if sub.Customer != "cus_1" || !sub.Entitled() {
+		t.Errorf("GetSubscription() = %+v", sub)
+	}
+	if plan, ok := c.Plan(sub); plan != "pro" || !ok {
+		t.Errorf("Plan() = %q, %v, want pro", plan, ok)
+	}
+
+	got, err := c.CreatePortalSession(ctx, "cus_1", "https://notes.example.com")
+	if err != nil {
+		t.Fatalf("CreatePortalSession() error = %v", err)
+	}
+	if got != "https://billing.stripe.com/session/abc" {
+		t.Errorf("CreatePortalSession() = %q", got)
+	}
+
+	if _, err := New(srv.URL, "wrong", "whsec", nil).CreatePortalSession(ctx, "cus_1", ""); err == nil {
+		t.Error("CreatePortalSession() with a wrong key succeeded")
+	}
+}
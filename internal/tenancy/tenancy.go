@@ -0,0 +1,351 @@
+// Package tenancy routes each organization ("tenant") of a hosted
+// deployment to a database of its own, so tenants' data is isolated and
+// each can be backed up and restored on its own. Which database a tenant
+// uses is looked up in a mapping table, and connections to them are cached.
+// Tenants can pick a region for their data to reside in, whose database
+// servers their databases are then on.
+package tenancy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"golang.org/x/sync/singleflight"
+)
+
+// Header names the tenant of a request that can't be sent to its
+// subdomain, e.g. in development.
+const Header = "X-Tenant"
+
+const (
+	// DefaultMaxOpen is how many tenant databases are kept open at once.
+	DefaultMaxOpen = 100
+
+	// recheckInterval is how often a cached tenant's database is looked up
+	// again, so a mapping changed through another instance takes effect.
+	recheckInterval = time.Minute
+
+	// closeDelay is how long a connection that was evicted or replaced
+	// stays open for the requests still using it.
+	closeDelay = time.Minute
+)
+
+// ErrUnknownTenant is returned for tenants that aren't in the mapping table.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// Conn is an open tenant database.
+type Conn struct {
+	ID     string
+	Region string        // Where the tenant's data resides; "" if it didn't pick a region.
+	DB     *sql.DB       // For starting transactions.
+	DBTX   database.DBTX // DB with the same instrumentation as the default database.
+
+	url string
+}
+
+// Location is where a tenant's data is.
+type Location struct {
+	DatabaseURL string
+	Region      string
+}
+
+// Config configures a Router.
+type Config struct {
+	// Lookup returns the location of a tenant, or ErrUnknownTenant.
+	Lookup func(ctx context.Context, id string) (Location, error)
+	// Open connects to a database URL.
+	Open func(url string) (*sql.DB, database.DBTX, error)
+	// MaxOpen is how many tenant databases are kept open at once; the ones
+	// used least recently are closed beyond it.
+	MaxOpen int
+}
+
+// Router hands out connections to tenant databases.
+type Router struct {
+	cfg        Config
+	clock      clock.Clock
+	closeDelay time.Duration
+
+	mu      sync.Mutex
+	conns   map[string]*entry
+	connect singleflight.Group // Collapses concurrent lookups of the same tenant.
+}
+
+type entry struct {
+	conn      *Conn
+	lastUsed  time.Time
+	checkedAt time.Time
+}
+
+func NewRouter(cfg Config, clock clock.Clock) *Router {
+	if cfg.MaxOpen <= 0 {
+		cfg.MaxOpen = DefaultMaxOpen
+	}
+	return &Router{
+		cfg:        cfg,
+		clock:      clock,
+		closeDelay: closeDelay,
+		conns:      make(map[string]*entry),
+	}
+}
+
+// ValidID reports whether id can name a tenant: a DNS label of lowercase
+// letters, digits and hyphens, so it can be a subdomain.
+func ValidID(id string) bool {
+	if id == "" || len(id) > 63 || id[0] == '-' || id[len(id)-1] == '-' {
+		return false
+	}
+	for _, c := range id {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// FromRequest returns the tenant r is for: the subdomain of domain it was
+// sent to, e.g. "acme" for acme.notes.example.com, or else its Header. It
+// returns "" for requests to domain itself or to other hosts without the
+// header, which use the default database.
+func FromRequest(r *http.Request, domain string) string {
+	if domain != "" {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if sub, ok := strings.CutSuffix(host, "."+strings.ToLower(domain)); ok {
+			return sub
+		}
+	}
+	return strings.ToLower(r.Header.Get(Header))
+}
+
+// Get returns a connection to the database of tenant id.
+func (r *Router) Get(ctx context.Context, id string) (*Conn, error) {
+	if !ValidID(id) {
+		return nil, ErrUnknownTenant
+	}
+	now := r.clock.Now()
+	r.mu.Lock()
+	if e, ok := r.conns[id]; ok && now.Sub(e.checkedAt) < recheckInterval {
+		e.lastUsed = now
+		r.mu.Unlock()
+		return e.conn, nil
+	}
+	r.mu.Unlock()
+
+	v, err, _ := r.connect.Do(id, func() (any, error) {
+		// Other requests may be waiting on the result even if this one is canceled.
+		return r.open(context.WithoutCancel(ctx), id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Conn), nil
+}
+
+// open looks up the database of tenant id and connects to it, reusing the
+// cached connection unless the tenant was moved to another database or
+// region.
+func (r *Router) open(ctx context.Context, id string) (*Conn, error) {
+	loc, err := r.cfg.Lookup(ctx, id)
+	if errors.Is(err, ErrUnknownTenant) {
+		r.Forget(id)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.clock.Now()
+	r.mu.Lock()
+	if e, ok := r.conns[id]; ok && e.conn.url == loc.DatabaseURL && e.conn.Region == loc.Region {
+		e.lastUsed, e.checkedAt = now, now
+		r.mu.Unlock()
+		return e.conn, nil
+	}
+	r.mu.Unlock()
+
+	db, dbtx, err := r.cfg.Open(loc.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	conn := &Conn{ID: id, Region: loc.Region, DB: db, DBTX: dbtx, url: loc.DatabaseURL}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.conns[id]; ok {
+		r.retire(old.conn)
+	}
+	r.conns[id] = &entry{conn: conn, lastUsed: now, checkedAt: now}
+	for len(r.conns) > r.cfg.MaxOpen {
+		var oldest string
+		for id, e := range r.conns {
+			if oldest == "" || e.lastUsed.Before(r.conns[oldest].lastUsed) {
+				oldest = id
+			}
+		}
+		r.retire(r.conns[oldest].conn)
+		delete(r.conns, oldest)
+	}
+	return conn, nil
+}
+
+// Forget drops the cached connection of tenant id, e.g. after its mapping
+// changed, so the next Get looks it up again.
+func (r *Router) Forget(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.conns[id]; ok {
+		r.retire(e.conn)
+		delete(r.conns, id)
+	}
+}
+
+// Close closes every cached connection, for shutdown.
+func (r *Router) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, e := range r.conns {
+		e.conn.DB.Close()
+		delete(r.conns, id)
+	}
+}
+
+// retire closes conn once the requests that might still be using it are
+// done. r.mu must be held.
+func (r *Router) retire(conn *Conn) {
+	if r.closeDelay == 0 {
+		conn.DB.Close()
+		return
+	}
+	time.AfterFunc(r.closeDelay, func() { conn.DB.Close() })
+}
+
+// Regions maps each region tenants can pick to a template of the URLs of
+// its tenants' databases, in which {tenant} stands for the tenant ID, e.g.
+// "libsql://{tenant}-acme.aws-eu-west-1.turso.io?authToken=...".
+type Regions map[string]string
+
+// ParseRegions reads a comma-separated list of region=template pairs, as in
+// TENANT_REGIONS. Templates must be URLs with {tenant} in their host or,
+// for those without a host such as local files, their path.
+func ParseRegions(s string) (Regions, error) {
+	regions := make(Regions)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		region, template, ok := strings.Cut(pair, "=")
+		region, template = strings.TrimSpace(region), strings.TrimSpace(template)
+		if !ok || region == "" {
+			return nil, fmt.Errorf("tenant regions must be region=template, got %q", pair)
+		}
+		if _, _, err := templatePattern(template); err != nil {
+			return nil, fmt.Errorf("tenant region %s: %w", region, err)
+		}
+		regions[region] = template
+	}
+	return regions, nil
+}
+
+// DatabaseURL returns the URL of the database of tenant id in region, and
+// false if there's no such region.
+func (r Regions) DatabaseURL(region, id string) (string, bool) {
+	template, ok := r[region]
+	if !ok {
+		return "", false
+	}
+	return strings.ReplaceAll(template, "{tenant}", id), true
+}
+
+// InRegion reports whether databaseURL could be a database in region: its
+// host is the host of region's template with {tenant} standing for a name
+// of its own, which can't span labels, e.g. a database restored under
+// another name. For templates without a host, such as local files, the path
+// is compared instead, and {tenant} can't span directories. Query
+// parameters, such as auth tokens, may differ.
+func (r Regions) InRegion(region, databaseURL string) bool {
+	template, ok := r[region]
+	if !ok {
+		return false
+	}
+	scheme, pattern, err := templatePattern(template)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(databaseURL)
+	if err != nil || !strings.EqualFold(u.Scheme, scheme) {
+		return false
+	}
+	name := urlPath(u)
+	if u.Host != "" {
+		name = strings.ToLower(u.Hostname())
+	}
+	before, after, _ := strings.Cut(pattern, tenantPlaceholder)
+	if len(name) <= len(before)+len(after) || !strings.HasPrefix(name, before) || !strings.HasSuffix(name, after) {
+		return false
+	}
+	return !strings.ContainsAny(name[len(before):len(name)-len(after)], "./")
+}
+
+// tenantPlaceholder stands in for {tenant} while templates are parsed as
+// URLs, which can't have braces in their host.
+const tenantPlaceholder = "tenant-placeholder"
+
+// templatePattern returns the scheme of a region's URL template and its
+// host, or path if it has no host, with {tenant} as tenantPlaceholder.
+func templatePattern(template string) (scheme, pattern string, err error) {
+	if strings.Count(template, "{tenant}") != 1 {
+		return "", "", fmt.Errorf("template %q must contain {tenant} once", template)
+	}
+	t, err := url.Parse(strings.Replace(template, "{tenant}", tenantPlaceholder, 1))
+	if err != nil {
+		return "", "", err
+	}
+	pattern = urlPath(t)
+	if t.Host != "" {
+		pattern = strings.ToLower(t.Hostname())
+	}
+	if !strings.Contains(pattern, tenantPlaceholder) {
+		return "", "", fmt.Errorf("template %q must have {tenant} in its host, or its path if it has no host", template)
+	}
+	return t.Scheme, pattern, nil
+}
+
+// urlPath returns the path of u, which is opaque in URLs such as
+// file:tenant.db.
+func urlPath(u *url.URL) string {
+	if u.Opaque != "" {
+		return u.Opaque
+	}
+	return u.Path
+}
+
+// connKey is the context key for the connection set by WithConn.
+type connKey struct{}
+
+// WithConn returns a copy of ctx for tenant conn: statements run through a
+// database.ContextDB with it go to the tenant's database.
+func WithConn(ctx context.Context, conn *Conn) context.Context {
+	ctx = context.WithValue(ctx, connKey{}, conn)
+	return database.ContextWithDB(ctx, conn.DBTX)
+}
+
+// FromContext returns the tenant connection set by WithConn, or nil for
+// the default database.
+func FromContext(ctx context.Context) *Conn {
+	conn, _ := ctx.Value(connKey{}).(*Conn)
+	return conn
+}
@@ -0,0 +1,273 @@
+package tenancy
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+func TestValidID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "acme", want: true},
+		{id: "acme-2", want: true},
+		{id: "", want: false},
+		{id: "-acme", want: false},
+		{id: "acme-", want: false},
+		{id: "Acme", want: false},
+		{id: "acme.eu", want: false},
+		{id: "acme_eu", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := ValidID(tt.id); got != tt.want {
+				t.Errorf("ValidID(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		host   string
+		header string
+		domain string
+		want   string
+	}{
+		{name: "subdomain", host: "acme.notes.test", domain: "notes.test", want: "acme"},
+		{name: "subdomain with port", host: "Acme.notes.test:8080", domain: "notes.test", want: "acme"},
+		{name: "subdomain over header", host: "acme.notes.test", header: "other", domain: "notes.test", want: "acme"},
+		{name: "apex", host: "notes.test", domain: "notes.test", want: ""},
+		{name: "header", host: "localhost:8080", header: "acme", domain: "notes.test", want: "acme"},
+		{name: "header without domain", host: "localhost", header: "ACME", want: "acme"},
+		{name: "neither", host: "localhost", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Host = tt.host
+			if tt.header != "" {
+				r.Header.Set(Header, tt.header)
+			}
+			if got := FromRequest(r, tt.domain); got != tt.want {
+				t.Errorf("FromRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// nopConnector opens databases that never connect, so tests can tell
+// whether they were closed.
+type nopConnector struct{}
+
+func (nopConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("not connectable")
+}
+
+func (nopConnector) Driver() driver.Driver { return nil }
+
+func closed(db *sql.DB) bool {
+	return db.Ping() != nil && db.Ping().Error() == "sql: database is closed"
+}
+
+func newTestRouter(locations map[string]Location, maxOpen int) (*Router, *clock.Fake, *int) {
+	opened := 0
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	r := NewRouter(Config{
+		Lookup: func(_ context.Context, id string) (Location, error) {
+			loc, ok := locations[id]
+			if !ok {
+				return Location{}, ErrUnknownTenant
+			}
+			return loc, nil
+		},
+		Open: func(string) (*sql.DB, database.DBTX, error) {
+			opened++
+			db := sql.OpenDB(nopConnector{})
+			return db, db, nil
+		},
+		MaxOpen: maxOpen,
+	}, fake)
+	r.closeDelay = 0
+	return r, fake, &opened
+}
+
+func TestRouterGet(t *testing.T) {
+	locations := map[string]Location{"acme": {DatabaseURL: "libsql://acme.test"}}
+	r, fake, opened := newTestRouter(locations, 10)
+	ctx := context.Background()
+
+	conn, err := r.Get(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if again, _ := r.Get(ctx, "acme"); again != conn || *opened != 1 {
+		t.Fatalf("second Get() opened the database again")
+	}
+	for _, id := range []string{"other", "Acme", ""} {
+		if _, err := r.Get(ctx, id); !errors.Is(err, ErrUnknownTenant) {
+			t.Errorf("Get(%q) error = %v, want ErrUnknownTenant", id, err)
+		}
+	}
+
+	// Once rechecked, an unchanged mapping keeps the connection.
+	fake.Advance(2 * recheckInterval)
+	if again, _ := r.Get(ctx, "acme"); again != conn || *opened != 1 {
+		t.Fatalf("Get() after recheck reopened an unchanged database")
+	}
+
+	// A moved tenant gets a new connection and the old one is closed.
+	locations["acme"] = Location{DatabaseURL: "libsql://acme-restored.test"}
+	fake.Advance(2 * recheckInterval)
+	moved, err := r.Get(ctx, "acme")
+	if err != nil || moved == conn {
+		t.Fatalf("Get() after move = %v, %v, want a new connection", moved, err)
+	}
+	if !closed(conn.DB) {
+		t.Error("old connection left open")
+	}
+
+	// So does one that picked a region, which the connection carries.
+	locations["acme"] = Location{DatabaseURL: "libsql://acme-restored.test", Region: "eu"}
+	fake.Advance(2 * recheckInterval)
+	regional, err := r.Get(ctx, "acme")
+	if err != nil || regional == moved || regional.Region != "eu" {
+		t.Fatalf("Get() after picking a region = %+v, %v, want a new connection in eu", regional, err)
+	}
+
+	// A removed tenant is unknown and its connection closed.
+	delete(locations, "acme")
+	fake.Advance(2 * recheckInterval)
+	if _, err := r.Get(ctx, "acme"); !errors.Is(err, ErrUnknownTenant) {
+		t.Fatalf("Get() after removal error = %v, want ErrUnknownTenant", err)
+	}
+	if !closed(regional.DB) {
+		t.Error("removed tenant's connection left open")
+	}
+}
+
+func TestRouterEvictsLeastRecentlyUsed(t *testing.T) {
+	locations := map[string]Location{
+		"a": {DatabaseURL: "libsql://a.test"},
+		"b": {DatabaseURL: "libsql://b.test"},
+		"c": {DatabaseURL: "libsql://c.test"},
+	}
+	r, fake, _ := newTestRouter(locations, 2)
+	ctx := context.Background()
+
+	a, _ := r.Get(ctx, "a")
+	fake.Advance(time.Second)
+	b, _ := r.Get(ctx, "b")
+	fake.Advance(time.Second)
+	r.Get(ctx, "a")
+	fake.Advance(time.Second)
+	r.Get(ctx, "c")
+
+	if closed(a.DB) || !closed(b.DB) {
+		t.Errorf("closed a = %v, b = %v; want only b, the least recently used, closed", closed(a.DB), closed(b.DB))
+	}
+}
+
+func TestWithConn(t *testing.T) {
+	if conn := FromContext(context.Background()); conn != nil {
+		t.Errorf("FromContext() = %v without a tenant, want nil", conn)
+	}
+	conn := &Conn{ID: "acme"}
+	if got := FromContext(WithConn(context.Background(), conn)); got != conn {
+		t.Errorf("FromContext() = %v, want %v", got, conn)
+	}
+}
+
+func TestParseRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Regions
+		wantErr bool
+	}{
+		{name: "empty", s: "", want: Regions{}},
+		{
+			name: "regions",
+			s:    "eu=libsql://{tenant}-acme.aws-eu-west-1.turso.io?authToken=a, us = libsql://{tenant}-acme.aws-us-east-1.turso.io,",
+			want: Regions{
+				"eu": "libsql://{tenant}-acme.aws-eu-west-1.turso.io?authToken=a",
+				"us": "libsql://{tenant}-acme.aws-us-east-1.turso.io",
+			},
+		},
+		{name: "no template", s: "eu", wantErr: true},
+		{name: "no tenant in template", s: "eu=libsql://shared.turso.io", wantErr: true},
+		{name: "tenant twice", s: "eu=libsql://{tenant}.turso.io/{tenant}", wantErr: true},
+		{name: "tenant in query", s: "eu=libsql://shared.turso.io?db={tenant}", wantErr: true},
+		{name: "bad template", s: "eu=libsql://%zz{tenant}.turso.io", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRegions(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRegions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseRegions() = %v, want %v", got, tt.want)
+			}
+			for region, template := range tt.want {
+				if got[region] != template {
+					t.Errorf("ParseRegions()[%q] = %q, want %q", region, got[region], template)
+				}
+			}
+		})
+	}
+}
+
+func TestRegions(t *testing.T) {
+	regions := Regions{
+		"eu":     "libsql://{tenant}-acme.aws-eu-west-1.turso.io?authToken=a",
+		"local":  "file:/data/eu/{tenant}.db",
+		"flat":   "libsql://{tenant}.turso.io",
+		"broken": "libsql://%zz{tenant}.turso.io",
+	}
+	if got, ok := regions.DatabaseURL("eu", "globex"); got != "libsql://globex-acme.aws-eu-west-1.turso.io?authToken=a" || !ok {
+		t.Errorf("DatabaseURL(eu) = %q, %v", got, ok)
+	}
+	if _, ok := regions.DatabaseURL("us", "globex"); ok {
+		t.Error("DatabaseURL() of an unknown region succeeded")
+	}
+
+	tests := []struct {
+		region string
+		url    string
+		want   bool
+	}{
+		{region: "eu", url: "libsql://globex-restored-acme.aws-eu-west-1.turso.io?authToken=b", want: true},
+		{region: "eu", url: "libsql://globex-acme.aws-us-east-1.turso.io", want: false},
+		{region: "eu", url: "libsql://aws-eu-west-1.turso.io.evil.test", want: false},
+		{region: "eu", url: "https://globex-acme.aws-eu-west-1.turso.io", want: false},
+		{region: "eu", url: "libsql://-acme.aws-eu-west-1.turso.io", want: false},
+		{region: "eu", url: "libsql://acme.aws-eu-west-1.turso.io", want: false},
+		{region: "eu", url: "::", want: false},
+		{region: "us", url: "libsql://globex-acme.aws-us-east-1.turso.io", want: false},
+		{region: "local", url: "file:/data/eu/globex-restored.db", want: true},
+		{region: "local", url: "file:/data/us/globex.db", want: false},
+		{region: "local", url: "file:/data/eu/us/globex.db", want: false},
+		{region: "flat", url: "libsql://globex.turso.io", want: true},
+		{region: "flat", url: "libsql://globex.aws-eu-west-1.turso.io", want: false},
+		{region: "broken", url: "libsql://globex.turso.io", want: false},
+	}
+	for _, tt := range tests {
+		if got := regions.InRegion(tt.region, tt.url); got != tt.want {
+			t.Errorf("InRegion(%q, %q) = %v, want %v", tt.region, tt.url, got, tt.want)
+		}
+	}
+}
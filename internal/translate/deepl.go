@@ -0,0 +1,51 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/httpjson"
+)
+
+// DeepL uses the DeepL API. Free and pro accounts differ only in base URL.
+type DeepL struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func NewDeepL(baseURL, apiKey string) *DeepL {
+	return &DeepL{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (d *DeepL) Name() string {
+	return "deepl"
+}
+
+func (d *DeepL) Translate(ctx context.Context, text, target string) (string, error) {
+	header := http.Header{}
+	header.Set("Authorization", "DeepL-Auth-Key "+d.apiKey)
+	var resp struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	err := httpjson.Post(ctx, d.client, d.baseURL+"/v2/translate", header, map[string]interface{}{
+		"text":        []string{text},
+		"target_lang": strings.ToUpper(target),
+	}, &resp)
+	if err != nil {
+		return "", fmt.Errorf("translation request failed: %w", err)
+	}
+	if len(resp.Translations) == 0 {
+		return "", errors.New("translation response contained no text")
+	}
+	return resp.Translations[0].Text, nil
+}
@@ -0,0 +1,52 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/httpjson"
+)
+
+// LibreTranslate uses a (typically self-hosted) LibreTranslate server.
+type LibreTranslate struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewLibreTranslate creates a client for the server at baseURL. apiKey may
+// be empty for servers that don't require one.
+func NewLibreTranslate(baseURL, apiKey string) *LibreTranslate {
+	return &LibreTranslate{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (l *LibreTranslate) Name() string {
+	return "libretranslate"
+}
+
+func (l *LibreTranslate) Translate(ctx context.Context, text, target string) (string, error) {
+	body := map[string]string{
+		"q":      text,
+		"source": "auto",
+		// LibreTranslate only knows base languages, e.g. "pt" rather than "pt-BR".
+		"target": strings.ToLower(strings.SplitN(target, "-", 2)[0]),
+		"format": "text",
+	}
+	if l.apiKey != "" {
+		body["api_key"] = l.apiKey
+	}
+	var resp struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	err := httpjson.Post(ctx, l.client, l.baseURL+"/translate", nil, body, &resp)
+	if err != nil {
+		return "", fmt.Errorf("translation request failed: %w", err)
+	}
+	return resp.TranslatedText, nil
+}
@@ -0,0 +1,41 @@
+// Package translate translates note text through external services.
+package translate
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Translator translates text into a target language given as an ISO 639
+// code such as "de" or "pt-BR".
+type Translator interface {
+	Translate(ctx context.Context, text, target string) (string, error)
+	Name() string
+}
+
+// requestTimeout bounds a single translation request.
+const requestTimeout = time.Minute
+
+// FromEnv builds the Translator selected by TRANSLATE_PROVIDER ("deepl" or
+// "libretranslate"). Translation is off by default, in which case nil is
+// returned.
+func FromEnv(getenv func(string) string) (Translator, error) {
+	switch provider := getenv("TRANSLATE_PROVIDER"); provider {
+	case "":
+		return nil, nil
+	case "deepl":
+		if getenv("TRANSLATE_API_KEY") == "" {
+			return nil, fmt.Errorf("TRANSLATE_API_KEY is required for the deepl provider")
+		}
+		return NewDeepL(cmp.Or(getenv("TRANSLATE_URL"), "https://api-free.deepl.com"), getenv("TRANSLATE_API_KEY")), nil
+	case "libretranslate":
+		if getenv("TRANSLATE_URL") == "" {
+			return nil, fmt.Errorf("TRANSLATE_URL is required for the libretranslate provider")
+		}
+		return NewLibreTranslate(getenv("TRANSLATE_URL"), getenv("TRANSLATE_API_KEY")), nil
+	default:
+		return nil, fmt.Errorf("unknown TRANSLATE_PROVIDER %q", provider)
+	}
+}
@@ -0,0 +1,96 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		wantNil  bool
+		wantName string
+		wantErr  bool
+	}{
+		{name: "disabled by default", env: map[string]string{}, wantNil: true},
+		{name: "unknown provider", env: map[string]string{"TRANSLATE_PROVIDER": "babelfish"}, wantErr: true},
+		{name: "deepl without key", env: map[string]string{"TRANSLATE_PROVIDER": "deepl"}, wantErr: true},
+		{name: "deepl", env: map[string]string{"TRANSLATE_PROVIDER": "deepl", "TRANSLATE_API_KEY": "k"}, wantName: "deepl"},
+		{name: "libretranslate without url", env: map[string]string{"TRANSLATE_PROVIDER": "libretranslate"}, wantErr: true},
+		{name: "libretranslate", env: map[string]string{"TRANSLATE_PROVIDER": "libretranslate", "TRANSLATE_URL": "http://lt"}, wantName: "libretranslate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := FromEnv(func(k string) string { return tt.env[k] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (tr == nil) != tt.wantNil {
+				t.Fatalf("FromEnv() = %v, wantNil %v", tr, tt.wantNil)
+			}
+			if tr != nil && tr.Name() != tt.wantName {
+				t.Errorf("Name() = %q, want %q", tr.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/translate":
+			var body struct {
+				Text       []string `json:"text"`
+				TargetLang string   `json:"target_lang"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if r.Header.Get("Authorization") != "DeepL-Auth-Key secret" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"translations": []map[string]string{{"text": body.TargetLang + ":" + body.Text[0]}},
+			})
+		case "/translate":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			json.NewEncoder(w).Encode(map[string]string{"translatedText": body["target"] + ":" + body["q"]})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		translator Translator
+		target     string
+		want       string
+		wantErr    bool
+	}{
+		{name: "deepl uppercases the language", translator: NewDeepL(srv.URL, "secret"), target: "pt-br", want: "PT-BR:hello"},
+		{name: "deepl wrong key", translator: NewDeepL(srv.URL, "wrong"), target: "de", wantErr: true},
+		{name: "libretranslate uses the base language", translator: NewLibreTranslate(srv.URL+"/", ""), target: "pt-BR", want: "pt:hello"},
+		{name: "libretranslate not found", translator: NewLibreTranslate(srv.URL+"/missing", ""), target: "de", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.translator.Translate(context.Background(), "hello", tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Translate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
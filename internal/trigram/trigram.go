@@ -0,0 +1,51 @@
+// Package trigram splits text into trigrams for fuzzy matching, the way
+// PostgreSQL's pg_trgm does: each word is lowercased and padded with two
+// spaces in front and one behind, so short words and word starts still
+// produce trigrams and weigh more than word middles.
+package trigram
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Trigrams returns the distinct trigrams of s's words, in order of first
+// appearance. Words are runs of letters and digits.
+func Trigrams(s string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			t := string(runes[i : i+3])
+			if !seen[t] {
+				seen[t] = true
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// Similarity is the Jaccard similarity of two trigram sets: how many
+// trigrams they share out of all distinct trigrams in either, from 0 for
+// nothing in common to 1 for identical sets.
+func Similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inA := make(map[string]bool, len(a))
+	for _, t := range a {
+		inA[t] = true
+	}
+	shared := 0
+	for _, t := range b {
+		if inA[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
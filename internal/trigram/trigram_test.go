@@ -0,0 +1,58 @@
+package trigram
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTrigrams(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{name: "empty", s: "", want: nil},
+		{name: "punctuation only", s: "!?", want: nil},
+		{name: "single letter", s: "a", want: []string{"  a", " a "}},
+		{name: "word", s: "Cat", want: []string{"  c", " ca", "cat", "at "}},
+		{name: "duplicates once", s: "cat cat", want: []string{"  c", " ca", "cat", "at "}},
+		{name: "splits on punctuation", s: "go-to", want: []string{"  g", " go", "go ", "  t", " to", "to "}},
+		{name: "keeps letters outside ASCII", s: "Größe", want: []string{"  g", " gr", "grö", "röß", "öße", "ße "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Trigrams(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Trigrams(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "identical", a: "groceries", b: "Groceries", want: 1},
+		{name: "nothing shared", a: "cat", b: "dog", want: 0},
+		{name: "empty", a: "", b: "cat", want: 0},
+		// "  c", " ca", "cat", "at " vs "  c", " ca", "car", "ar ": 2 shared of 6.
+		{name: "typo", a: "cat", b: "car", want: 2.0 / 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(Trigrams(tt.a), Trigrams(tt.b))
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	if typo, unrelated := Similarity(Trigrams("groceries"), Trigrams("grocceries")), Similarity(Trigrams("groceries"), Trigrams("gardening")); typo <= unrelated {
+		t.Errorf("typo scored %v, not above unrelated word's %v", typo, unrelated)
+	}
+}
@@ -1,17 +1,27 @@
 // This file provides helper functions for sending JSON responses in a web app. It handles success responses (respondWithJSON) and error responses (respondWithError), with logging for server-side errors. The overall flow is:
 // 1. For errors: Log if needed, create an error JSON, and send it.
-// 2. For success: Marshal data to JSON, set headers, write response, handle any errors.
+// 2. For success: Encode data to JSON in a pooled buffer, set headers, write response, handle any errors.
 // This is used in the main app to return API data or errors securely.
 
 package main
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/jsoncase"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/respbuf"
 )
 
 func respondWithError(w http.ResponseWriter, code int, msg string, logErr error) {
+	respondWithErrorReason(w, code, "", msg, logErr)
+}
+
+// respondWithErrorReason is respondWithError with a machine-readable reason,
+// sent as "code" so clients can tell errors apart without parsing messages.
+func respondWithErrorReason(w http.ResponseWriter, code int, reason, msg string, logErr error) {
 	if logErr != nil {
 		log.Println(logErr) // Log any incoming error.
 	}
@@ -19,23 +29,104 @@ func respondWithError(w http.ResponseWriter, code int, msg string, logErr error)
 		log.Printf("Responding with 5XX error: %s", msg) // Log server-side errors (5XX).
 	}
 	type errorResponse struct {
-		Error string `json:"error"` // Structure for JSON error response.
+		Error string `json:"error"`          // Structure for JSON error response.
+		Code  string `json:"code,omitempty"` // Set for errors clients handle specially.
 	}
 	respondWithJSON(w, code, errorResponse{
 		Error: msg,
+		Code:  reason,
 	})
 }
 
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json") // Set JSON header.
-	dat, err := json.Marshal(payload)                  // Convert payload to JSON.
-	if err != nil {
+	if _, ok := payload.(dataKeyed); !ok && camelCaseResponse(w) {
+		payload = camelCaseJSON{payload} // Rename keys for clients that asked for camelCase.
+	}
+	// Encode into a pooled buffer and write the response.
+	err := respbuf.WriteJSON(w, code, payload)
+	if errors.Is(err, respbuf.ErrEncoding) {
 		log.Printf("Error marshalling JSON: %s", err) // Log marshalling error.
 		w.WriteHeader(500)
 		return
 	}
-	w.WriteHeader(code) // Set HTTP status code.
-	if _, err := w.Write(dat); err != nil {
+	if err != nil {
 		log.Printf("Error writing response: %v", err) // Fix G104: Handle write error with detailed log (%v for full err).
 	}
 }
+
+// maxResponseBuffer is how much of a list response is held in memory before
+// it's streamed with chunked transfer encoding instead.
+const maxResponseBuffer = 64 << 10
+
+// listFlushInterval is how many items of a streamed list are written between flushes.
+const listFlushInterval = 100
+
+// respondWithJSONList encodes items as a JSON array one at a time, so the
+// encoded response never has to be held in memory whole. Short lists are
+// still sent in one go with a Content-Length.
+func respondWithJSONList[T any](w http.ResponseWriter, code int, items []T) {
+	w.Header().Set("Content-Type", "application/json")
+	bw := respbuf.New(w, code, maxResponseBuffer)
+	enc := json.NewEncoder(bw)
+	err := writeJSONList(bw, enc, items, camelCaseResponse(w))
+	if err == nil {
+		err = bw.Close()
+	}
+	if err == nil {
+		return
+	}
+	if !bw.Streaming() {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't encode response", err)
+		return
+	}
+	// The status line is out; cut the response short so the client doesn't
+	// take a truncated list for a complete one.
+	log.Printf("Error streaming response: %v", err)
+	panic(http.ErrAbortHandler)
+}
+
+func writeJSONList[T any](bw *respbuf.Writer, enc *json.Encoder, items []T, camelCase bool) error {
+	if _, err := bw.Write([]byte("[")); err != nil {
+		return err
+	}
+	for i, item := range items {
+		if i > 0 {
+			if _, err := bw.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		var v any = item
+		if camelCase {
+			v = camelCaseJSON{item}
+		}
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		if (i+1)%listFlushInterval == 0 {
+			bw.Flush()
+		}
+	}
+	_, err := bw.Write([]byte("]"))
+	return err
+}
+
+// dataKeyed is implemented by responses whose object keys are data, such as
+// notification types and channels, rather than field names. Their keys are
+// never renamed.
+type dataKeyed interface {
+	dataKeyed()
+}
+
+// camelCaseJSON encodes a value with its snake_case keys renamed to camelCase.
+type camelCaseJSON struct {
+	v any
+}
+
+func (c camelCaseJSON) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(c.v)
+	if err != nil {
+		return nil, err
+	}
+	return jsoncase.Camel(data), nil
+}
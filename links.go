@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/linkpreview"
+)
+
+const (
+	// maxLinksPerNote caps how many URLs of a single note are tracked.
+	maxLinksPerNote = 20
+	// linkPreviewQueueSize bounds the URLs waiting for a preview fetch.
+	linkPreviewQueueSize = 256
+	// linkCheckBatchSize is how many links are loaded per query while checking.
+	linkCheckBatchSize = 100
+)
+
+// recordLinks stores the URLs referenced by note and queues previews for
+// the ones that haven't been fetched yet. Failures are only logged: the
+// note is saved either way, it just won't show link cards.
+func (cfg *apiConfig) recordLinks(ctx context.Context, note database.Note) {
+	for i, url := range linkpreview.ExtractURLs(note.Note) {
+		if i == maxLinksPerNote {
+			break
+		}
+		err := cfg.DB.CreateNoteLink(ctx, database.CreateNoteLinkParams{
+			NoteID:   note.ID,
+			Url:      url,
+			Position: int64(i),
+		})
+		if err != nil {
+			log.Printf("Couldn't record link for note %s: %v", note.ID, err)
+			continue
+		}
+		_, err = cfg.DB.GetLinkPreview(ctx, url)
+		if errors.Is(err, database.ErrNotFound) && !isDryRun(ctx) {
+			cfg.queueLinkPreview(linkToPreview{tenant: tenantID(ctx), url: url})
+		} else if err != nil {
+			log.Printf("Couldn't get link preview: %v", err)
+		}
+	}
+}
+
+// linkToPreview is a URL waiting for its preview to be fetched into
+// the database of tenant, or the default one if it's empty.
+type linkToPreview struct {
+	tenant string
+	url    string
+}
+
+// queueLinkPreview hands req to runLinkPreviews without blocking. When the
+// queue is full the URL is skipped; it's queued again the next time a note
+// references it.
+func (cfg *apiConfig) queueLinkPreview(req linkToPreview) {
+	select {
+	case cfg.linkPreviewQueue <- req:
+	default:
+		log.Printf("Link preview queue full, skipping %s", req.url)
+	}
+}
+
+// runLinkPreviews fetches queued link previews one at a time until ctx is done.
+func (cfg *apiConfig) runLinkPreviews(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-cfg.linkPreviewQueue:
+			tenantCtx, err := cfg.tenantContext(ctx, req.tenant)
+			if err != nil {
+				log.Printf("Couldn't connect to database of tenant %s: %v", req.tenant, err)
+				continue
+			}
+			cfg.fetchLinkPreview(tenantCtx, req.url)
+		}
+	}
+}
+
+// fetchLinkPreview stores the preview metadata of url. Pages that can't be
+// fetched are stored without metadata, so they aren't fetched over and over.
+func (cfg *apiConfig) fetchLinkPreview(ctx context.Context, url string) {
+	params := database.UpsertLinkPreviewParams{
+		Url:       url,
+		FetchedAt: cfg.Clock.Now().UTC().Format(time.RFC3339),
+	}
+	resp, err := cfg.pageFetcher.Get(ctx, url, "text/html,application/xhtml+xml")
+	if err != nil {
+		log.Printf("Couldn't fetch link preview: %v", err)
+	} else if preview, err := linkpreview.Parse(resp.Body, resp.URL); err != nil {
+		log.Printf("Couldn't parse link preview for %s: %v", url, err)
+	} else {
+		params.Title = nullIfEmpty(preview.Title)
+		params.Description = nullIfEmpty(preview.Description)
+		params.ImageUrl = nullIfEmpty(preview.ImageURL)
+		params.SiteName = nullIfEmpty(preview.SiteName)
+	}
+
+	if err := cfg.DB.UpsertLinkPreview(ctx, params); err != nil {
+		log.Printf("Couldn't store link preview for %s: %v", url, err)
+	}
+}
+
+// runLinkChecks re-validates every linked URL not checked within interval,
+// in every tenant's database, once at startup and then every interval,
+// until ctx is done.
+func (cfg *apiConfig) runLinkChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cfg.forEachTenant(ctx, func(ctx context.Context) { cfg.checkLinks(ctx, interval) })
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cfg *apiConfig) checkLinks(ctx context.Context, interval time.Duration) {
+	staleBefore := cfg.Clock.Now().UTC().Add(-interval).Format(time.RFC3339)
+	checked, broken := 0, 0
+	for ctx.Err() == nil {
+		urls, err := cfg.DB.GetLinksToCheck(ctx, database.GetLinksToCheckParams{
+			CheckedAt: staleBefore,
+			Limit:     linkCheckBatchSize,
+		})
+		if err != nil {
+			log.Printf("Couldn't get links to check: %v", err)
+			return
+		}
+		if len(urls) == 0 {
+			break
+		}
+		for _, url := range urls {
+			alive, err := cfg.checkLink(ctx, url)
+			if err != nil {
+				// Unrecorded links would be returned again, so stop instead of looping.
+				log.Printf("Couldn't store link status for %s: %v", url, err)
+				return
+			}
+			if !alive {
+				broken++
+			}
+			checked++
+		}
+	}
+	if checked > 0 {
+		log.Printf("Checked %d links, %d broken", checked, broken)
+	}
+}
+
+// checkLink records whether url still resolves to a successful response and
+// reports the result. Anything below 400 after following redirects is alive.
+func (cfg *apiConfig) checkLink(ctx context.Context, url string) (bool, error) {
+	params := database.UpsertLinkStatusParams{Url: url}
+	status, err := cfg.pageFetcher.Check(ctx, url)
+	if err != nil {
+		params.Error = nullIfEmpty(err.Error())
+	} else {
+		params.StatusCode = sql.NullInt64{Int64: int64(status), Valid: true}
+		params.Alive = status < 400
+	}
+	params.CheckedAt = cfg.Clock.Now().UTC().Format(time.RFC3339)
+
+	return params.Alive, cfg.DB.UpsertLinkStatus(ctx, params)
+}
+
+func nullIfEmpty(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// linkPreviewsByNote groups the user's fetched link previews by note ID.
+// Links whose page had no usable metadata are left out.
+func (cfg *apiConfig) linkPreviewsByNote(ctx context.Context, userID string) (map[string][]LinkPreview, error) {
+	rows, err := cfg.DB.GetLinkPreviewsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	previews := make(map[string][]LinkPreview)
+	for _, row := range rows {
+		if !row.LinkPreview.Title.Valid {
+			continue
+		}
+		previews[row.NoteID] = append(previews[row.NoteID], databaseLinkPreviewToLinkPreview(row.LinkPreview))
+	}
+	return previews, nil
+}
@@ -8,26 +8,134 @@
 package main
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
 	"embed"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/alerting"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/chaos"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
 	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/embeddings"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/languagetool"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/llm"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/mail"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/metering"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/push"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/recording"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/safefetch"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/slo"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/stripe"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/tenancy"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/translate"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 	_ "github.com/tursodatabase/libsql-client-go/libsql"
+	"golang.org/x/sync/singleflight"
 )
 
 // Configuration structure to hold app-wide settings, like the database connection.
 type apiConfig struct {
-	DB *database.Queries
+	DB   *database.Store
+	Conn *sql.DB // Underlying connection, used to run queries inside transactions.
+
+	KeyRotationGrace time.Duration        // How long a rotated API key keeps working.
+	UsernameCooldown time.Duration        // How long after a username change it can't be changed again.
+	FuzzyTitleSearch bool                 // Keep title trigrams for fuzzy title suggestions; set by FUZZY_TITLE_SEARCH.
+	EnforcePlans     bool                 // Hold users to the limits of their plans; set by ENFORCE_PLANS.
+	AdminAPIKey      string               // Key for the /admin endpoints; they aren't served unless ADMIN_API_KEY is set.
+	SiteURL          string               // Public base URL of the site, for the sitemap; it isn't served unless SITE_URL is set.
+	Events           events.Publisher     // Note lifecycle events; a no-op unless EVENTS_BACKEND is set.
+	Audit            audit.Logger         // Security events for a SIEM; a no-op unless SECURITY_LOG is set.
+	Embedder         embeddings.Embedder  // Embeds notes for semantic search; nil unless EMBEDDINGS_PROVIDER is set.
+	LLM              llm.Provider         // Writes note summaries; nil unless LLM_PROVIDER is set.
+	LanguageTool     *languagetool.Client // Spelling and grammar checks; nil unless LANGUAGETOOL_URL is set.
+	Translator       translate.Translator // Translates notes; nil unless TRANSLATE_PROVIDER is set.
+	Mailer           mail.Mailer          // Sends email; nil unless MAIL_PROVIDER is set.
+	Push             push.Senders         // Mobile push delivery by provider; empty unless FCM or APNs is configured.
+	WebPush          *push.WebPush        // Browser push delivery; nil unless WEB_PUSH_SUBJECT is set.
+	SLO              *slo.Tracker         // Per-route latency and error objectives; nil unless SLO_FILE is set.
+	Alerts           *alerting.Monitor    // Alerts on abnormal error rates; nil unless ALERT_WEBHOOK_URL is set.
+	Tenants          *tenancy.Router      // Connections to tenants' own databases; nil unless MULTI_TENANT is set.
+	TenantDomain     string               // Domain whose subdomains name tenants, from TENANT_DOMAIN.
+	TenantRegions    tenancy.Regions      // Regions tenants can keep their data in, from TENANT_REGIONS.
+	Usage            *metering.Counter    // API calls per tenant for billing; nil unless USAGE_METERING is set.
+	Stripe           *stripe.Client       // Subscriptions to paid plans; nil unless STRIPE_SECRET_KEY is set.
+	BillingReturnURL string               // Where the billing portal links back to, from STRIPE_PORTAL_RETURN_URL or SITE_URL.
+	Clock            clock.Clock          // Time source for timestamps, expiry and rate limits; clock.System outside tests.
+
+	pageFetcher      *safefetch.Fetcher // Fetches user-supplied web pages, refusing internal addresses.
+	linkPreviewQueue chan linkToPreview // URLs waiting for runLinkPreviews.
+	summarizeLimiter *userRateLimiter   // Per-user budget for LLM summary calls.
+	checkLimiter     *userRateLimiter   // Per-user budget for uncached LanguageTool checks.
+	reactionEmoji    map[string]bool    // Emoji users may react to notes and comments with.
+	quickCache       *quickCache        // Recent command palette results.
+	sitemapCache     *sitemapCache      // Rendered sitemap pages.
+	emailTemplates   *mail.Templates    // Bodies of outgoing email, in every language they're translated into.
+	defaultDB        *database.Store    // Queries of the default database whichever tenant a request is for, e.g. of the tenants table.
+	authCache        *authCache         // Recent API key resolutions; nil if AUTH_CACHE_TTL is 0.
+	userLookups      singleflight.Group // Collapses concurrent lookups of the same API key.
+	noteLists        singleflight.Group // Collapses concurrent note list reads by tenantUserKey.
+	noteViews        *noteViewCounts    // Published page views not yet written to the database.
+	rebuildRateLimit int                // Notes per second an index rebuild processes.
+
+	draining   atomic.Bool // Set on shutdown so readiness fails while load balancers drain.
+	rebuilding atomic.Bool // Set while an index rebuild runs on this instance.
 }
 
+// Defaults for durations that can be overridden through the environment.
+const (
+	defaultKeyRotationGrace  = 24 * time.Hour
+	defaultUsernameCooldown  = 30 * 24 * time.Hour
+	defaultShutdownDrain     = 0 * time.Second
+	defaultShutdownTimeout   = 30 * time.Second
+	defaultLinkCheckInterval = 24 * time.Hour
+	defaultNotePurgeInterval = time.Minute
+	defaultNoteExpiryWarning = 24 * time.Hour
+	defaultAuthCacheTTL      = 30 * time.Second
+	defaultNoteViewFlush     = 30 * time.Second
+	defaultUsageFlush        = time.Minute
+	defaultUsageMeasurement  = time.Hour
+	defaultTrialInterval     = 10 * time.Minute
+	defaultTrialWarning      = 3 * 24 * time.Hour
+
+	defaultMaxQueueWait          = 500 * time.Millisecond
+	defaultSlowQueryThreshold    = 500 * time.Millisecond
+	defaultSlowQueryPlanInterval = 10 * time.Minute
+)
+
+// Per-user hourly budgets for endpoints that call out to paid or rate-limited services.
+const (
+	defaultSummarizeRateLimit = 20
+	defaultCheckRateLimit     = 100
+)
+
+// How many requests of each class are handled at once before further ones queue.
+const (
+	defaultMaxInFlight       = 200
+	defaultMaxInFlightWrites = 50
+	defaultMaxInFlightBulk   = 10
+)
+
+// checkCacheSize is how many LanguageTool responses are kept in memory.
+const checkCacheSize = 1000
+
+// maxPageSize is the largest web page fetched on behalf of a user, in bytes.
+const maxPageSize = 5 << 20
+
 // Embed static files (e.g., HTML) into the binary so the app can serve them without external files.
 //
 //go:embed static/*
@@ -46,7 +154,111 @@ func main() {
 		log.Fatal("PORT environment variable is not set")
 	}
 
-	apiCfg := apiConfig{}
+	apiCfg := &apiConfig{
+		Clock:            clock.System,
+		KeyRotationGrace: durationFromEnv("API_KEY_ROTATION_GRACE", defaultKeyRotationGrace),
+		UsernameCooldown: durationFromEnv("USERNAME_CHANGE_COOLDOWN", defaultUsernameCooldown),
+		FuzzyTitleSearch: os.Getenv("FUZZY_TITLE_SEARCH") == "true",
+		EnforcePlans:     os.Getenv("ENFORCE_PLANS") == "true",
+		AdminAPIKey:      os.Getenv("ADMIN_API_KEY"),
+		pageFetcher: safefetch.New(safefetch.Options{
+			MaxSize:      maxPageSize,
+			ContentTypes: []string{"text/html", "application/xhtml+xml"},
+		}),
+		linkPreviewQueue: make(chan linkToPreview, linkPreviewQueueSize),
+		reactionEmoji:    parseReactionEmoji(defaultReactionEmoji),
+		quickCache:       newQuickCache(quickCacheTTL, quickCacheSize, clock.System),
+		sitemapCache:     newSitemapCache(clock.System),
+		authCache:        newAuthCache(durationFromEnv("AUTH_CACHE_TTL", defaultAuthCacheTTL), authCacheSize, clock.System),
+		noteViews:        newNoteViewCounts(clock.System),
+		rebuildRateLimit: intFromEnv("REBUILD_RATE_LIMIT", defaultRebuildRateLimit),
+	}
+	apiCfg.SiteURL, err = siteURLFromEnv(os.Getenv("SITE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if list := os.Getenv("REACTION_EMOJI"); list != "" {
+		apiCfg.reactionEmoji = parseReactionEmoji(list)
+	}
+
+	// Publish note lifecycle events to NATS or Kafka if configured; off by default.
+	apiCfg.Events, err = events.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up event publishing: %v", err)
+	}
+
+	// Write security events to their own file or syslog if configured; off by default.
+	apiCfg.Audit, err = audit.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up security logging: %v", err)
+	}
+
+	// Embed notes for semantic search through an OpenAI-compatible API or a local model if configured; off by default.
+	apiCfg.Embedder, err = embeddings.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up embeddings: %v", err)
+	}
+
+	// Summarize notes with an LLM if configured; off by default.
+	apiCfg.LLM, err = llm.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up LLM provider: %v", err)
+	}
+	apiCfg.summarizeLimiter = newUserRateLimiter(intFromEnv("SUMMARIZE_RATE_LIMIT", defaultSummarizeRateLimit), time.Hour, apiCfg.Clock)
+
+	// Proxy spelling and grammar checks to a LanguageTool-compatible service if configured; off by default.
+	if ltURL := os.Getenv("LANGUAGETOOL_URL"); ltURL != "" {
+		apiCfg.LanguageTool = languagetool.New(ltURL, os.Getenv("LANGUAGETOOL_USERNAME"), os.Getenv("LANGUAGETOOL_API_KEY"), checkCacheSize)
+	}
+	apiCfg.checkLimiter = newUserRateLimiter(intFromEnv("CHECK_RATE_LIMIT", defaultCheckRateLimit), time.Hour, apiCfg.Clock)
+
+	// Translate notes through DeepL or LibreTranslate if configured; off by default.
+	apiCfg.Translator, err = translate.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up translation: %v", err)
+	}
+
+	// Send email through SMTP, SendGrid or SES, or log it, if configured; off by default.
+	apiCfg.Mailer, err = mail.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up email: %v", err)
+	}
+	apiCfg.emailTemplates, err = mail.LoadTemplates()
+	if err != nil {
+		log.Fatalf("Couldn't load email templates: %v", err)
+	}
+
+	// Let users subscribe to paid plans through Stripe if configured; off by default.
+	apiCfg.Stripe, err = stripe.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up Stripe: %v", err)
+	}
+	apiCfg.BillingReturnURL = cmp.Or(os.Getenv("STRIPE_PORTAL_RETURN_URL"), apiCfg.SiteURL)
+
+	// Send push notifications to mobile apps through FCM and APNs if configured; off by default.
+	apiCfg.Push, err = push.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up push notifications: %v", err)
+	}
+
+	// Track per-route latency and error objectives and alert when their error budgets burn too fast, if configured; off by default.
+	if path := os.Getenv("SLO_FILE"); path != "" {
+		sloConfig, err := slo.Load(path)
+		if err != nil {
+			log.Fatalf("Couldn't load SLOs: %v", err)
+		}
+		apiCfg.SLO = slo.NewTracker(sloConfig, sloAlertWebhook(os.Getenv("SLO_ALERT_WEBHOOK_URL")))
+	}
+
+	// Post to a webhook when server errors, failed logins or database errors spike, if configured; off by default.
+	apiCfg.Alerts, err = alerting.FromEnv(os.Getenv, apiCfg.Clock)
+	if err != nil {
+		log.Fatalf("Couldn't set up alerting: %v", err)
+	}
+
+	// How long to keep serving with failing readiness before shutting down, and how long in-flight requests may take afterwards.
+	shutdownDrain := durationFromEnv("SHUTDOWN_DRAIN", defaultShutdownDrain)
+	shutdownTimeout := durationFromEnv("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
 
 	// Attempt to connect to the database using the URL from environment. If missing, run without DB features and log.
 	dbURL := os.Getenv("DATABASE_URL")
@@ -54,25 +266,92 @@ func main() {
 		log.Println("DATABASE_URL environment variable is not set")
 		log.Println("Running without CRUD endpoints")
 	} else {
-		db, err := sql.Open("libsql", dbURL)
+		db, dbtx, err := apiCfg.openDatabase(dbURL)
 		if err != nil {
 			log.Fatal(err)
 		}
-		dbQueries := database.New(db)
+		// Dry runs put a transaction, and requests for a tenant the tenant's database, in the request
+		// context for every query to run in.
+		dbQueries := database.NewStore(database.NewContextDB(dbtx))
 		apiCfg.DB = dbQueries
+		apiCfg.Conn = db
+		apiCfg.defaultDB = database.NewStore(dbtx)
 		log.Println("Connected to database!")
+
+		// Route each tenant of a hosted deployment to a database of its own if configured; off by default.
+		if os.Getenv("MULTI_TENANT") == "true" {
+			apiCfg.TenantDomain = os.Getenv("TENANT_DOMAIN")
+			apiCfg.TenantRegions, err = tenancy.ParseRegions(os.Getenv("TENANT_REGIONS"))
+			if err != nil {
+				log.Fatalf("Couldn't configure tenant regions: %v", err)
+			}
+			apiCfg.Tenants = tenancy.NewRouter(tenancy.Config{
+				Lookup:  apiCfg.lookupTenant,
+				Open:    apiCfg.openDatabase,
+				MaxOpen: intFromEnv("TENANT_MAX_OPEN", tenancy.DefaultMaxOpen),
+			}, apiCfg.Clock)
+		}
+
+		// Meter API calls and storage per tenant for billing if configured; off by default.
+		if os.Getenv("USAGE_METERING") == "true" {
+			apiCfg.Usage = metering.NewCounter(apiCfg.Clock)
+		}
+
+		// Optionally create initial users from a declarative file, so provisioned deployments need no manual steps.
+		if path := os.Getenv("BOOTSTRAP_FILE"); path != "" {
+			if err := apiCfg.applyBootstrap(context.Background(), path); err != nil {
+				log.Fatalf("Couldn't apply bootstrap file: %v", err)
+			}
+		}
+
+		// Send Web Push notifications to the browser client if configured; off by default.
+		if subject := os.Getenv("WEB_PUSH_SUBJECT"); subject != "" {
+			apiCfg.WebPush, err = apiCfg.newWebPush(context.Background(), os.Getenv("WEB_PUSH_VAPID_PRIVATE_KEY"), subject)
+			if err != nil {
+				log.Fatalf("Couldn't set up Web Push: %v", err)
+			}
+		}
 	}
 
 	// Set up the main router for handling web requests, with CORS for cross-origin security.
 	router := chi.NewRouter()
+	router.Use(middlewareRequestID)
+	if apiCfg.SLO != nil {
+		router.Use(apiCfg.middlewareSLO)
+	}
+	if apiCfg.Alerts != nil {
+		router.Use(apiCfg.middlewareAlerts)
+	}
+	// Turn excess requests away with a 503 instead of letting every request slow down, limiting
+	// reads, writes and slow calls to other services separately so none can starve the others.
+	shedder := newLoadShedder([numRequestClasses]int{
+		classInteractive: intFromEnv("MAX_IN_FLIGHT_REQUESTS", defaultMaxInFlight),
+		classWrite:       intFromEnv("MAX_IN_FLIGHT_WRITES", defaultMaxInFlightWrites),
+		classBulk:        intFromEnv("MAX_IN_FLIGHT_BULK", defaultMaxInFlightBulk),
+	}, durationFromEnv("MAX_QUEUE_WAIT", defaultMaxQueueWait))
+	router.Use(shedder.middleware)
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", dryRunHeader},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
+	// Run requests for a tenant against its own database.
+	if apiCfg.Tenants != nil {
+		router.Use(apiCfg.middlewareTenant)
+	}
+	router.Use(middlewareJSONNaming)
+	// Inject latency, errors and dropped connections for resilience testing if configured; off by default.
+	chaosInjector, err := chaos.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Couldn't set up fault injection: %v", err)
+	}
+	if chaosInjector != nil {
+		log.Println("WARNING: CHAOS_RATE is set; faults are injected into requests")
+		router.Use(chaosInjector.Middleware)
+	}
 
 	// Route for the root path: Serve the embedded index.html as the main page.
 	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -87,18 +366,187 @@ func main() {
 		}
 	})
 
+	// The Web Push service worker is served from the root so its scope covers the page.
+	router.Get("/sw.js", func(w http.ResponseWriter, r *http.Request) {
+		f, err := staticFiles.Open("static/sw.js")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		if _, err := io.Copy(w, f); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	// Published notes are rendered as plain HTML pages under /site, only if DB is connected.
+	if apiCfg.DB != nil {
+		router.Get("/site/{userID}", apiCfg.handlerSiteIndex)
+		router.Get("/site/{userID}/{noteID}", apiCfg.handlerSiteNote)
+		router.Get("/u/{username}", apiCfg.handlerProfile)
+	}
+
+	// Crawlers are kept out of the API and pointed to a sitemap of public profiles and notes, if SITE_URL is set.
+	router.Get("/robots.txt", apiCfg.handlerRobots)
+	if apiCfg.DB != nil && apiCfg.SiteURL != "" {
+		router.Get("/sitemap.xml", apiCfg.handlerSitemapIndex)
+		router.Get("/sitemap/{page}.xml", apiCfg.handlerSitemapPage)
+	}
+
+	// Stripe reports subscription changes here, if billing is configured.
+	if apiCfg.DB != nil && apiCfg.Stripe != nil {
+		router.Post("/webhooks/stripe", handle(apiCfg.handlerStripeWebhook))
+	}
+
+	// Model Context Protocol endpoint so AI assistants can use a user's notes with their API key.
+	if apiCfg.DB != nil {
+		router.Post("/mcp", apiCfg.middlewareAuth(apiCfg.handlerMCP(apiCfg.newMCPServer())))
+	}
+
 	// Set up API routes under /v1, only if DB is connected (for data operations).
 	v1Router := chi.NewRouter()
+	// Record API requests and responses for replaying with cmd/replay if configured; off by default.
+	if path := os.Getenv("RECORD_FILE"); path != "" {
+		recorder, err := recording.Open(path)
+		if err != nil {
+			log.Fatalf("Couldn't open recording file: %v", err)
+		}
+		defer recorder.Close()
+		v1Router.Use(recorder.Middleware)
+	}
+	v1Router.Use(apiCfg.middlewareDryRun)
+	if apiCfg.Usage != nil {
+		v1Router.Use(apiCfg.middlewareUsage)
+	}
 	if apiCfg.DB != nil {
-		v1Router.Post("/users", apiCfg.handlerUsersCreate)
+		v1Router.Post("/users", handle(apiCfg.handlerUsersCreate))
 		v1Router.Get("/users", apiCfg.middlewareAuth(apiCfg.handlerUsersGet))
+		v1Router.Put("/users/profile", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerUsersProfileUpdate)))
+		v1Router.Put("/users/username", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerUsernameUpdate)))
+		v1Router.Get("/users/by-username/{username}", apiCfg.middlewareAuth(apiCfg.handlerUsersByUsername))
 		v1Router.Get("/notes", apiCfg.middlewareAuth(apiCfg.handlerNotesGet))
-		v1Router.Post("/notes", apiCfg.middlewareAuth(apiCfg.handlerNotesCreate))
+		v1Router.Post("/notes", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNotesCreate)))
+		v1Router.Post("/notes/bulk", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNotesBulkCreate)))
+		v1Router.Get("/notes/search", apiCfg.middlewareAuth(apiCfg.handlerNotesSearch))
+		if apiCfg.Embedder != nil {
+			v1Router.Get("/notes/semantic-search", apiCfg.middlewareAuth(apiCfg.handlerNotesSemanticSearch))
+		}
+		v1Router.Get("/notes/title-suggest", apiCfg.middlewareAuth(apiCfg.handlerNoteTitleSuggest))
+		v1Router.Get("/notes/trash", apiCfg.middlewareAuth(apiCfg.handlerNotesTrashGet))
+		v1Router.Delete("/notes/trash/{noteID}", apiCfg.middlewareAuth(apiCfg.handlerNoteTrashDelete))
+		v1Router.Get("/quick", apiCfg.middlewareAuth(apiCfg.handlerQuick))
+		v1Router.Post("/capture", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerCapture)))
+		v1Router.Post("/publish", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNotesPublish)))
+		v1Router.Get("/notes/{noteID}", apiCfg.middlewareAuth(apiCfg.handlerNoteGet))
+		v1Router.Delete("/notes/{noteID}", apiCfg.middlewareAuth(apiCfg.handlerNoteDelete))
+		v1Router.Post("/notes/{noteID}/restore", apiCfg.middlewareAuth(apiCfg.handlerNoteRestore))
+		v1Router.Post("/notes/{noteID}/archive", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteArchive)))
+		v1Router.Post("/notes/{noteID}/unarchive", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteUnarchive)))
+		v1Router.Post("/notes/{noteID}/pin", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNotePin)))
+		v1Router.Post("/notes/{noteID}/unpin", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteUnpin)))
+		v1Router.Put("/notes/{noteID}/expiration", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteExpirationSet)))
+		v1Router.Put("/notes/{noteID}/noindex", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteNoindexSet)))
+		v1Router.Put("/notes/{noteID}/tags", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteTagsSet)))
+		v1Router.Put("/notes/{noteID}/notebook", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteNotebookSet)))
+		v1Router.Get("/notes/{noteID}/comments", apiCfg.middlewareAuth(apiCfg.handlerNoteCommentsGet))
+		v1Router.Post("/notes/{noteID}/comments", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteCommentsCreate)))
+		v1Router.Delete("/notes/{noteID}/comments/{commentID}", apiCfg.middlewareAuth(apiCfg.handlerNoteCommentsDelete))
+		v1Router.Put("/notes/{noteID}/reactions/{emoji}", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNoteReactionAdd)))
+		v1Router.Delete("/notes/{noteID}/reactions/{emoji}", apiCfg.middlewareAuth(apiCfg.handlerNoteReactionRemove))
+		v1Router.Put("/notes/{noteID}/comments/{commentID}/reactions/{emoji}", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerCommentReactionAdd)))
+		v1Router.Delete("/notes/{noteID}/comments/{commentID}/reactions/{emoji}", apiCfg.middlewareAuth(apiCfg.handlerCommentReactionRemove))
+		v1Router.Get("/notes/{noteID}/links", apiCfg.middlewareAuth(apiCfg.handlerNoteLinks))
+		v1Router.Get("/notes/{noteID}/related", apiCfg.middlewareAuth(apiCfg.handlerNoteRelated))
+		v1Router.Get("/notes/{noteID}/suggested-tags", apiCfg.middlewareAuth(apiCfg.handlerNoteSuggestedTags))
+		v1Router.Get("/tags", apiCfg.middlewareAuth(apiCfg.handlerTagsGet))
+		v1Router.Get("/notebooks", apiCfg.middlewareAuth(apiCfg.handlerNotebooksGet))
+		v1Router.Post("/notebooks", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNotebooksCreate)))
+		v1Router.Get("/notebooks/{notebookID}", apiCfg.middlewareAuth(apiCfg.handlerNotebookGet))
+		v1Router.Put("/notebooks/{notebookID}", apiCfg.middlewareAuth(apiCfg.requireWritable(apiCfg.handlerNotebookUpdate)))
+		v1Router.Delete("/notebooks/{notebookID}", apiCfg.middlewareAuth(apiCfg.handlerNotebookDelete))
+		if apiCfg.Stripe != nil {
+			v1Router.Get("/billing/portal", apiCfg.middlewareAuth(apiCfg.handlerBillingPortal))
+		}
+		if apiCfg.LLM != nil {
+			v1Router.Post("/notes/{noteID}/summarize", apiCfg.middlewareAuth(apiCfg.handlerNoteSummarize))
+		}
+		if apiCfg.Translator != nil {
+			v1Router.Post("/notes/{noteID}/translate", apiCfg.middlewareAuth(apiCfg.handlerNoteTranslate))
+		}
+		if apiCfg.LanguageTool != nil {
+			v1Router.Post("/check", apiCfg.middlewareAuth(apiCfg.handlerCheck))
+		}
+		v1Router.Get("/notifications", apiCfg.middlewareAuth(apiCfg.handlerNotificationsGet))
+		v1Router.Post("/notifications/read", apiCfg.middlewareAuth(apiCfg.handlerNotificationsReadAll))
+		v1Router.Post("/notifications/{notificationID}/read", apiCfg.middlewareAuth(apiCfg.handlerNotificationRead))
+		v1Router.Get("/notifications/preferences", apiCfg.middlewareAuth(apiCfg.handlerNotificationPreferencesGet))
+		v1Router.Put("/notifications/preferences", apiCfg.middlewareAuth(apiCfg.handlerNotificationPreferencesUpdate))
+		v1Router.Post("/notifications/preferences/reset", apiCfg.middlewareAuth(apiCfg.handlerNotificationPreferencesReset))
+		v1Router.Get("/notifications/quiet-hours", apiCfg.middlewareAuth(apiCfg.handlerQuietHoursGet))
+		v1Router.Put("/notifications/quiet-hours", apiCfg.middlewareAuth(apiCfg.handlerQuietHoursUpdate))
+		v1Router.Delete("/notifications/quiet-hours", apiCfg.middlewareAuth(apiCfg.handlerQuietHoursDelete))
+		v1Router.Post("/devices", apiCfg.middlewareAuth(apiCfg.handlerDevicesCreate))
+		v1Router.Get("/devices", apiCfg.middlewareAuth(apiCfg.handlerDevicesGet))
+		v1Router.Delete("/devices/{deviceID}", apiCfg.middlewareAuth(apiCfg.handlerDevicesDelete))
+		if apiCfg.WebPush != nil {
+			v1Router.Get("/webpush/public-key", apiCfg.handlerWebPushPublicKey)
+			v1Router.Post("/webpush/subscriptions", apiCfg.middlewareAuth(apiCfg.handlerWebPushSubscriptionsCreate))
+			v1Router.Get("/webpush/subscriptions", apiCfg.middlewareAuth(apiCfg.handlerWebPushSubscriptionsGet))
+			v1Router.Delete("/webpush/subscriptions/{subscriptionID}", apiCfg.middlewareAuth(apiCfg.handlerWebPushSubscriptionsDelete))
+		}
+		v1Router.Get("/keys", apiCfg.middlewareAuth(apiCfg.handlerKeysGet))
+		v1Router.Post("/keys/{keyID}/rotate", apiCfg.middlewareAuth(apiCfg.handlerKeysRotate))
 	}
-	v1Router.Get("/healthz", handlerReadiness)
+	v1Router.Get("/healthz", apiCfg.handlerReadiness)
 
 	router.Mount("/v1", v1Router)
 
+	// Interactive console for trying the /v1 endpoints with an API key.
+	consoleRoutes, err := handlerConsoleRoutes(v1Router, "/v1")
+	if err != nil {
+		log.Fatalf("Couldn't list routes for the console: %v", err)
+	}
+	router.Get("/console", handlerConsole)
+	router.Get("/console/routes", consoleRoutes)
+
+	// Operational endpoints, only if an admin key is configured.
+	if apiCfg.AdminAPIKey != "" {
+		adminRouter := chi.NewRouter()
+		adminRouter.Get("/", apiCfg.handlerAdminUI)
+		adminRouter.Get("/features", apiCfg.middlewareAdmin(apiCfg.handlerAdminFeaturesGet))
+		if apiCfg.DB != nil {
+			adminRouter.Get("/stats", apiCfg.middlewareAdmin(apiCfg.handlerAdminStatsGet))
+			adminRouter.Get("/users", apiCfg.middlewareAdmin(apiCfg.handlerAdminUsersGet))
+			adminRouter.Post("/users/{userID}/revoke-keys", apiCfg.middlewareAdmin(apiCfg.handlerAdminUserKeysRevoke))
+			adminRouter.Get("/plans", apiCfg.middlewareAdmin(apiCfg.handlerAdminPlansGet))
+			adminRouter.Put("/users/{userID}/plan", apiCfg.middlewareAdmin(apiCfg.handlerAdminUserPlanSet))
+			adminRouter.Post("/rebuild", apiCfg.middlewareAdmin(apiCfg.handlerRebuildStart))
+			adminRouter.Get("/rebuild", apiCfg.middlewareAdmin(apiCfg.handlerRebuildGet))
+		}
+		if apiCfg.Tenants != nil {
+			adminRouter.Get("/tenants", apiCfg.middlewareAdmin(apiCfg.handlerAdminTenantsGet))
+			adminRouter.Put("/tenants/{tenantID}", apiCfg.middlewareAdmin(apiCfg.handlerAdminTenantPut))
+			adminRouter.Delete("/tenants/{tenantID}", apiCfg.middlewareAdmin(apiCfg.handlerAdminTenantDelete))
+		}
+		if apiCfg.Usage != nil {
+			adminRouter.Get("/usage", apiCfg.middlewareAdmin(apiCfg.handlerAdminUsageGet))
+		}
+		if apiCfg.Mailer != nil {
+			adminRouter.Post("/email/test", apiCfg.middlewareAdmin(apiCfg.handlerAdminEmailTest))
+		}
+		if apiCfg.SLO != nil {
+			adminRouter.Get("/slo", apiCfg.middlewareAdmin(apiCfg.handlerSLOGet))
+		}
+		router.Mount("/admin", adminRouter)
+	}
+
+	// Preview email templates in development, i.e. when email is only logged.
+	if _, ok := apiCfg.Mailer.(*mail.Console); ok {
+		router.Get("/dev/emails", apiCfg.handlerEmailPreviews)
+		router.Get("/dev/emails/{name}", apiCfg.handlerEmailPreview)
+	}
+
 	// Configure and start the HTTP server with timeout for security against attacks.
 	srv := &http.Server{
 		Addr:              ":" + port,
@@ -106,6 +554,135 @@ func main() {
 		ReadHeaderTimeout: 10 * time.Second, // Timeout to prevent slow attacks on the server.
 	}
 
-	log.Printf("Serving on port: %s\n", port)
-	log.Fatal(srv.ListenAndServe())
+	// Stop gracefully on SIGINT/SIGTERM: fail readiness first so load balancers stop
+	// routing here, give them time to notice, then finish in-flight requests.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Fetch link previews for notes and re-check linked URLs in the background while serving.
+	if apiCfg.DB != nil {
+		go apiCfg.runLinkPreviews(ctx)
+		if interval := durationFromEnv("LINK_CHECK_INTERVAL", defaultLinkCheckInterval); interval > 0 {
+			go apiCfg.runLinkChecks(ctx, interval)
+		}
+	}
+
+	// Index note titles for fuzzy suggestions, or drop the index if they've been turned off.
+	if apiCfg.DB != nil {
+		go apiCfg.runTitleTrigramBackfill(ctx)
+	}
+
+	// Finish a rebuild of derived data that a restart interrupted.
+	if apiCfg.DB != nil {
+		go apiCfg.resumeIndexRebuild(ctx)
+	}
+
+	// Write published page views to the database in batches rather than one UPDATE per view.
+	if apiCfg.DB != nil {
+		flushInterval := durationFromEnv("NOTE_VIEW_FLUSH_INTERVAL", defaultNoteViewFlush)
+		if flushInterval <= 0 {
+			log.Fatal("NOTE_VIEW_FLUSH_INTERVAL must be positive")
+		}
+		go apiCfg.runNoteViewFlushes(ctx, flushInterval)
+	}
+
+	// Write metered API calls to the database in batches, and measure what each tenant stores.
+	if apiCfg.Usage != nil {
+		flushInterval := durationFromEnv("USAGE_FLUSH_INTERVAL", defaultUsageFlush)
+		measureInterval := durationFromEnv("USAGE_MEASURE_INTERVAL", defaultUsageMeasurement)
+		if flushInterval <= 0 || measureInterval <= 0 {
+			log.Fatal("USAGE_FLUSH_INTERVAL and USAGE_MEASURE_INTERVAL must be positive")
+		}
+		go apiCfg.runUsageFlushes(ctx, flushInterval)
+		go apiCfg.runUsageMeasurements(ctx, measureInterval)
+	}
+
+	// Delete expired notes in the background, announcing them beforehand.
+	if apiCfg.DB != nil {
+		purgeInterval := durationFromEnv("NOTE_PURGE_INTERVAL", defaultNotePurgeInterval)
+		if purgeInterval <= 0 {
+			log.Fatal("NOTE_PURGE_INTERVAL must be positive")
+		}
+		go apiCfg.runNotePurge(ctx, purgeInterval, durationFromEnv("NOTE_EXPIRY_WARNING", defaultNoteExpiryWarning))
+	}
+
+	// End trials of paid plans in the background, warning their users beforehand.
+	if apiCfg.DB != nil && apiCfg.EnforcePlans {
+		trialInterval := durationFromEnv("TRIAL_CHECK_INTERVAL", defaultTrialInterval)
+		if trialInterval <= 0 {
+			log.Fatal("TRIAL_CHECK_INTERVAL must be positive")
+		}
+		go apiCfg.runTrialExpiry(ctx, trialInterval, durationFromEnv("TRIAL_EXPIRY_WARNING", defaultTrialWarning))
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Serving on port: %s\n", port)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+	stop() // A second signal kills the process immediately.
+
+	apiCfg.draining.Store(true)
+	log.Printf("Shutting down, draining for %s", shutdownDrain)
+	time.Sleep(shutdownDrain)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Couldn't shut down cleanly: %v", err)
+	}
+	if apiCfg.DB != nil {
+		if err := apiCfg.flushNoteViews(shutdownCtx); err != nil {
+			log.Printf("Couldn't flush note views: %v", err)
+		}
+	}
+	if apiCfg.Usage != nil {
+		if err := apiCfg.flushUsage(shutdownCtx); err != nil {
+			log.Printf("Couldn't flush API call counts: %v", err)
+		}
+	}
+	if apiCfg.Tenants != nil {
+		apiCfg.Tenants.Close()
+	}
+	if err := apiCfg.Events.Close(); err != nil {
+		log.Printf("Couldn't flush events: %v", err)
+	}
+	if err := apiCfg.Audit.Close(); err != nil {
+		log.Printf("Couldn't close security log: %v", err)
+	}
+	log.Println("Server stopped")
+}
+
+// durationFromEnv reads a non-negative Go duration (e.g. "1h30m") from the
+// environment, returning def when the variable is unset.
+func durationFromEnv(name string, def time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Fatalf("%s must be a non-negative duration: %q", name, value)
+	}
+	return d
+}
+
+// intFromEnv reads a positive integer from the environment, returning def
+// when the variable is unset.
+func intFromEnv(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		log.Fatalf("%s must be a positive integer: %q", name, value)
+	}
+	return n
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/auth"
+	"github.com/go-chi/chi/v5"
+)
+
+// middlewareAdmin only lets requests through that carry ADMIN_API_KEY in
+// the usual "ApiKey <key>" header. Admin routes aren't mounted at all when
+// it isn't set. Every request is written to the security log: those let
+// through as admin actions, the rest as authentication failures, or as
+// denied if the key is a user's.
+func (cfg *apiConfig) middlewareAdmin(handler appHandler) http.HandlerFunc {
+	return handle(func(w http.ResponseWriter, r *http.Request) error {
+		apiKey, err := auth.GetAPIKey(r.Header)
+		if err != nil {
+			cfg.logSecurityEvent(r, audit.Event{
+				Type:    audit.TypeAuthFailure,
+				Outcome: audit.OutcomeFailure,
+				Reason:  err.Error(),
+				Actor:   audit.Actor{Type: audit.ActorAnonymous},
+			})
+			return errUnauthorized("Couldn't find api key", err)
+		}
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(cfg.AdminAPIKey)) != 1 {
+			cfg.logAdminKeyRejected(r, apiKey)
+			return errUnauthorized("Invalid admin api key", nil)
+		}
+
+		err = handler(w, r)
+		e := audit.Event{
+			Type:  audit.TypeAdminAction,
+			Actor: audit.Actor{Type: audit.ActorAdmin},
+		}
+		if userID := chi.URLParam(r, "userID"); userID != "" {
+			e.Target = &audit.Target{UserID: userID}
+		}
+		if err != nil {
+			e.Outcome, e.Reason = audit.OutcomeFailure, auditFailureReason(err)
+		}
+		cfg.logSecurityEvent(r, e)
+		return err
+	})
+}
+
+// logAdminKeyRejected logs a request to an admin endpoint with the wrong
+// key. A user's own key gets them a permission denial rather than an
+// authentication failure, so the SIEM can tell who tried.
+func (cfg *apiConfig) logAdminKeyRejected(r *http.Request, apiKey string) {
+	e := audit.Event{
+		Type:    audit.TypeAuthFailure,
+		Outcome: audit.OutcomeFailure,
+		Reason:  "invalid admin api key",
+		Actor:   audit.Actor{Type: audit.ActorAnonymous, KeyFingerprint: audit.KeyFingerprint(apiKey)},
+	}
+	if cfg.DB != nil {
+		if user, err := cfg.lookupUserByAPIKey(r.Context(), apiKey); err == nil {
+			e.Type, e.Reason = audit.TypePermissionDenied, "not an admin"
+			e.Actor = audit.Actor{Type: audit.ActorUser, UserID: user.ID}
+		}
+	}
+	cfg.logSecurityEvent(r, e)
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+)
+
+// middlewareAlerts counts every response's status towards the 5xx rate
+// alert, including responses from middleware such as the load shedder.
+func (cfg *apiConfig) middlewareAlerts(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		cfg.Alerts.RecordRequest(rec.status)
+	})
+}
@@ -1,28 +1,67 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/audit"
 	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/auth"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/ctxkeys"
 	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
 )
 
-type authedHandler func(http.ResponseWriter, *http.Request, database.User)
+type authedHandler func(http.ResponseWriter, *http.Request, database.User) error
 
 func (cfg *apiConfig) middlewareAuth(handler authedHandler) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	return handle(func(w http.ResponseWriter, r *http.Request) error {
 		apiKey, err := auth.GetAPIKey(r.Header)
 		if err != nil {
-			respondWithError(w, http.StatusUnauthorized, "Couldn't find api key", err)
-			return
+			cfg.logSecurityEvent(r, audit.Event{
+				Type:    audit.TypeAuthFailure,
+				Outcome: audit.OutcomeFailure,
+				Reason:  err.Error(),
+				Actor:   audit.Actor{Type: audit.ActorAnonymous},
+			})
+			return errUnauthorized("Couldn't find api key", err)
 		}
 
-		user, err := cfg.DB.GetUser(r.Context(), apiKey)
-		if err != nil {
-			respondWithError(w, http.StatusNotFound, "Couldn't get user", err)
-			return
+		user, gen, ok := cfg.authCache.get(r.Context(), apiKey)
+		if !ok {
+			user, err = cfg.lookupUserByAPIKey(r.Context(), apiKey)
+			if errors.Is(err, database.ErrNotFound) {
+				cfg.logSecurityEvent(r, audit.Event{
+					Type:    audit.TypeAuthFailure,
+					Outcome: audit.OutcomeFailure,
+					Reason:  "unknown or expired api key",
+					Actor:   audit.Actor{Type: audit.ActorAnonymous, KeyFingerprint: audit.KeyFingerprint(apiKey)},
+				})
+			}
+			if err != nil {
+				return errInternal("Couldn't get user", err)
+			}
+			cfg.authCache.put(r.Context(), apiKey, user, gen)
 		}
 
-		handler(w, r, user)
+		return handler(w, r.WithContext(ctxkeys.WithUser(r.Context(), user)), user)
+	})
+}
+
+// lookupUserByAPIKey finds the user apiKey belongs to, sharing one query
+// between concurrent requests with the same key for the same tenant.
+func (cfg *apiConfig) lookupUserByAPIKey(ctx context.Context, apiKey string) (database.User, error) {
+	v, err, _ := cfg.userLookups.Do(tenantID(ctx)+"/"+apiKey, func() (any, error) {
+		// Other requests may be waiting on the result even if this one is
+		// canceled. Rotated keys stay valid until their expires_at.
+		return cfg.DB.GetUserByAPIKey(context.WithoutCancel(ctx), database.GetUserByAPIKeyParams{
+			ApiKey: apiKey,
+			Now:    sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true},
+		})
+	})
+	if err != nil {
+		return database.User{}, err
 	}
+	return v.(database.User), nil
 }
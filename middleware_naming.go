@@ -0,0 +1,67 @@
+package main
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// camelCaseParam is the Accept parameter that asks for camelCase keys:
+// "Accept: application/json; naming=camelCase".
+const camelCaseParam = "naming"
+
+// camelCaseWriter marks a response whose JSON keys respondWithJSON should
+// rename to camelCase.
+type camelCaseWriter struct {
+	http.ResponseWriter
+}
+
+func (w *camelCaseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *camelCaseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// middlewareJSONNaming lets clients choose camelCase keys in JSON responses
+// through the Accept header; snake_case stays the default.
+func middlewareJSONNaming(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		if wantsCamelCase(r.Header.Get("Accept")) {
+			w = &camelCaseWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wantsCamelCase reports whether accept lists application/json with
+// naming=camelCase.
+func wantsCamelCase(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err == nil && mediaType == "application/json" && params[camelCaseParam] == "camelCase" {
+			return true
+		}
+	}
+	return false
+}
+
+// camelCaseResponse reports whether w's client asked for camelCase keys.
+// Middleware inside middlewareJSONNaming may have wrapped the writer, so it
+// looks through writers that Unwrap.
+func camelCaseResponse(w http.ResponseWriter) bool {
+	for {
+		switch u := w.(type) {
+		case *camelCaseWriter:
+			return true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = u.Unwrap()
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/recording"
+)
+
+func TestMiddlewareJSONNaming(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondWithJSON(w, http.StatusOK, struct {
+			CreatedAt string `json:"created_at"`
+			APIKey    string `json:"api_key"`
+		}{CreatedAt: "2024-01-01T00:00:00Z", APIKey: "secret"})
+	})
+
+	tests := []struct {
+		name   string
+		accept string
+		record bool
+		want   string
+	}{
+		{name: "default", want: `"created_at"`},
+		{name: "camelCase", accept: "application/json; naming=camelCase", want: `"createdAt"`},
+		{name: "recorded default", record: true, want: `"created_at"`},
+		{name: "recorded camelCase", accept: "application/json; naming=camelCase", record: true, want: `"createdAt"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h http.Handler = handler
+			var rec *recording.Recorder
+			path := t.TempDir() + "/rec.jsonl"
+			if tt.record {
+				var err error
+				rec, err = recording.Open(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				h = rec.Middleware(h)
+			}
+			h = middlewareJSONNaming(h)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if body := w.Body.String(); !strings.Contains(body, tt.want) {
+				t.Errorf("body = %s, want a %s key", body, tt.want)
+			}
+			if rec == nil {
+				return
+			}
+
+			if err := rec.Close(); err != nil {
+				t.Fatal(err)
+			}
+			recorded, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(recorded), "secret") {
+				t.Errorf("recording holds the API key: %s", recorded)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/ctxkeys"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength bounds client-supplied request IDs so they can't bloat logs.
+const maxRequestIDLength = 128
+
+// middlewareRequestID tags every request with an ID (reusing a sane X-Request-ID
+// from the client) and the trace ID from a W3C traceparent header, if present.
+func middlewareRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" || len(requestID) > maxRequestIDLength || strings.ContainsAny(requestID, " \t\r\n") {
+			requestID = uuid.New().String()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		ctx := ctxkeys.WithRequestID(r.Context(), requestID)
+		if traceID := traceIDFromTraceparent(r.Header.Get("traceparent")); traceID != "" {
+			ctx = ctxkeys.WithTraceID(ctx, traceID)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// traceIDFromTraceparent extracts the trace-id field of a "version-traceid-parentid-flags"
+// traceparent header, returning "" when the header is missing or malformed.
+// It runs on every request, so it slices the header rather than splitting it.
+func traceIDFromTraceparent(header string) string {
+	_, rest, ok := strings.Cut(header, "-")
+	if !ok {
+		return ""
+	}
+	traceID, rest, ok := strings.Cut(rest, "-")
+	if !ok {
+		return ""
+	}
+	// Exactly two more fields: parent-id and flags.
+	if strings.Count(rest, "-") != 1 || len(traceID) != 32 {
+		return ""
+	}
+	for i := 0; i < len(traceID); i++ {
+		if c := traceID[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return ""
+		}
+	}
+	if strings.Trim(traceID, "0") == "" {
+		return ""
+	}
+	return traceID
+}
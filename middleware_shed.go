@@ -0,0 +1,133 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// shedRetryAfter is what shed requests are told to wait before retrying.
+const shedRetryAfter = time.Second
+
+// requestClass groups requests that share a concurrency limit, so a burst in
+// one class can't take the slots of another.
+type requestClass int
+
+const (
+	classInteractive requestClass = iota // Reads a user is waiting on.
+	classWrite                           // Everything else that changes data.
+	classBulk                            // Slow calls out to other services.
+	numRequestClasses
+)
+
+var requestClassNames = [numRequestClasses]string{"interactive", "write", "bulk"}
+
+// bulkPathSuffixes identify the routes in classBulk: each of them waits on
+// a fetched web page, an LLM or a translation service, or writes hundreds
+// of notes at once.
+var bulkPathSuffixes = []string{"/capture", "/summarize", "/translate", "/notes/bulk"}
+
+// requestClassOf classifies r by its method and path.
+func requestClassOf(r *http.Request) requestClass {
+	for _, suffix := range bulkPathSuffixes {
+		if strings.HasSuffix(r.URL.Path, suffix) {
+			return classBulk
+		}
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return classInteractive
+	}
+	return classWrite
+}
+
+// slotPool caps how many requests of a class are handled at once.
+type slotPool struct {
+	slots   chan struct{} // Holds a token per request being handled.
+	waiting atomic.Int64
+}
+
+// loadShedder caps how many requests of each class are handled at once.
+// Requests over the cap wait for a slot, but only up to maxWait and only as
+// many as could be handled in one go; the rest are turned away with a 503
+// straight away, so an overloaded server answers quickly instead of timing
+// out everything.
+type loadShedder struct {
+	pools   [numRequestClasses]slotPool
+	maxWait time.Duration
+
+	shed    atomic.Int64
+	logShed rate.Sometimes
+}
+
+// newLoadShedder allows limits[c] concurrent requests of class c.
+func newLoadShedder(limits [numRequestClasses]int, maxWait time.Duration) *loadShedder {
+	s := &loadShedder{
+		maxWait: maxWait,
+		logShed: rate.Sometimes{Interval: 10 * time.Second},
+	}
+	for c, limit := range limits {
+		s.pools[c].slots = make(chan struct{}, limit)
+	}
+	return s
+}
+
+// middleware applies the caps to everything except health checks and admin
+// routes, which must keep answering for operators and load balancers to
+// see and fix the overload.
+func (s *loadShedder) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/healthz" || r.URL.Path == "/admin" || strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		class := requestClassOf(r)
+		pool := &s.pools[class]
+		if !s.acquire(r, pool) {
+			s.reject(w, class)
+			return
+		}
+		defer func() { <-pool.slots }()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *loadShedder) acquire(r *http.Request, pool *slotPool) bool {
+	select {
+	case pool.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if pool.waiting.Add(1) > int64(cap(pool.slots)) {
+		pool.waiting.Add(-1)
+		return false
+	}
+	defer pool.waiting.Add(-1)
+	timer := time.NewTimer(s.maxWait)
+	defer timer.Stop()
+	select {
+	case pool.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func (s *loadShedder) reject(w http.ResponseWriter, class requestClass) {
+	s.shed.Add(1)
+	s.logShed.Do(func() {
+		pool := &s.pools[class]
+		log.Printf("Shedding %s load: %d requests in flight, %d waiting, %d shed so far",
+			requestClassNames[class], len(pool.slots), pool.waiting.Load(), s.shed.Load())
+	})
+	w.Header().Set("Retry-After", strconv.Itoa(int(shedRetryAfter.Seconds())))
+	respondWithJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Server is overloaded, retry later"})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/httpjson"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/slo"
+	"github.com/go-chi/chi/v5"
+)
+
+// sloAlertTimeout bounds a call to SLO_ALERT_WEBHOOK_URL.
+const sloAlertTimeout = 10 * time.Second
+
+// statusRecorder remembers the status code a handler responded with.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// middlewareSLO records every routed request's status and latency against
+// its route's objective, identifying routes by method and pattern so that
+// e.g. all notes share "GET /v1/notes/{noteID}/links". Unmatched paths
+// aren't recorded.
+func (cfg *apiConfig) middlewareSLO(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil {
+			return
+		}
+		// RoutePattern joins the pattern up on every call, so call it once.
+		pattern := rctx.RoutePattern()
+		if pattern == "" {
+			return
+		}
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		cfg.SLO.Record(r.Method+" "+pattern, rec.status, time.Since(start))
+	})
+}
+
+// sloAlertWebhook returns an alert callback that logs the alert and, if url
+// is set, posts it there as JSON.
+func sloAlertWebhook(url string) func(slo.Alert) {
+	client := &http.Client{Timeout: sloAlertTimeout}
+	return func(alert slo.Alert) {
+		log.Printf("SLO burn alert: %s at %.1fx (%.1fx recently), compliance %.4f of %.4f over %s",
+			alert.Route, alert.BurnRate, alert.ShortBurnRate, alert.Compliance, alert.Target, alert.Window)
+		if url == "" {
+			return
+		}
+		if err := httpjson.Post(context.Background(), client, url, nil, alert, nil); err != nil {
+			log.Printf("Couldn't send SLO alert: %v", err)
+		}
+	}
+}
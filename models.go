@@ -1,17 +1,21 @@
 package main
 
 import (
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
 )
 
 type User struct {
-	ID        string    `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Name      string    `json:"name"`
-	ApiKey    string    `json:"api_key"`
+	ID            string    `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Name          string    `json:"name"`
+	ApiKey        string    `json:"api_key"`
+	Username      *string   `json:"username,omitempty"`
+	ProfilePublic bool      `json:"profile_public"`
 }
 
 func databaseUserToUser(user database.User) (User, error) {
@@ -24,21 +28,98 @@ func databaseUserToUser(user database.User) (User, error) {
 	if err != nil {
 		return User{}, err
 	}
-	return User{
-		ID:        user.ID,
+	resp := User{
+		ID:            user.ID,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		Name:          user.Name,
+		ApiKey:        user.ApiKey,
+		ProfilePublic: user.ProfilePublic,
+	}
+	if user.Username.Valid {
+		resp.Username = &user.Username.String
+	}
+	return resp, nil
+}
+
+type APIKey struct {
+	ID           string     `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	Key          string     `json:"key,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	SupersededBy *string    `json:"superseded_by,omitempty"`
+}
+
+// databaseAPIKeyToAPIKey converts a stored key. The secret is only included
+// when withSecret is set, i.e. in the response that issues the key.
+func databaseAPIKeyToAPIKey(key database.ApiKey, withSecret bool) (APIKey, error) {
+	createdAt, err := time.Parse(time.RFC3339, key.CreatedAt)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	resp := APIKey{
+		ID:        key.ID,
 		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
-		Name:      user.Name,
-		ApiKey:    user.ApiKey,
-	}, nil
+	}
+	if withSecret {
+		resp.Key = key.ApiKey
+	}
+	if key.ExpiresAt.Valid {
+		expiresAt, err := time.Parse(time.RFC3339, key.ExpiresAt.String)
+		if err != nil {
+			return APIKey{}, err
+		}
+		resp.ExpiresAt = &expiresAt
+	}
+	if key.SupersededBy.Valid {
+		resp.SupersededBy = &key.SupersededBy.String
+	}
+	return resp, nil
+}
+
+// NotePage is a page of the user's notes with its meta.
+type NotePage struct {
+	Results []Note   `json:"results"`
+	Meta    PageMeta `json:"meta"`
+}
+
+// NoteCursorPage is a page of the user's notes paginated with ?cursor=.
+type NoteCursorPage struct {
+	Results []Note     `json:"results"`
+	Meta    SearchMeta `json:"meta"`
+}
+
+// PageMeta describes a page of a list paginated with ?limit= and ?offset=.
+// Total counts the whole list, so clients can tell how many pages there are.
+type PageMeta struct {
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
 }
 
 type Note struct {
-	ID        string    `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Note      string    `json:"note"`
-	UserID    string    `json:"user_id"`
+	ID          string     `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Note        string     `json:"note"`
+	Title       string     `json:"title"`
+	UserID      string     `json:"user_id"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	SourceURL   *string    `json:"source_url,omitempty"`
+	SourceTitle *string    `json:"source_title,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Noindex     bool       `json:"noindex,omitempty"` // Kept out of search engines once published.
+	NotebookID  *string    `json:"notebook_id,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // When it was moved to the trash.
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	Pinned      bool       `json:"pinned,omitempty"` // Listed first in the default order.
+
+	LinkPreviews []LinkPreview   `json:"link_previews,omitempty"`
+	Reactions    []ReactionCount `json:"reactions,omitempty"`
+	Views        int64           `json:"views,omitempty"`          // Views of the published page, as of the last flush.
+	LastViewedAt *time.Time      `json:"last_viewed_at,omitempty"` // Latest of those views.
 }
 
 func databaseNoteToNote(post database.Note) (Note, error) {
@@ -51,13 +132,56 @@ func databaseNoteToNote(post database.Note) (Note, error) {
 	if err != nil {
 		return Note{}, err
 	}
-	return Note{
-		ID:        post.ID,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
-		Note:      post.Note,
-		UserID:    post.UserID,
-	}, nil
+	var publishedAt *time.Time
+	if post.PublishedAt.Valid {
+		t, err := time.Parse(time.RFC3339, post.PublishedAt.String)
+		if err != nil {
+			return Note{}, err
+		}
+		publishedAt = &t
+	}
+	resp := Note{
+		ID:          post.ID,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+		Note:        post.Note,
+		Title:       post.Title,
+		UserID:      post.UserID,
+		PublishedAt: publishedAt,
+		Noindex:     post.Noindex,
+		Pinned:      post.Pinned,
+	}
+	if post.SourceUrl.Valid {
+		resp.SourceURL = &post.SourceUrl.String
+	}
+	if post.SourceTitle.Valid {
+		resp.SourceTitle = &post.SourceTitle.String
+	}
+	if post.NotebookID.Valid {
+		resp.NotebookID = &post.NotebookID.String
+	}
+	if post.ExpiresAt.Valid {
+		expiresAt, err := time.Parse(time.RFC3339, post.ExpiresAt.String)
+		if err != nil {
+			return Note{}, err
+		}
+		resp.ExpiresAt = &expiresAt
+	}
+	if post.DeletedAt.Valid {
+		deletedAt, err := time.Parse(time.RFC3339, post.DeletedAt.String)
+		if err != nil {
+			return Note{}, err
+		}
+		resp.DeletedAt = &deletedAt
+	}
+	if post.ArchivedAt.Valid {
+		archivedAt, err := time.Parse(time.RFC3339, post.ArchivedAt.String)
+		if err != nil {
+			return Note{}, err
+		}
+		resp.ArchivedAt = &archivedAt
+	}
+	return resp, nil
 }
 
 func databasePostsToPosts(notes []database.Note) ([]Note, error) {
@@ -72,3 +196,450 @@ func databasePostsToPosts(notes []database.Note) ([]Note, error) {
 	}
 	return result, nil
 }
+
+// ScoredNote is a search result: the note plus how closely it matched.
+type ScoredNote struct {
+	Note
+	Score float64 `json:"score"`
+}
+
+type NoteSummary struct {
+	NoteID    string    `json:"note_id"`
+	Summary   string    `json:"summary"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+	Cached    bool      `json:"cached"`
+}
+
+func databaseNoteSummaryToNoteSummary(summary database.NoteSummary, cached bool) (NoteSummary, error) {
+	createdAt, err := time.Parse(time.RFC3339, summary.CreatedAt)
+	if err != nil {
+		return NoteSummary{}, err
+	}
+	return NoteSummary{
+		NoteID:    summary.NoteID,
+		Summary:   summary.Summary,
+		Model:     summary.Model,
+		CreatedAt: createdAt,
+		Cached:    cached,
+	}, nil
+}
+
+type TagSuggestion struct {
+	Tag   string  `json:"tag"`
+	Score float64 `json:"score"`
+}
+
+// TagCount is one of a user's tags and how many of their notes have it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Notes int64  `json:"notes"`
+}
+
+// Notebook is one of a user's notebooks. ParentID is the notebook it's in,
+// if any, and Notes how many notes are in it directly.
+type Notebook struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	ParentID  *string   `json:"parent_id,omitempty"`
+	Notes     int64     `json:"notes"`
+}
+
+func databaseNotebookToNotebook(notebook database.Notebook, notes int64) (Notebook, error) {
+	createdAt, err := time.Parse(time.RFC3339, notebook.CreatedAt)
+	if err != nil {
+		return Notebook{}, err
+	}
+	updatedAt, err := time.Parse(time.RFC3339, notebook.UpdatedAt)
+	if err != nil {
+		return Notebook{}, err
+	}
+	resp := Notebook{
+		ID:        notebook.ID,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		Name:      notebook.Name,
+		Notes:     notes,
+	}
+	if notebook.ParentID.Valid {
+		resp.ParentID = &notebook.ParentID.String
+	}
+	return resp, nil
+}
+
+type NoteTranslation struct {
+	NoteID      string    `json:"note_id"`
+	Language    string    `json:"language"`
+	Translation string    `json:"translation"`
+	Provider    string    `json:"provider"`
+	CreatedAt   time.Time `json:"created_at"`
+	Cached      bool      `json:"cached"`
+}
+
+func databaseNoteTranslationToNoteTranslation(translation database.NoteTranslation, cached bool) (NoteTranslation, error) {
+	createdAt, err := time.Parse(time.RFC3339, translation.CreatedAt)
+	if err != nil {
+		return NoteTranslation{}, err
+	}
+	return NoteTranslation{
+		NoteID:      translation.NoteID,
+		Language:    translation.Language,
+		Translation: translation.Translation,
+		Provider:    translation.Provider,
+		CreatedAt:   createdAt,
+		Cached:      cached,
+	}, nil
+}
+
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+func databaseLinkPreviewToLinkPreview(preview database.LinkPreview) LinkPreview {
+	return LinkPreview{
+		URL:         preview.Url,
+		Title:       preview.Title.String,
+		Description: preview.Description.String,
+		ImageURL:    preview.ImageUrl.String,
+		SiteName:    preview.SiteName.String,
+	}
+}
+
+// Link check results reported in NoteLink.Status.
+const (
+	linkStatusUnchecked = "unchecked"
+	linkStatusOK        = "ok"
+	linkStatusBroken    = "broken"
+)
+
+type NoteLink struct {
+	URL        string     `json:"url"`
+	Status     string     `json:"status"`
+	StatusCode *int       `json:"status_code,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CheckedAt  *time.Time `json:"checked_at,omitempty"`
+}
+
+func databaseNoteLinkToNoteLink(link database.GetLinksForNoteRow) (NoteLink, error) {
+	resp := NoteLink{URL: link.Url, Status: linkStatusUnchecked}
+	if !link.CheckedAt.Valid {
+		return resp, nil
+	}
+	checkedAt, err := time.Parse(time.RFC3339, link.CheckedAt.String)
+	if err != nil {
+		return NoteLink{}, err
+	}
+	resp.CheckedAt = &checkedAt
+	resp.Status = linkStatusBroken
+	if link.Alive.Bool {
+		resp.Status = linkStatusOK
+	}
+	if link.StatusCode.Valid {
+		code := int(link.StatusCode.Int64)
+		resp.StatusCode = &code
+	}
+	resp.Error = link.Error.String
+	return resp, nil
+}
+
+type Comment struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	NoteID    string    `json:"note_id"`
+	UserID    string    `json:"user_id"`
+	Body      string    `json:"body"`
+
+	Reactions []ReactionCount `json:"reactions,omitempty"`
+}
+
+func databaseCommentToComment(comment database.NoteComment) (Comment, error) {
+	createdAt, err := time.Parse(time.RFC3339, comment.CreatedAt)
+	if err != nil {
+		return Comment{}, err
+	}
+	return Comment{
+		ID:        comment.ID,
+		CreatedAt: createdAt,
+		NoteID:    comment.NoteID,
+		UserID:    comment.UserID,
+		Body:      comment.Body,
+	}, nil
+}
+
+// ReactionCount is how many users reacted to a note or comment with Emoji.
+type ReactionCount struct {
+	Emoji string `json:"emoji"`
+	Count int64  `json:"count"`
+}
+
+type Notification struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Type      string     `json:"type"`
+	NoteID    *string    `json:"note_id,omitempty"`
+	CommentID *string    `json:"comment_id,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+type NotificationList struct {
+	UnreadCount   int64          `json:"unread_count"`
+	Notifications []Notification `json:"notifications"`
+}
+
+func databaseNotificationToNotification(notification database.Notification) (Notification, error) {
+	createdAt, err := time.Parse(time.RFC3339, notification.CreatedAt)
+	if err != nil {
+		return Notification{}, err
+	}
+	resp := Notification{
+		ID:        notification.ID,
+		CreatedAt: createdAt,
+		Type:      notification.Type,
+	}
+	if notification.NoteID.Valid {
+		resp.NoteID = &notification.NoteID.String
+	}
+	if notification.CommentID.Valid {
+		resp.CommentID = &notification.CommentID.String
+	}
+	if notification.ReadAt.Valid {
+		readAt, err := time.Parse(time.RFC3339, notification.ReadAt.String)
+		if err != nil {
+			return Notification{}, err
+		}
+		resp.ReadAt = &readAt
+	}
+	return resp, nil
+}
+
+// QuietHours is a daily do-not-disturb period, e.g. 22:00 to 07:00 in Europe/Berlin.
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+func databaseQuietHoursToQuietHours(quietHours database.NotificationQuietHour) QuietHours {
+	return QuietHours{
+		Start:    quietHours.StartTime,
+		End:      quietHours.EndTime,
+		Timezone: quietHours.Timezone,
+	}
+}
+
+// Device is a mobile app installation registered for push notifications.
+type Device struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Provider  string    `json:"provider"`
+	Token     string    `json:"token"`
+}
+
+func databaseDeviceTokenToDevice(device database.DeviceToken) (Device, error) {
+	createdAt, err := time.Parse(time.RFC3339, device.CreatedAt)
+	if err != nil {
+		return Device{}, err
+	}
+	return Device{
+		ID:        device.ID,
+		CreatedAt: createdAt,
+		Provider:  device.Provider,
+		Token:     device.Token,
+	}, nil
+}
+
+// WebPushSubscription is a browser subscribed to push notifications. The
+// encryption keys it registered with aren't returned.
+type WebPushSubscription struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Endpoint  string    `json:"endpoint"`
+}
+
+func databaseWebPushSubscriptionToWebPushSubscription(subscription database.WebPushSubscription) (WebPushSubscription, error) {
+	createdAt, err := time.Parse(time.RFC3339, subscription.CreatedAt)
+	if err != nil {
+		return WebPushSubscription{}, err
+	}
+	return WebPushSubscription{
+		ID:        subscription.ID,
+		CreatedAt: createdAt,
+		Endpoint:  subscription.Endpoint,
+	}, nil
+}
+
+// QuickResult is one entry of the command palette: a note, or an action the
+// client knows how to perform, identified by ID.
+type QuickResult struct {
+	Kind      string     `json:"kind"`
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// TitleSuggestion is a note whose title starts with what the user typed.
+type TitleSuggestion struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// IndexRebuild reports the progress of a rebuild of derived data started
+// through POST /admin/rebuild.
+type IndexRebuild struct {
+	ID         string     `json:"id"`
+	Derived    []string   `json:"derived"`
+	NotesDone  int64      `json:"notes_done"`
+	Running    bool       `json:"running"`
+	StartedAt  time.Time  `json:"started_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func databaseIndexRebuildToIndexRebuild(rebuild database.IndexRebuild) (IndexRebuild, error) {
+	startedAt, err := time.Parse(time.RFC3339, rebuild.StartedAt)
+	if err != nil {
+		return IndexRebuild{}, err
+	}
+	updatedAt, err := time.Parse(time.RFC3339, rebuild.UpdatedAt)
+	if err != nil {
+		return IndexRebuild{}, err
+	}
+	resp := IndexRebuild{
+		ID:        rebuild.ID,
+		Derived:   strings.Split(rebuild.Derived, ","),
+		NotesDone: rebuild.NotesDone,
+		StartedAt: startedAt,
+		UpdatedAt: updatedAt,
+	}
+	if rebuild.FinishedAt.Valid {
+		finishedAt, err := time.Parse(time.RFC3339, rebuild.FinishedAt.String)
+		if err != nil {
+			return IndexRebuild{}, err
+		}
+		resp.FinishedAt = &finishedAt
+	}
+	return resp, nil
+}
+
+// AdminUser is a user as listed in the admin UI, without their API key.
+type AdminUser struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Name       string    `json:"name"`
+	Username   *string   `json:"username,omitempty"`
+	Notes      int64     `json:"notes"`
+	ActiveKeys int64     `json:"active_keys"`
+}
+
+func databaseListUsersRowToAdminUser(row database.ListUsersRow) (AdminUser, error) {
+	createdAt, err := time.Parse(time.RFC3339, row.CreatedAt)
+	if err != nil {
+		return AdminUser{}, err
+	}
+	resp := AdminUser{
+		ID:         row.ID,
+		CreatedAt:  createdAt,
+		Name:       row.Name,
+		Notes:      row.Notes,
+		ActiveKeys: row.ActiveKeys,
+	}
+	if row.Username.Valid {
+		resp.Username = &row.Username.String
+	}
+	return resp, nil
+}
+
+// AdminTenant is a tenant as listed to admins. Credentials are left out of
+// its database URL.
+type AdminTenant struct {
+	ID          string    `json:"id"`
+	DatabaseURL string    `json:"database_url"`
+	Region      string    `json:"region,omitempty"` // Where the tenant's data resides, if it picked a region.
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Plan is what a plan allows; a null limit is unlimited.
+type Plan struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	MaxNotes           *int64 `json:"max_notes"`
+	MaxAttachmentBytes *int64 `json:"max_attachment_bytes"`
+	MaxCollaborators   *int64 `json:"max_collaborators"`
+}
+
+func databasePlanToPlan(plan database.Plan) Plan {
+	resp := Plan{
+		ID:   plan.ID,
+		Name: plan.Name,
+	}
+	if plan.MaxNotes.Valid {
+		resp.MaxNotes = &plan.MaxNotes.Int64
+	}
+	if plan.MaxAttachmentBytes.Valid {
+		resp.MaxAttachmentBytes = &plan.MaxAttachmentBytes.Int64
+	}
+	if plan.MaxCollaborators.Valid {
+		resp.MaxCollaborators = &plan.MaxCollaborators.Int64
+	}
+	return resp
+}
+
+// TenantUsage is what a tenant used in a billing period. The default
+// database is tenant "". Notes and storage are the most measured on any day
+// of the period.
+type TenantUsage struct {
+	Tenant       string `json:"tenant"`
+	Period       string `json:"period"`
+	APICalls     int64  `json:"api_calls"`
+	Notes        int64  `json:"notes"`
+	StorageBytes int64  `json:"storage_bytes"`
+}
+
+func databaseTenantToAdminTenant(tenant database.Tenant) (AdminTenant, error) {
+	createdAt, err := time.Parse(time.RFC3339, tenant.CreatedAt)
+	if err != nil {
+		return AdminTenant{}, err
+	}
+	updatedAt, err := time.Parse(time.RFC3339, tenant.UpdatedAt)
+	if err != nil {
+		return AdminTenant{}, err
+	}
+	// Hosted databases take their auth token as a query parameter.
+	databaseURL, err := url.Parse(tenant.DatabaseUrl)
+	if err != nil {
+		return AdminTenant{}, err
+	}
+	databaseURL.User = nil
+	databaseURL.RawQuery = ""
+	return AdminTenant{
+		ID:          tenant.ID,
+		DatabaseURL: databaseURL.String(),
+		Region:      tenant.Region.String,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}, nil
+}
+
+// AdminStats are instance-wide counts for the admin UI.
+type AdminStats struct {
+	Users            int64 `json:"users"`
+	Notes            int64 `json:"notes"`
+	PublishedNotes   int64 `json:"published_notes"`
+	Comments         int64 `json:"comments"`
+	PendingNoteViews int   `json:"pending_note_views"` // Notes with views not yet flushed on this instance.
+}
+
+// Feature is an optional capability and whether this instance has it on.
+// Features are configured through the environment variable Env.
+type Feature struct {
+	Name    string `json:"name"`
+	Env     string `json:"env"`
+	Enabled bool   `json:"enabled"`
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"slices"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// noteFilter narrows the note list down to the notes with a tag, in a
+// notebook, or both, among either the archived notes or the rest. Its zero
+// value lists every note that isn't archived.
+type noteFilter struct {
+	tag        string
+	notebookID string
+	archived   bool
+}
+
+// queryNoteFilter reads ?tag=, normalized like the tags it's compared to,
+// ?notebook_id=, which must be one of the user's notebooks, and
+// ?archived=true.
+func (cfg *apiConfig) queryNoteFilter(r *http.Request, user database.User) (noteFilter, error) {
+	query := r.URL.Query()
+	f := noteFilter{
+		notebookID: query.Get("notebook_id"),
+		archived:   query.Get("archived") == "true",
+	}
+	if tag := query.Get("tag"); tag != "" {
+		var err error
+		f.tag, err = normalizeTag(tag)
+		if err != nil {
+			return noteFilter{}, err
+		}
+	}
+	if f.notebookID != "" {
+		if _, err := cfg.notebookRef(r.Context(), user.ID, &f.notebookID); err != nil {
+			return noteFilter{}, err
+		}
+	}
+	return f, nil
+}
+
+func (f noteFilter) empty() bool {
+	return f == noteFilter{}
+}
+
+func (f noteFilter) matches(note Note) bool {
+	if (note.ArchivedAt != nil) != f.archived {
+		return false
+	}
+	if f.tag != "" && !slices.Contains(note.Tags, f.tag) {
+		return false
+	}
+	if f.notebookID != "" && (note.NotebookID == nil || *note.NotebookID != f.notebookID) {
+		return false
+	}
+	return true
+}
+
+// apply returns the notes among notes that match, leaving notes itself as
+// it is since it may be shared.
+func (f noteFilter) apply(notes []Note) []Note {
+	matching := []Note{}
+	for _, note := range notes {
+		if f.matches(note) {
+			matching = append(matching, note)
+		}
+	}
+	return matching
+}
+
+// cursorParts identifies the filter in the query hash of cursors. Tags
+// can't contain ":", so they can't be mistaken for the other parts.
+func (f noteFilter) cursorParts() []string {
+	var parts []string
+	if f.tag != "" {
+		parts = append(parts, f.tag)
+	}
+	if f.notebookID != "" {
+		parts = append(parts, "notebook:"+f.notebookID)
+	}
+	if f.archived {
+		parts = append(parts, "archived:true")
+	}
+	return parts
+}
+
+// filteredNotes returns all of the user's notes that match f, in order.
+func (cfg *apiConfig) filteredNotes(ctx context.Context, userID string, f noteFilter, o noteOrder) ([]database.Note, error) {
+	var notes []database.Note
+	var err error
+	switch {
+	case f.tag != "":
+		notes, err = cfg.DB.GetNotesForUserWithTag(ctx, database.GetNotesForUserWithTagParams{
+			UserID: userID,
+			Tag:    f.tag,
+		})
+	case f.notebookID != "":
+		notes, err = cfg.DB.GetNotesForUserInNotebook(ctx, database.GetNotesForUserInNotebookParams{
+			UserID:     userID,
+			NotebookID: sql.NullString{String: f.notebookID, Valid: true},
+		})
+	default:
+		notes, err = cfg.DB.GetArchivedNotesForUser(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	notes = slices.DeleteFunc(notes, func(note database.Note) bool {
+		if f.notebookID != "" && note.NotebookID.String != f.notebookID {
+			return true
+		}
+		return note.ArchivedAt.Valid != f.archived
+	})
+	o.sortNotes(notes)
+	return notes, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+// noteOrder is the order the note list is in: by sort, "created_at" or
+// "updated_at", with the ID breaking ties, and order, "asc" or "desc".
+// With pinned, pinned notes come before the rest.
+type noteOrder struct {
+	sort   string
+	order  string
+	pinned bool
+}
+
+// defaultNoteOrder is the order of lists the client didn't ask for an
+// order of: newest first, after the pinned notes.
+var defaultNoteOrder = noteOrder{sort: "created_at", order: "desc", pinned: true}
+
+// notePosition is where a note is in an order: after whether it's pinned,
+// if that counts, by its key and then its ID.
+type notePosition struct {
+	pinned bool
+	key    string
+	id     string
+}
+
+// queryNoteOrder reads ?sort= and ?order=, which default to created_at and
+// desc, i.e. newest first. Only columns there's an index and a query for
+// are allowed. Pinned notes only come first if neither is given, since a
+// client asking for an order expects the notes in exactly that order.
+func queryNoteOrder(r *http.Request) (noteOrder, error) {
+	o := defaultNoteOrder
+	query := r.URL.Query()
+	if query.Has("sort") || query.Has("order") {
+		o.pinned = false
+	}
+	if v := query.Get("sort"); v != "" {
+		if v != "created_at" && v != "updated_at" {
+			return noteOrder{}, errValidation("sort must be created_at or updated_at", nil)
+		}
+		o.sort = v
+	}
+	if v := query.Get("order"); v != "" {
+		if v != "asc" && v != "desc" {
+			return noteOrder{}, errValidation("order must be asc or desc", nil)
+		}
+		o.order = v
+	}
+	return o, nil
+}
+
+// position returns where note is in o.
+func (o noteOrder) position(note database.Note) notePosition {
+	p := notePosition{pinned: o.pinned && note.Pinned, key: note.CreatedAt, id: note.ID}
+	if o.sort == "updated_at" {
+		p.key = note.UpdatedAt
+	}
+	return p
+}
+
+// compare orders the notes at positions a and b the way the queries below
+// do.
+func (o noteOrder) compare(a, b notePosition) int {
+	c := cmp.Or(cmp.Compare(pinnedRank(a.pinned), pinnedRank(b.pinned)), cmp.Compare(a.key, b.key), cmp.Compare(a.id, b.id))
+	if o.order == "desc" {
+		return -c
+	}
+	return c
+}
+
+func pinnedRank(pinned bool) float64 {
+	if pinned {
+		return 1
+	}
+	return 0
+}
+
+// cursor returns a cursor for the page that starts after position p.
+func (o noteOrder) cursor(query string, p notePosition) searchCursor {
+	return searchCursor{Query: query, Pinned: p.pinned, Key: p.key, ID: p.id}
+}
+
+// cursorPosition returns the position a cursor from cursor marks.
+func (o noteOrder) cursorPosition(c *searchCursor) notePosition {
+	return notePosition{pinned: o.pinned && c.Pinned, key: c.Key, id: c.ID}
+}
+
+// cursorParts identifies o in the query hash of its cursors.
+func (o noteOrder) cursorParts() []string {
+	parts := []string{o.sort, o.order}
+	if o.pinned {
+		parts = append(parts, "pinned")
+	}
+	return parts
+}
+
+// sortNotes sorts notes that were queried in no particular order, e.g.
+// those with a tag, in order.
+func (o noteOrder) sortNotes(notes []database.Note) {
+	slices.SortFunc(notes, func(a, b database.Note) int {
+		return o.compare(o.position(a), o.position(b))
+	})
+}
+
+// notesPage returns limit of the user's notes in order, skipping offset.
+func (cfg *apiConfig) notesPage(ctx context.Context, userID string, o noteOrder, limit, offset int64) ([]database.Note, error) {
+	switch o {
+	case noteOrder{"created_at", "desc", false}:
+		return cfg.DB.GetNotesForUserPageCreatedDesc(ctx, database.GetNotesForUserPageCreatedDescParams{UserID: userID, Limit: limit, Offset: offset})
+	case noteOrder{"created_at", "asc", false}:
+		return cfg.DB.GetNotesForUserPageCreatedAsc(ctx, database.GetNotesForUserPageCreatedAscParams{UserID: userID, Limit: limit, Offset: offset})
+	case noteOrder{"updated_at", "desc", false}:
+		return cfg.DB.GetNotesForUserPageUpdatedDesc(ctx, database.GetNotesForUserPageUpdatedDescParams{UserID: userID, Limit: limit, Offset: offset})
+	case noteOrder{"updated_at", "asc", false}:
+		return cfg.DB.GetNotesForUserPageUpdatedAsc(ctx, database.GetNotesForUserPageUpdatedAscParams{UserID: userID, Limit: limit, Offset: offset})
+	default:
+		return cfg.DB.GetNotesForUserPage(ctx, database.GetNotesForUserPageParams{UserID: userID, Limit: limit, Offset: offset})
+	}
+}
+
+// notesAfter returns limit of the user's notes in order, starting after
+// position p.
+func (cfg *apiConfig) notesAfter(ctx context.Context, userID string, o noteOrder, p notePosition, limit int64) ([]database.Note, error) {
+	switch o {
+	case noteOrder{"created_at", "desc", false}:
+		return cfg.DB.GetNotesForUserBeforeCreated(ctx, database.GetNotesForUserBeforeCreatedParams{UserID: userID, BeforeCreatedAt: p.key, BeforeID: p.id, Limit: limit})
+	case noteOrder{"created_at", "asc", false}:
+		return cfg.DB.GetNotesForUserAfterCreated(ctx, database.GetNotesForUserAfterCreatedParams{UserID: userID, AfterCreatedAt: p.key, AfterID: p.id, Limit: limit})
+	case noteOrder{"updated_at", "desc", false}:
+		return cfg.DB.GetNotesForUserBeforeUpdated(ctx, database.GetNotesForUserBeforeUpdatedParams{UserID: userID, BeforeUpdatedAt: p.key, BeforeID: p.id, Limit: limit})
+	case noteOrder{"updated_at", "asc", false}:
+		return cfg.DB.GetNotesForUserAfterUpdated(ctx, database.GetNotesForUserAfterUpdatedParams{UserID: userID, AfterUpdatedAt: p.key, AfterID: p.id, Limit: limit})
+	default:
+		return cfg.DB.GetNotesForUserBefore(ctx, database.GetNotesForUserBeforeParams{UserID: userID, BeforePinned: p.pinned, BeforeCreatedAt: p.key, BeforeID: p.id, Limit: limit})
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/tenancy"
+)
+
+// maxPendingNoteViews is how many notes can have unflushed views before a
+// flush is started early. Past twice as many, views of further notes are
+// dropped until the database catches up.
+const maxPendingNoteViews = 10000
+
+// noteViewCounts buffers page views per note in memory, so a popular page
+// costs one UPDATE per flush instead of one per view.
+type noteViewCounts struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	pending map[string]*pendingNoteViews // Keyed by tenant and note ID.
+	dropped int64
+	full    chan struct{} // Signaled when a flush should start early.
+}
+
+type pendingNoteViews struct {
+	tenant       string // Whose database the note is in; "" for the default one.
+	NoteID       string `json:"note_id"`
+	Views        int64  `json:"views"`
+	LastViewedAt string `json:"last_viewed_at"`
+}
+
+func newNoteViewCounts(clock clock.Clock) *noteViewCounts {
+	return &noteViewCounts{
+		clock:   clock,
+		pending: make(map[string]*pendingNoteViews),
+		full:    make(chan struct{}, 1),
+	}
+}
+
+// record counts a view of noteID in tenant's database.
+func (c *noteViewCounts) record(tenant, noteID string) {
+	now := c.clock.Now().UTC().Format(time.RFC3339)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(&pendingNoteViews{tenant: tenant, NoteID: noteID, Views: 1, LastViewedAt: now})
+}
+
+// add merges v into the pending counts. c.mu must be held.
+func (c *noteViewCounts) add(v *pendingNoteViews) {
+	key := v.tenant + "/" + v.NoteID
+	if p, ok := c.pending[key]; ok {
+		p.Views += v.Views
+		if v.LastViewedAt > p.LastViewedAt {
+			p.LastViewedAt = v.LastViewedAt
+		}
+		return
+	}
+	if len(c.pending) >= 2*maxPendingNoteViews {
+		c.dropped += v.Views
+		return
+	}
+	c.pending[key] = v
+	if len(c.pending) == maxPendingNoteViews {
+		select {
+		case c.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// size returns how many notes have views pending.
+func (c *noteViewCounts) size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// take removes and returns the pending counts.
+func (c *noteViewCounts) take() (map[string]*pendingNoteViews, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending, dropped := c.pending, c.dropped
+	c.pending, c.dropped = make(map[string]*pendingNoteViews), 0
+	return pending, dropped
+}
+
+// restore puts back counts that couldn't be flushed, to be tried again.
+func (c *noteViewCounts) restore(pending []*pendingNoteViews) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, v := range pending {
+		c.add(v)
+	}
+}
+
+// runNoteViewFlushes writes buffered note views to the database every
+// interval, or sooner when many notes have views pending, until ctx is
+// done. Views recorded after that are written by the final flushNoteViews
+// on shutdown.
+func (cfg *apiConfig) runNoteViewFlushes(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-cfg.noteViews.full:
+		}
+		if err := cfg.flushNoteViews(ctx); err != nil {
+			log.Printf("Couldn't flush note views: %v", err)
+		}
+	}
+}
+
+// flushNoteViews adds all buffered note views to the database in a single
+// statement per tenant. On failure they're kept for the next flush, unless
+// their tenant no longer exists.
+func (cfg *apiConfig) flushNoteViews(ctx context.Context) error {
+	pending, dropped := cfg.noteViews.take()
+	if dropped > 0 {
+		log.Printf("Dropped %d note views while the database was behind", dropped)
+	}
+	batches := make(map[string][]*pendingNoteViews)
+	for _, v := range pending {
+		batches[v.tenant] = append(batches[v.tenant], v)
+	}
+	var errs []error
+	for tenant, batch := range batches {
+		err := cfg.addNoteViews(ctx, tenant, batch)
+		if errors.Is(err, tenancy.ErrUnknownTenant) {
+			continue
+		}
+		if err != nil {
+			cfg.noteViews.restore(batch)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (cfg *apiConfig) addNoteViews(ctx context.Context, tenant string, batch []*pendingNoteViews) error {
+	ctx, err := cfg.tenantContext(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	views, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	return cfg.DB.AddNoteViews(ctx, string(views))
+}
+
+// noteViewsByNote returns the flushed view counts of a user's notes, keyed by note ID.
+func (cfg *apiConfig) noteViewsByNote(ctx context.Context, userID string) (map[string]database.NoteView, error) {
+	rows, err := cfg.DB.GetNoteViewsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	views := make(map[string]database.NoteView, len(rows))
+	for _, row := range rows {
+		views[row.NoteID] = row
+	}
+	return views, nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/push"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/quiethours"
+	"github.com/google/uuid"
+)
+
+// Channels notifications can be delivered through: the in-app notification
+// center and push notifications to the user's registered mobile devices and
+// browsers.
+const (
+	channelInApp = "in_app"
+	channelPush  = "push"
+)
+
+var notificationChannels = []string{channelInApp, channelPush}
+
+// defaultNotificationPreferences says, per type of notification, which
+// channels deliver it until the user changes that.
+var defaultNotificationPreferences = map[string]map[string]bool{
+	events.TypeCommentCreated: {channelInApp: true},
+	events.TypeNoteExpiring:   {channelInApp: true, channelPush: true},
+	events.TypeTrialEnding:    {channelInApp: true, channelPush: true},
+	events.TypeTrialEnded:     {channelInApp: true},
+}
+
+// pushTitles is the headline of the push notification for each type.
+var pushTitles = map[string]string{
+	events.TypeCommentCreated: "New comment on your note",
+	events.TypeNoteExpiring:   "Your note expires soon",
+	events.TypeTrialEnding:    "Your trial ends soon",
+	events.TypeTrialEnded:     "Your trial has ended",
+}
+
+// urgentNotificationTypes are delivered even during quiet hours: holding
+// back a warning that a note is about to expire could make it useless.
+var urgentNotificationTypes = map[string]bool{
+	events.TypeNoteExpiring: true,
+}
+
+// notificationPreferences is the matrix of which channels deliver which type
+// of notification, keyed by type and then channel.
+type notificationPreferences map[string]map[string]bool
+
+func (notificationPreferences) dataKeyed() {}
+
+// notify delivers a notification to userID on every channel they receive
+// that type of notification through. Non-urgent notifications arriving
+// during the user's quiet hours are queued in-app until those end and not
+// pushed at all. Like publishEvent, failures are only logged.
+func (cfg *apiConfig) notify(ctx context.Context, userID, notificationType, noteID, commentID string) {
+	prefs, err := cfg.notificationPreferences(ctx, userID)
+	if err != nil {
+		log.Printf("Couldn't get notification preferences for %s: %v", userID, err)
+		return
+	}
+	inApp := prefs[notificationType][channelInApp]
+	pushed := prefs[notificationType][channelPush] && (len(cfg.Push) > 0 || cfg.WebPush != nil)
+	if !inApp && !pushed {
+		return
+	}
+
+	now := cfg.Clock.Now().UTC()
+	deliverAt := sql.NullString{}
+	if !urgentNotificationTypes[notificationType] {
+		next, err := cfg.quietHoursEnd(ctx, userID, now)
+		if err != nil {
+			log.Printf("Couldn't get quiet hours for %s: %v", userID, err)
+		} else if next.After(now) {
+			deliverAt = sql.NullString{String: next.UTC().Format(time.RFC3339), Valid: true}
+		}
+	}
+
+	id := uuid.New().String()
+	if inApp {
+		err = cfg.DB.CreateNotification(ctx, database.CreateNotificationParams{
+			ID:        id,
+			CreatedAt: now.Format(time.RFC3339),
+			UserID:    userID,
+			Type:      notificationType,
+			NoteID:    nullIfEmpty(noteID),
+			CommentID: nullIfEmpty(commentID),
+			DeliverAt: deliverAt,
+		})
+		if err != nil {
+			log.Printf("Couldn't create %s notification: %v", notificationType, err)
+		}
+	}
+	if pushed && !deliverAt.Valid && !isDryRun(ctx) {
+		// Providers can take a while to answer, so don't hold up the request
+		// that caused the notification.
+		go cfg.sendPush(context.WithoutCancel(ctx), userID, id, notificationType, noteID, commentID)
+	}
+}
+
+// sendPush pushes a notification to each of userID's registered mobile
+// devices and browsers, forgetting those whose push service reports them as
+// gone.
+func (cfg *apiConfig) sendPush(ctx context.Context, userID, notificationID, notificationType, noteID, commentID string) {
+	msg := push.Message{
+		Title: pushTitles[notificationType],
+		Data: map[string]string{
+			"notification_id": notificationID,
+			"type":            notificationType,
+		},
+	}
+	if noteID != "" {
+		msg.Data["note_id"] = noteID
+		if note, err := cfg.DB.GetNote(ctx, noteID); err == nil {
+			msg.Body = noteTitle(note.Note)
+		}
+	}
+	if commentID != "" {
+		msg.Data["comment_id"] = commentID
+	}
+
+	if cfg.WebPush != nil {
+		cfg.sendWebPush(ctx, userID, msg)
+	}
+	if len(cfg.Push) == 0 {
+		return
+	}
+	devices, err := cfg.DB.GetDeviceTokensForUser(ctx, userID)
+	if err != nil {
+		log.Printf("Couldn't get devices for %s: %v", userID, err)
+		return
+	}
+	for _, device := range devices {
+		sender := cfg.Push[device.Provider]
+		if sender == nil {
+			continue
+		}
+		err := sender.Send(ctx, device.Token, msg)
+		if errors.Is(err, push.ErrUnregistered) {
+			if err := cfg.DB.DeleteDeviceTokenByToken(ctx, device.Token); err != nil {
+				log.Printf("Couldn't remove unregistered device %s: %v", device.ID, err)
+			}
+			continue
+		}
+		if err != nil {
+			log.Printf("Couldn't push %s notification to device %s: %v", notificationType, device.ID, err)
+		}
+	}
+}
+
+// notificationPreferences returns userID's preference matrix: the defaults
+// overridden by whatever the user has changed.
+func (cfg *apiConfig) notificationPreferences(ctx context.Context, userID string) (notificationPreferences, error) {
+	rows, err := cfg.DB.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := make(notificationPreferences, len(defaultNotificationPreferences))
+	for notificationType, channels := range defaultNotificationPreferences {
+		prefs[notificationType] = make(map[string]bool, len(notificationChannels))
+		for _, channel := range notificationChannels {
+			prefs[notificationType][channel] = channels[channel]
+		}
+	}
+	for _, row := range rows {
+		if _, ok := prefs[row.EventType][row.Channel]; ok {
+			prefs[row.EventType][row.Channel] = row.Enabled
+		}
+	}
+	return prefs, nil
+}
+
+// quietHoursEnd returns when notifications created at now may be delivered
+// to userID: now itself unless the user is within their quiet hours.
+func (cfg *apiConfig) quietHoursEnd(ctx context.Context, userID string, now time.Time) (time.Time, error) {
+	quietHours, err := cfg.DB.GetNotificationQuietHours(ctx, userID)
+	if errors.Is(err, database.ErrNotFound) {
+		return now, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	schedule, err := quiethours.New(quietHours.StartTime, quietHours.EndTime, quietHours.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(now), nil
+}
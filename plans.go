@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/go-chi/chi/v5"
+)
+
+// freePlan is the plan of users who haven't been put on another.
+const freePlan = "free"
+
+// checkNoteQuota returns an UPGRADE_REQUIRED error if user already has as
+// many notes as their plan allows. Handlers call it before creating a note.
+func (cfg *apiConfig) checkNoteQuota(ctx context.Context, user database.User) error {
+	return cfg.checkNotesQuota(ctx, user, 1)
+}
+
+// checkNotesQuota is checkNoteQuota for adding n notes at once.
+func (cfg *apiConfig) checkNotesQuota(ctx context.Context, user database.User, n int64) error {
+	if !cfg.EnforcePlans {
+		return nil
+	}
+	plan, err := cfg.DB.GetPlanForUser(ctx, user.ID)
+	if err != nil {
+		return errInternal("Couldn't get plan", err)
+	}
+	if !plan.MaxNotes.Valid {
+		return nil
+	}
+	notes, err := cfg.DB.CountNotesForUser(ctx, user.ID)
+	if err != nil {
+		return errInternal("Couldn't count notes", err)
+	}
+	if notes+n > plan.MaxNotes.Int64 {
+		return errUpgradeRequired("The " + plan.Name + " plan allows " + strconv.FormatInt(plan.MaxNotes.Int64, 10) + " notes; upgrade to add more")
+	}
+	return nil
+}
+
+// checkWritable returns an UPGRADE_REQUIRED error if user's account is
+// read-only because their trial has ended. They keep their notes, and can
+// read and delete them, until they're put on a plan again.
+func (cfg *apiConfig) checkWritable(ctx context.Context, user database.User) error {
+	if !cfg.EnforcePlans {
+		return nil
+	}
+	userPlan, err := cfg.DB.GetUserPlan(ctx, user.ID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return errInternal("Couldn't get plan", err)
+	}
+	if userPlan.ReadOnlySince.Valid {
+		return errUpgradeRequired("Your trial has ended and your account is read-only; upgrade to make changes")
+	}
+	return nil
+}
+
+// requireWritable wraps the handlers of routes that create or change
+// content, which read-only accounts can't; see checkWritable. Other routes
+// stay open to them: deleting, restoring from the trash, rotating keys and
+// notification settings don't add anything to the account. MCP tools check
+// for themselves.
+func (cfg *apiConfig) requireWritable(handler authedHandler) authedHandler {
+	return func(w http.ResponseWriter, r *http.Request, user database.User) error {
+		if err := cfg.checkWritable(r.Context(), user); err != nil {
+			return err
+		}
+		return handler(w, r, user)
+	}
+}
+
+// handlerAdminPlansGet lists the plans users can be assigned.
+func (cfg *apiConfig) handlerAdminPlansGet(w http.ResponseWriter, r *http.Request) error {
+	rows, err := cfg.DB.ListPlans(r.Context())
+	if err != nil {
+		return errInternal("Couldn't get plans", err)
+	}
+	plans := make([]Plan, len(rows))
+	for i, row := range rows {
+		plans[i] = databasePlanToPlan(row)
+	}
+	respondWithJSONList(w, http.StatusOK, plans)
+	return nil
+}
+
+// handlerAdminUserPlanSet puts a user on the plan in the body, e.g. after
+// they've paid for an upgrade, and responds with it. With trial_days, the
+// user is only on the plan for that many days; see runTrialExpiry. Either
+// way, an account made read-only by an ended trial can be changed again.
+func (cfg *apiConfig) handlerAdminUserPlanSet(w http.ResponseWriter, r *http.Request) error {
+	type parameters struct {
+		Plan      string `json:"plan"`
+		TrialDays int    `json:"trial_days"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		return errValidation("Couldn't decode parameters", err)
+	}
+	if params.TrialDays < 0 {
+		return errValidation("trial_days must not be negative", nil)
+	}
+	if params.TrialDays > 0 && params.Plan == freePlan {
+		return errValidation("Trials are of plans other than free", nil)
+	}
+
+	user, err := cfg.DB.GetUserByID(r.Context(), chi.URLParam(r, "userID"))
+	if err != nil {
+		return errInternal("Couldn't get user", err)
+	}
+	plan, err := cfg.DB.GetPlan(r.Context(), params.Plan)
+	if errors.Is(err, database.ErrNotFound) {
+		return errValidation("Unknown plan "+params.Plan, nil)
+	}
+	if err != nil {
+		return errInternal("Couldn't get plan", err)
+	}
+
+	now := cfg.Clock.Now().UTC()
+	if params.TrialDays > 0 {
+		err = cfg.DB.StartTrial(r.Context(), database.StartTrialParams{
+			UserID:      user.ID,
+			PlanID:      plan.ID,
+			UpdatedAt:   now.Format(time.RFC3339),
+			TrialEndsAt: sql.NullString{String: now.AddDate(0, 0, params.TrialDays).Format(time.RFC3339), Valid: true},
+		})
+	} else {
+		err = cfg.DB.SetUserPlan(r.Context(), database.SetUserPlanParams{
+			UserID:    user.ID,
+			PlanID:    plan.ID,
+			UpdatedAt: now.Format(time.RFC3339),
+		})
+	}
+	if err != nil {
+		return errInternal("Couldn't set plan", err)
+	}
+	respondWithJSON(w, http.StatusOK, databasePlanToPlan(plan))
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+)
+
+func TestReadOnlyAccount(t *testing.T) {
+	api := newTestAPI(t)
+	api.cfg.EnforcePlans = true
+	user, key := api.newUser(t)
+	note := api.newNote(t, key, "kept")
+	if code := api.do(t, http.MethodDelete, "/v1/notes/"+note.ID, key, nil, nil); code != http.StatusNoContent {
+		t.Fatalf("trashing note: status %d", code)
+	}
+
+	// End a trial, as runTrialExpiry does.
+	ctx := context.Background()
+	now := api.clock.Now().UTC().Format(time.RFC3339)
+	err := api.cfg.DB.StartTrial(ctx, database.StartTrialParams{
+		UserID:      user.ID,
+		PlanID:      freePlan,
+		UpdatedAt:   now,
+		TrialEndsAt: sql.NullString{String: now, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = api.cfg.DB.ExpireTrial(ctx, database.ExpireTrialParams{
+		ReadOnlySince: sql.NullString{String: now, Valid: true},
+		UpdatedAt:     now,
+		UserID:        user.ID,
+		TrialEndsAt:   sql.NullString{String: now, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code := api.do(t, http.MethodPost, "/v1/notes", key, map[string]string{"note": "new"}, nil); code != http.StatusPaymentRequired {
+		t.Errorf("creating a note: status %d, want %d", code, http.StatusPaymentRequired)
+	}
+	if code := api.do(t, http.MethodPost, "/v1/notes/"+note.ID+"/restore", key, nil, nil); code != http.StatusOK {
+		t.Errorf("restoring a note: status %d, want %d", code, http.StatusOK)
+	}
+	var keys []APIKey
+	api.do(t, http.MethodGet, "/v1/keys", key, nil, &keys)
+	if len(keys) == 0 {
+		t.Fatal("user has no keys")
+	}
+	if code := api.do(t, http.MethodPost, "/v1/keys/"+keys[0].ID+"/rotate", key, nil, nil); code != http.StatusCreated {
+		t.Errorf("rotating a key: status %d, want %d", code, http.StatusCreated)
+	}
+}
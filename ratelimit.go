@@ -0,0 +1,43 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+)
+
+// userRateLimiter keeps a token bucket per user, for endpoints that are
+// expensive enough to need a per-user budget. Buckets live in memory, so
+// limits apply per instance and reset on restart.
+type userRateLimiter struct {
+	mu       sync.Mutex
+	clock    clock.Clock
+	limiters map[string]*rate.Limiter
+	limit    rate.Limit
+	burst    int
+}
+
+// newUserRateLimiter allows each user n calls per period, all of which may
+// be spent at once.
+func newUserRateLimiter(n int, period time.Duration, clock clock.Clock) *userRateLimiter {
+	return &userRateLimiter{
+		clock:    clock,
+		limiters: make(map[string]*rate.Limiter),
+		limit:    rate.Limit(float64(n) / period.Seconds()),
+		burst:    n,
+	}
+}
+
+func (l *userRateLimiter) Allow(userID string) bool {
+	l.mu.Lock()
+	limiter, ok := l.limiters[userID]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[userID] = limiter
+	}
+	l.mu.Unlock()
+	return limiter.AllowN(l.clock.Now(), 1)
+}
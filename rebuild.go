@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"golang.org/x/time/rate"
+)
+
+// Kinds of derived data an index rebuild can recompute from the notes table.
+const (
+	derivedTitles     = "titles"
+	derivedSearch     = "search"
+	derivedLinks      = "links"
+	derivedTrigrams   = "trigrams"
+	derivedEmbeddings = "embeddings"
+)
+
+const (
+	// rebuildBatchSize is how many notes are loaded per query while
+	// rebuilding, and how often progress is saved.
+	rebuildBatchSize = 100
+	// defaultRebuildRateLimit is how many notes per second a rebuild
+	// processes, so it doesn't starve requests or exhaust an embedding
+	// provider's quota.
+	defaultRebuildRateLimit = 50
+)
+
+// availableDerived lists the derived data this instance maintains, in the
+// order a rebuild recomputes it: titles come first since the search index
+// and trigrams are made from them.
+func (cfg *apiConfig) availableDerived() []string {
+	derived := []string{derivedTitles, derivedSearch, derivedLinks}
+	if cfg.FuzzyTitleSearch {
+		derived = append(derived, derivedTrigrams)
+	}
+	if cfg.Embedder != nil {
+		derived = append(derived, derivedEmbeddings)
+	}
+	return derived
+}
+
+// startIndexRebuild runs rebuild in the background, recording it first
+// unless it's being resumed. It fails if a rebuild is already running on
+// this instance.
+func (cfg *apiConfig) startIndexRebuild(ctx context.Context, rebuild database.IndexRebuild) error {
+	if !cfg.rebuilding.CompareAndSwap(false, true) {
+		return errConflict("A rebuild is already running", nil)
+	}
+	if rebuild.StartedAt == "" {
+		rebuild.StartedAt = cfg.Clock.Now().UTC().Format(time.RFC3339)
+		err := cfg.DB.CreateIndexRebuild(ctx, database.CreateIndexRebuildParams{
+			ID:        rebuild.ID,
+			Derived:   rebuild.Derived,
+			StartedAt: rebuild.StartedAt,
+			UpdatedAt: rebuild.StartedAt,
+		})
+		if err != nil {
+			cfg.rebuilding.Store(false)
+			return errInternal("Couldn't record rebuild", err)
+		}
+	}
+	go func() {
+		defer cfg.rebuilding.Store(false)
+		if err := cfg.runIndexRebuild(context.WithoutCancel(ctx), rebuild); err != nil {
+			log.Printf("Rebuild %s stopped: %v", rebuild.ID, err)
+		}
+	}()
+	return nil
+}
+
+// resumeIndexRebuild continues a rebuild interrupted by a restart, if any.
+func (cfg *apiConfig) resumeIndexRebuild(ctx context.Context) {
+	rebuild, err := cfg.DB.GetUnfinishedIndexRebuild(ctx)
+	if errors.Is(err, database.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Couldn't get unfinished rebuild: %v", err)
+		return
+	}
+	log.Printf("Resuming rebuild %s after note %q", rebuild.ID, rebuild.LastNoteID)
+	if err := cfg.startIndexRebuild(ctx, rebuild); err != nil {
+		log.Printf("Couldn't resume rebuild %s: %v", rebuild.ID, err)
+	}
+}
+
+// runIndexRebuild recomputes rebuild's derived data for every note after
+// its last_note_id in ID order, saving its place after each batch.
+func (cfg *apiConfig) runIndexRebuild(ctx context.Context, rebuild database.IndexRebuild) error {
+	derived := strings.Split(rebuild.Derived, ",")
+	limiter := rate.NewLimiter(rate.Limit(cfg.rebuildRateLimit), 1)
+	for {
+		notes, err := cfg.DB.GetNotesAfterID(ctx, database.GetNotesAfterIDParams{
+			ID:    rebuild.LastNoteID,
+			Limit: rebuildBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("getting notes: %w", err)
+		}
+		for _, note := range notes {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+			if err := cfg.rebuildNote(ctx, note, derived); err != nil {
+				return fmt.Errorf("rebuilding note %s: %w", note.ID, err)
+			}
+		}
+		if len(notes) == 0 {
+			break
+		}
+
+		rebuild.LastNoteID = notes[len(notes)-1].ID
+		rebuild.NotesDone += int64(len(notes))
+		err = cfg.DB.UpdateIndexRebuildProgress(ctx, database.UpdateIndexRebuildProgressParams{
+			LastNoteID: rebuild.LastNoteID,
+			NotesDone:  rebuild.NotesDone,
+			UpdatedAt:  cfg.Clock.Now().UTC().Format(time.RFC3339),
+			ID:         rebuild.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("saving progress: %w", err)
+		}
+	}
+
+	now := cfg.Clock.Now().UTC().Format(time.RFC3339)
+	err := cfg.DB.FinishIndexRebuild(ctx, database.FinishIndexRebuildParams{
+		FinishedAt: sql.NullString{String: now, Valid: true},
+		UpdatedAt:  now,
+		ID:         rebuild.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("finishing: %w", err)
+	}
+	log.Printf("Rebuilt %s for %d notes", rebuild.Derived, rebuild.NotesDone)
+	return nil
+}
+
+// rebuildNote recomputes the given kinds of derived data for note. Titles,
+// the search index and deletes of stale rows must succeed; the rest reuses the write path's
+// helpers, which only log failures of the external services they call.
+func (cfg *apiConfig) rebuildNote(ctx context.Context, note database.Note, derived []string) error {
+	for _, d := range derived {
+		switch d {
+		case derivedTitles:
+			title := noteTitle(note.Note)
+			if title == note.Title {
+				continue
+			}
+			err := cfg.DB.UpdateNoteTitle(ctx, database.UpdateNoteTitleParams{
+				Title: title,
+				ID:    note.ID,
+			})
+			if err != nil {
+				return err
+			}
+			cfg.notesChanged(ctx, note.UserID)
+			note.Title = title
+		case derivedSearch:
+			if err := cfg.DB.ReindexNoteForSearch(ctx, note.ID); err != nil {
+				return err
+			}
+		case derivedLinks:
+			if err := cfg.DB.DeleteNoteLinks(ctx, note.ID); err != nil {
+				return err
+			}
+			cfg.recordLinks(ctx, note)
+		case derivedTrigrams:
+			if err := cfg.DB.DeleteNoteTitleTrigrams(ctx, note.ID); err != nil {
+				return err
+			}
+			cfg.indexNoteTitle(ctx, note.ID, note.UserID, note.Title)
+		case derivedEmbeddings:
+			cfg.embedNote(ctx, note)
+		}
+	}
+	return nil
+}
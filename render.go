@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// Kinds of blocks a note body is split into for rendering.
+const (
+	blockParagraph = "paragraph"
+	blockCode      = "code"
+	blockMermaid   = "mermaid"
+)
+
+// codeFormatter renders highlighted code with inline styles so pages need no extra stylesheet.
+var codeFormatter = chromahtml.New(chromahtml.TabWidth(4))
+
+var codeStyle = styles.Get("github")
+
+// renderBlock is one piece of a rendered note body. Text is escaped by the
+// template; HTML is only set for code highlighted by chroma, which escapes
+// every token it emits.
+type renderBlock struct {
+	Kind string
+	Text string
+	HTML template.HTML
+}
+
+// renderNoteBlocks splits a note body into paragraphs and fenced code blocks.
+// Fences tagged "mermaid" are passed through for client-side rendering, other
+// fences are syntax highlighted using their language tag.
+func renderNoteBlocks(body string) []renderBlock {
+	var blocks []renderBlock
+	var text []string
+	flushText := func() {
+		for _, p := range noteParagraphs(strings.Join(text, "\n")) {
+			blocks = append(blocks, renderBlock{Kind: blockParagraph, Text: p})
+		}
+		text = nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		fence, lang, ok := openingFence(lines[i])
+		if !ok {
+			text = append(text, lines[i])
+			continue
+		}
+		flushText()
+
+		var code []string
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != fence; i++ {
+			code = append(code, lines[i])
+		}
+		source := strings.Join(code, "\n")
+		if lang == "mermaid" {
+			blocks = append(blocks, renderBlock{Kind: blockMermaid, Text: source})
+			continue
+		}
+		blocks = append(blocks, renderBlock{Kind: blockCode, Text: source, HTML: highlightCode(source, lang)})
+	}
+	flushText()
+
+	return blocks
+}
+
+// openingFence reports whether line opens a fenced code block, returning the
+// fence marker that closes it and the lowercased language tag.
+func openingFence(line string) (fence, lang string, ok bool) {
+	trimmed := strings.TrimSpace(line)
+	for _, marker := range []string{"```", "~~~"} {
+		if !strings.HasPrefix(trimmed, marker) {
+			continue
+		}
+		fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, marker[:1]))]
+		info := strings.Fields(strings.TrimPrefix(trimmed, fence))
+		if len(info) > 0 {
+			lang = strings.ToLower(info[0])
+		}
+		return fence, lang, true
+	}
+	return "", "", false
+}
+
+// highlightCode renders source as highlighted HTML, falling back to an
+// escaped plain block when the language is unknown or highlighting fails.
+func highlightCode(source, lang string) template.HTML {
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	var buf bytes.Buffer
+	iterator, err := lexer.Tokenise(nil, source)
+	if err == nil {
+		err = codeFormatter.Format(&buf, codeStyle, iterator)
+	}
+	if err != nil {
+		return template.HTML("<pre><code>" + template.HTMLEscapeString(source) + "</code></pre>") // #nosec G203 -- source is escaped.
+	}
+	return template.HTML(buf.String()) // #nosec G203 -- chroma escapes all token values.
+}
+
+func hasBlockKind(blocks []renderBlock, kind string) bool {
+	for _, b := range blocks {
+		if b.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+-- name: GetAdminStats :one
+SELECT
+    (SELECT COUNT(*) FROM users) AS users,
+    (SELECT COUNT(*) FROM notes) AS notes,
+    (SELECT COUNT(*) FROM notes WHERE published_at IS NOT NULL) AS published_notes,
+    (SELECT COUNT(*) FROM note_comments) AS comments;
+--
@@ -0,0 +1,23 @@
+-- name: CreateAPIKey :exec
+INSERT INTO api_keys (id, created_at, user_id, api_key)
+VALUES (?, ?, ?, ?);
+--
+
+-- name: GetAPIKeyForUser :one
+SELECT * FROM api_keys WHERE id = ? AND user_id = ?;
+--
+
+-- name: GetAPIKeysForUser :many
+SELECT * FROM api_keys WHERE user_id = ? ORDER BY created_at;
+--
+
+-- name: SupersedeAPIKey :execrows
+UPDATE api_keys SET superseded_by = ?, expires_at = ?
+WHERE id = ? AND superseded_by IS NULL;
+--
+
+-- name: RevokeAPIKeysForUser :execrows
+UPDATE api_keys SET expires_at = sqlc.arg(now)
+WHERE user_id = sqlc.arg(user_id)
+AND (expires_at IS NULL OR expires_at > sqlc.arg(now));
+--
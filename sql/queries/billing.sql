@@ -0,0 +1,19 @@
+-- name: GetBillingCustomer :one
+SELECT * FROM billing_customers WHERE user_id = ?;
+--
+
+-- name: GetBillingCustomerByStripeID :one
+SELECT * FROM billing_customers WHERE stripe_customer_id = ?;
+--
+
+-- name: SetStripeCustomer :exec
+INSERT INTO billing_customers (user_id, stripe_customer_id, updated_at)
+VALUES (?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET stripe_customer_id = excluded.stripe_customer_id, updated_at = excluded.updated_at;
+--
+
+-- name: SetSubscriptionStatus :exec
+UPDATE billing_customers SET subscription_status = ?, last_event_at = ?, updated_at = ?
+WHERE user_id = ?;
+--
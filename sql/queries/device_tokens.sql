@@ -0,0 +1,22 @@
+-- name: UpsertDeviceToken :exec
+INSERT INTO device_tokens (id, created_at, user_id, provider, token)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(token) DO UPDATE SET user_id = excluded.user_id, provider = excluded.provider;
+--
+
+-- name: GetDeviceTokenByToken :one
+SELECT * FROM device_tokens WHERE token = ?;
+--
+
+-- name: GetDeviceTokensForUser :many
+SELECT * FROM device_tokens WHERE user_id = ?
+ORDER BY created_at, id;
+--
+
+-- name: DeleteDeviceToken :execrows
+DELETE FROM device_tokens WHERE id = ? AND user_id = ?;
+--
+
+-- name: DeleteDeviceTokenByToken :exec
+DELETE FROM device_tokens WHERE token = ?;
+--
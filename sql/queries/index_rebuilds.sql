@@ -0,0 +1,20 @@
+-- name: CreateIndexRebuild :exec
+INSERT INTO index_rebuilds (id, derived, started_at, updated_at)
+VALUES (?, ?, ?, ?);
+--
+
+-- name: GetLatestIndexRebuild :one
+SELECT * FROM index_rebuilds ORDER BY started_at DESC, id DESC LIMIT 1;
+--
+
+-- name: GetUnfinishedIndexRebuild :one
+SELECT * FROM index_rebuilds WHERE finished_at IS NULL ORDER BY started_at LIMIT 1;
+--
+
+-- name: UpdateIndexRebuildProgress :exec
+UPDATE index_rebuilds SET last_note_id = ?, notes_done = ?, updated_at = ? WHERE id = ?;
+--
+
+-- name: FinishIndexRebuild :exec
+UPDATE index_rebuilds SET finished_at = ?, updated_at = ? WHERE id = ?;
+--
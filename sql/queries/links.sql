@@ -0,0 +1,58 @@
+-- name: CreateNoteLink :exec
+INSERT INTO note_links (note_id, url, position)
+VALUES (?, ?, ?)
+ON CONFLICT (note_id, url) DO NOTHING;
+--
+
+-- name: GetLinkPreview :one
+SELECT * FROM link_previews WHERE url = ?;
+--
+
+-- name: UpsertLinkPreview :exec
+INSERT INTO link_previews (url, title, description, image_url, site_name, fetched_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (url) DO UPDATE
+SET title = excluded.title, description = excluded.description, image_url = excluded.image_url,
+    site_name = excluded.site_name, fetched_at = excluded.fetched_at;
+--
+
+-- name: GetLinkPreviewsForNote :many
+SELECT link_previews.* FROM note_links
+JOIN link_previews ON link_previews.url = note_links.url
+WHERE note_links.note_id = ?
+ORDER BY note_links.position;
+--
+
+-- name: GetLinkPreviewsForUser :many
+SELECT note_links.note_id, sqlc.embed(link_previews) FROM note_links
+JOIN notes ON notes.id = note_links.note_id
+JOIN link_previews ON link_previews.url = note_links.url
+WHERE notes.user_id = ?
+ORDER BY note_links.note_id, note_links.position;
+--
+
+-- name: GetLinksToCheck :many
+SELECT DISTINCT note_links.url FROM note_links
+LEFT JOIN link_statuses ON link_statuses.url = note_links.url
+WHERE link_statuses.checked_at IS NULL OR link_statuses.checked_at < ?
+LIMIT ?;
+--
+
+-- name: UpsertLinkStatus :exec
+INSERT INTO link_statuses (url, alive, status_code, error, checked_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (url) DO UPDATE
+SET alive = excluded.alive, status_code = excluded.status_code, error = excluded.error, checked_at = excluded.checked_at;
+--
+
+-- name: GetLinksForNote :many
+SELECT note_links.url, link_statuses.alive, link_statuses.status_code, link_statuses.error, link_statuses.checked_at
+FROM note_links
+LEFT JOIN link_statuses ON link_statuses.url = note_links.url
+WHERE note_links.note_id = ?
+ORDER BY note_links.position;
+--
+
+-- name: DeleteNoteLinks :exec
+DELETE FROM note_links WHERE note_id = ?;
+--
@@ -0,0 +1,18 @@
+-- name: CreateNoteComment :exec
+INSERT INTO note_comments (id, created_at, note_id, user_id, body)
+VALUES (?, ?, ?, ?, ?);
+--
+
+-- name: GetNoteComment :one
+SELECT * FROM note_comments WHERE id = ?;
+--
+
+-- name: GetNoteComments :many
+SELECT * FROM note_comments WHERE note_id = ?
+ORDER BY created_at, id
+LIMIT ? OFFSET ?;
+--
+
+-- name: DeleteNoteComment :execrows
+DELETE FROM note_comments WHERE id = ? AND note_id = ? AND user_id = ?;
+--
@@ -0,0 +1,12 @@
+-- name: UpsertNoteEmbedding :exec
+INSERT INTO note_embeddings (note_id, model, embedding, created_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (note_id) DO UPDATE
+SET model = excluded.model, embedding = excluded.embedding, created_at = excluded.created_at;
+--
+
+-- name: GetNoteEmbeddingsForUser :many
+SELECT sqlc.embed(notes), note_embeddings.embedding FROM notes
+JOIN note_embeddings ON note_embeddings.note_id = notes.id
+WHERE notes.user_id = ? AND note_embeddings.model = ? AND notes.deleted_at IS NULL;
+--
@@ -0,0 +1,10 @@
+-- name: GetPublishedNoteListForUser :many
+SELECT * FROM note_list_entries WHERE user_id = ? AND published_at IS NOT NULL
+ORDER BY published_at DESC;
+--
+
+-- name: GetPublishedNoteListForUserPage :many
+SELECT * FROM note_list_entries WHERE user_id = ? AND published_at IS NOT NULL
+ORDER BY published_at DESC
+LIMIT ? OFFSET ?;
+--
@@ -0,0 +1,10 @@
+-- name: GetNoteSummary :one
+SELECT * FROM note_summaries WHERE note_id = ?;
+--
+
+-- name: UpsertNoteSummary :exec
+INSERT INTO note_summaries (note_id, content_hash, model, summary, created_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (note_id) DO UPDATE
+SET content_hash = excluded.content_hash, model = excluded.model, summary = excluded.summary, created_at = excluded.created_at;
+--
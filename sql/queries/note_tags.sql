@@ -0,0 +1,31 @@
+-- name: AddNoteTag :exec
+INSERT INTO note_tags (note_id, user_id, tag)
+VALUES (?, ?, ?)
+ON CONFLICT DO NOTHING;
+--
+
+-- name: DeleteNoteTags :exec
+DELETE FROM note_tags WHERE note_id = ?;
+--
+
+-- name: GetNoteTags :many
+SELECT tag FROM note_tags WHERE note_id = ? ORDER BY tag;
+--
+
+-- name: GetNoteTagsForUser :many
+SELECT note_id, tag FROM note_tags WHERE user_id = ? ORDER BY tag;
+--
+
+-- name: GetTagsForUser :many
+SELECT note_tags.tag, COUNT(*) AS notes FROM note_tags
+JOIN notes ON notes.id = note_tags.note_id
+WHERE note_tags.user_id = ? AND notes.deleted_at IS NULL
+GROUP BY note_tags.tag
+ORDER BY note_tags.tag;
+--
+
+-- name: GetNotesForUserWithTag :many
+SELECT notes.* FROM notes
+JOIN note_tags ON note_tags.note_id = notes.id
+WHERE note_tags.user_id = ? AND note_tags.tag = ? AND notes.deleted_at IS NULL;
+--
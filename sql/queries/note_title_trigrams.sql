@@ -0,0 +1,31 @@
+-- name: InsertNoteTitleTrigram :exec
+INSERT INTO note_title_trigrams (note_id, user_id, trigram)
+VALUES (?, ?, ?)
+ON CONFLICT DO NOTHING;
+--
+
+-- name: DeleteAllNoteTitleTrigrams :exec
+DELETE FROM note_title_trigrams;
+--
+
+-- name: GetNotesWithoutTitleTrigrams :many
+SELECT id, user_id, title FROM notes
+WHERE id > ? AND NOT EXISTS (
+    SELECT 1 FROM note_title_trigrams WHERE note_title_trigrams.note_id = notes.id
+)
+ORDER BY id
+LIMIT ?;
+--
+
+-- name: GetFuzzyNoteTitleMatches :many
+SELECT note_list_entries.note_id AS id, note_list_entries.title, COUNT(*) AS shared FROM note_title_trigrams
+JOIN note_list_entries ON note_list_entries.note_id = note_title_trigrams.note_id
+WHERE note_title_trigrams.user_id = ? AND note_title_trigrams.trigram IN (sqlc.slice(trigrams))
+GROUP BY note_list_entries.note_id
+ORDER BY shared DESC, note_list_entries.note_id
+LIMIT ?;
+--
+
+-- name: DeleteNoteTitleTrigrams :exec
+DELETE FROM note_title_trigrams WHERE note_id = ?;
+--
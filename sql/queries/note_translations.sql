@@ -0,0 +1,10 @@
+-- name: GetNoteTranslation :one
+SELECT * FROM note_translations WHERE note_id = ? AND language = ?;
+--
+
+-- name: UpsertNoteTranslation :exec
+INSERT INTO note_translations (note_id, language, content_hash, provider, translation, created_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (note_id, language) DO UPDATE
+SET content_hash = excluded.content_hash, provider = excluded.provider, translation = excluded.translation, created_at = excluded.created_at;
+--
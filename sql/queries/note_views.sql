@@ -0,0 +1,22 @@
+-- name: AddNoteViews :exec
+-- Adds a batch of view counts in one statement. views is a JSON array of
+-- {"note_id", "views", "last_viewed_at"} objects; notes deleted since are
+-- skipped.
+INSERT INTO note_views (note_id, views, last_viewed_at)
+SELECT json_extract(value, '$.note_id'), json_extract(value, '$.views'), json_extract(value, '$.last_viewed_at')
+FROM json_each(sqlc.arg(views))
+WHERE json_extract(value, '$.note_id') IN (SELECT id FROM notes)
+ON CONFLICT (note_id) DO UPDATE SET
+    views = note_views.views + excluded.views,
+    last_viewed_at = max(note_views.last_viewed_at, excluded.last_viewed_at);
+--
+
+-- name: GetNoteView :one
+SELECT * FROM note_views WHERE note_id = ?;
+--
+
+-- name: GetNoteViewsForUser :many
+SELECT note_views.* FROM note_views
+JOIN notes ON notes.id = note_views.note_id
+WHERE notes.user_id = ?;
+--
@@ -0,0 +1,52 @@
+-- name: CreateNotebook :one
+INSERT INTO notebooks (id, created_at, updated_at, user_id, parent_id, name)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+--
+
+-- name: GetNotebook :one
+SELECT * FROM notebooks WHERE id = ? AND user_id = ?;
+--
+
+-- name: GetNotebooksForUser :many
+SELECT notebooks.*, (SELECT COUNT(*) FROM notes WHERE notes.notebook_id = notebooks.id AND notes.deleted_at IS NULL) AS notes
+FROM notebooks WHERE user_id = ?
+ORDER BY name, id;
+--
+
+-- name: CountNotesInNotebook :one
+SELECT COUNT(*) FROM notes WHERE notebook_id = ? AND deleted_at IS NULL;
+--
+
+-- name: UpdateNotebook :one
+UPDATE notebooks SET name = ?, parent_id = ?, updated_at = ?
+WHERE id = ? AND user_id = ?
+RETURNING *;
+--
+
+-- name: GetNotebookTree :many
+WITH RECURSIVE tree(id) AS (
+    SELECT CAST(sqlc.arg(id) AS TEXT)
+    UNION
+    SELECT notebooks.id FROM notebooks JOIN tree ON notebooks.parent_id = tree.id
+)
+SELECT id FROM tree;
+--
+
+-- name: UnfileNotesInNotebookTree :exec
+WITH RECURSIVE tree(id) AS (
+    SELECT CAST(sqlc.arg(id) AS TEXT)
+    UNION
+    SELECT notebooks.id FROM notebooks JOIN tree ON notebooks.parent_id = tree.id
+)
+UPDATE notes SET notebook_id = NULL WHERE notebook_id IN (SELECT id FROM tree);
+--
+
+-- name: DeleteNotebookTree :exec
+WITH RECURSIVE tree(id) AS (
+    SELECT CAST(sqlc.arg(id) AS TEXT)
+    UNION
+    SELECT notebooks.id FROM notebooks JOIN tree ON notebooks.parent_id = tree.id
+)
+DELETE FROM notebooks WHERE id IN (SELECT id FROM tree);
+--
@@ -1,6 +1,6 @@
 -- name: CreateNote :exec
-INSERT INTO notes (id, created_at, updated_at, note, user_id)
-VALUES (?, ?, ?, ?, ?);
+INSERT INTO notes (id, created_at, updated_at, note, user_id, source_url, source_title, expires_at, title, notebook_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 --
 
 -- name: GetNote :one
@@ -8,5 +8,212 @@ SELECT * FROM notes WHERE id = ?;
 --
 
 -- name: GetNotesForUser :many
-SELECT * FROM notes WHERE user_id = ?;
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL;
+--
+
+-- name: GetNotesForUserPage :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY pinned DESC, created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+--
+
+-- name: CountNotesForUser :one
+SELECT COUNT(*) FROM notes WHERE user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: GetNotesForUserBefore :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (pinned, created_at, id) < (sqlc.arg(before_pinned), sqlc.arg(before_created_at), sqlc.arg(before_id))
+ORDER BY pinned DESC, created_at DESC, id DESC
+LIMIT ?;
+--
+
+-- name: GetNotesForUserPageCreatedDesc :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+--
+
+-- name: GetNotesForUserBeforeCreated :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (created_at, id) < (sqlc.arg(before_created_at), sqlc.arg(before_id))
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+--
+
+-- name: GetNotesForUserPageCreatedAsc :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY created_at, id
+LIMIT ? OFFSET ?;
+--
+
+-- name: GetNotesForUserAfterCreated :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (created_at, id) > (sqlc.arg(after_created_at), sqlc.arg(after_id))
+ORDER BY created_at, id
+LIMIT ?;
+--
+
+-- name: GetNotesForUserPageUpdatedDesc :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY updated_at DESC, id DESC
+LIMIT ? OFFSET ?;
+--
+
+-- name: GetNotesForUserBeforeUpdated :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (updated_at, id) < (sqlc.arg(before_updated_at), sqlc.arg(before_id))
+ORDER BY updated_at DESC, id DESC
+LIMIT ?;
+--
+
+-- name: GetNotesForUserPageUpdatedAsc :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+ORDER BY updated_at, id
+LIMIT ? OFFSET ?;
+--
+
+-- name: GetNotesForUserAfterUpdated :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL
+AND (updated_at, id) > (sqlc.arg(after_updated_at), sqlc.arg(after_id))
+ORDER BY updated_at, id
+LIMIT ?;
+--
+
+-- name: PublishNote :execrows
+UPDATE notes SET published_at = COALESCE(published_at, ?)
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: UnpublishNote :exec
+UPDATE notes SET published_at = NULL WHERE id = ? AND user_id = ?;
+--
+
+-- name: GetPublishedNoteIDsForUser :many
+SELECT id FROM notes WHERE user_id = ? AND published_at IS NOT NULL;
+--
+
+-- name: GetPublishedNotesForUser :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND published_at IS NOT NULL
+ORDER BY published_at DESC;
+--
+
+-- name: GetPublishedNote :one
+SELECT * FROM notes WHERE id = ? AND user_id = ? AND published_at IS NOT NULL AND deleted_at IS NULL;
+--
+
+-- name: GetNoteByID :one
+SELECT * FROM notes WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: SetNoteExpiration :execrows
+UPDATE notes SET expires_at = ?, expiry_warned_at = NULL, updated_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: SetNoteNoindex :execrows
+UPDATE notes SET noindex = ?, updated_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: GetNotesExpiringBefore :many
+SELECT * FROM notes
+WHERE expires_at IS NOT NULL AND expires_at <= ? AND expiry_warned_at IS NULL AND deleted_at IS NULL;
+--
+
+-- name: MarkNoteExpiryWarned :exec
+UPDATE notes SET expiry_warned_at = ? WHERE id = ?;
+--
+
+-- name: DeleteExpiredNotes :many
+DELETE FROM notes WHERE expires_at IS NOT NULL AND expires_at <= ?
+RETURNING id, user_id;
+--
+
+-- name: QuickRecentNotes :many
+SELECT id, substr(note, 1, 500) AS head, updated_at FROM notes
+WHERE user_id = ? AND deleted_at IS NULL
+ORDER BY updated_at DESC
+LIMIT ?;
+--
+
+-- name: QuickSearchNotes :many
+SELECT notes.id, substr(notes.note, 1, 500) AS head, notes.updated_at FROM notes_fts
+JOIN notes ON notes.rowid = notes_fts.rowid AND notes.id = notes_fts.note_id
+WHERE notes_fts MATCH sqlc.arg(query) AND notes.user_id = sqlc.arg(user_id) AND notes.deleted_at IS NULL
+ORDER BY notes.updated_at DESC
+LIMIT ?;
+--
+
+-- name: SuggestNoteTitles :many
+SELECT note_id AS id, title FROM note_list_entries
+WHERE user_id = ? AND title LIKE ? ESCAPE '\'
+AND (title, note_id) > (sqlc.arg(after_title), sqlc.arg(after_id))
+ORDER BY title, note_id
+LIMIT ?;
+--
+
+-- name: GetNotesAfterID :many
+SELECT * FROM notes WHERE id > ? ORDER BY id LIMIT ?;
+--
+
+-- name: UpdateNoteTitle :exec
+UPDATE notes SET title = ? WHERE id = ?;
+--
+
+-- name: TouchNote :execrows
+UPDATE notes SET updated_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: SetNoteNotebook :execrows
+UPDATE notes SET notebook_id = ?, updated_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: GetNotesForUserInNotebook :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND notebook_id = ?;
+--
+
+-- name: TrashNote :execrows
+UPDATE notes SET deleted_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: RestoreNote :execrows
+UPDATE notes SET deleted_at = NULL
+WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL;
+--
+
+-- name: GetTrashedNotesForUser :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC;
+--
+
+-- name: DeleteTrashedNote :execrows
+DELETE FROM notes WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL;
+--
+
+-- name: CountUnarchivedNotesForUser :one
+SELECT COUNT(*) FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL;
+--
+
+-- name: ArchiveNote :execrows
+UPDATE notes SET archived_at = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: UnarchiveNote :execrows
+UPDATE notes SET archived_at = NULL
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+--
+
+-- name: GetArchivedNotesForUser :many
+SELECT * FROM notes WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NOT NULL
+ORDER BY archived_at DESC, id DESC;
+--
+
+-- name: SetNotePinned :execrows
+UPDATE notes SET pinned = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
 --
@@ -0,0 +1,13 @@
+-- name: SearchNotes :many
+SELECT sqlc.embed(notes), notes_fts.rank FROM notes_fts
+JOIN notes ON notes.rowid = notes_fts.rowid AND notes.id = notes_fts.note_id
+WHERE notes_fts MATCH sqlc.arg(query) AND notes.user_id = sqlc.arg(user_id) AND notes.deleted_at IS NULL
+AND (notes_fts.rank, notes.id) > (sqlc.arg(after_rank), sqlc.arg(after_id))
+ORDER BY notes_fts.rank, notes.id
+LIMIT ?;
+--
+
+-- name: ReindexNoteForSearch :exec
+INSERT OR REPLACE INTO notes_fts (rowid, note_id, title, note)
+SELECT rowid, id, title, note FROM notes WHERE id = ?;
+--
@@ -0,0 +1,61 @@
+-- name: CreateNotification :exec
+INSERT INTO notifications (id, created_at, user_id, type, note_id, comment_id, deliver_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+--
+
+-- name: GetNotificationsForUser :many
+SELECT * FROM notifications WHERE user_id = ?
+AND (deliver_at IS NULL OR deliver_at <= sqlc.arg(now))
+ORDER BY COALESCE(deliver_at, created_at) DESC, id DESC
+LIMIT ? OFFSET ?;
+--
+
+-- name: GetUnreadNotificationsForUser :many
+SELECT * FROM notifications WHERE user_id = ? AND read_at IS NULL
+AND (deliver_at IS NULL OR deliver_at <= sqlc.arg(now))
+ORDER BY COALESCE(deliver_at, created_at) DESC, id DESC
+LIMIT ? OFFSET ?;
+--
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL
+AND (deliver_at IS NULL OR deliver_at <= sqlc.arg(now));
+--
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications SET read_at = COALESCE(read_at, ?) WHERE id = ? AND user_id = ?;
+--
+
+-- name: MarkAllNotificationsRead :exec
+UPDATE notifications SET read_at = ? WHERE user_id = ? AND read_at IS NULL
+AND (deliver_at IS NULL OR deliver_at <= sqlc.arg(now));
+--
+
+-- name: GetNotificationPreferences :many
+SELECT * FROM notification_preferences WHERE user_id = ?;
+--
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, event_type, channel, enabled)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id, event_type, channel) DO UPDATE SET enabled = excluded.enabled;
+--
+
+-- name: DeleteNotificationPreferences :exec
+DELETE FROM notification_preferences WHERE user_id = ?;
+--
+
+-- name: GetNotificationQuietHours :one
+SELECT * FROM notification_quiet_hours WHERE user_id = ?;
+--
+
+-- name: UpsertNotificationQuietHours :exec
+INSERT INTO notification_quiet_hours (user_id, start_time, end_time, timezone)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET start_time = excluded.start_time, end_time = excluded.end_time, timezone = excluded.timezone;
+--
+
+-- name: DeleteNotificationQuietHours :exec
+DELETE FROM notification_quiet_hours WHERE user_id = ?;
+--
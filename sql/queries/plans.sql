@@ -0,0 +1,50 @@
+-- name: GetPlan :one
+SELECT * FROM plans WHERE id = ?;
+--
+
+-- name: ListPlans :many
+SELECT * FROM plans ORDER BY id;
+--
+
+-- name: GetPlanForUser :one
+SELECT * FROM plans
+WHERE id = COALESCE((SELECT plan_id FROM user_plans WHERE user_id = ?), 'free');
+--
+
+-- name: SetUserPlan :exec
+INSERT INTO user_plans (user_id, plan_id, updated_at)
+VALUES (?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET plan_id = excluded.plan_id, updated_at = excluded.updated_at,
+    trial_ends_at = NULL, trial_warned_at = NULL, read_only_since = NULL;
+--
+
+-- name: StartTrial :exec
+INSERT INTO user_plans (user_id, plan_id, updated_at, trial_ends_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET plan_id = excluded.plan_id, updated_at = excluded.updated_at,
+    trial_ends_at = excluded.trial_ends_at, trial_warned_at = NULL, read_only_since = NULL;
+--
+
+-- name: GetUserPlan :one
+SELECT * FROM user_plans WHERE user_id = ?;
+--
+
+-- name: GetTrialsEndingBefore :many
+SELECT * FROM user_plans WHERE trial_ends_at <= ? AND trial_warned_at IS NULL;
+--
+
+-- name: MarkTrialWarned :exec
+UPDATE user_plans SET trial_warned_at = ? WHERE user_id = ?;
+--
+
+-- name: GetExpiredTrials :many
+SELECT * FROM user_plans WHERE trial_ends_at <= ?;
+--
+
+-- name: ExpireTrial :execrows
+UPDATE user_plans
+SET plan_id = 'free', trial_ends_at = NULL, trial_warned_at = NULL, read_only_since = ?, updated_at = ?
+WHERE user_id = ? AND trial_ends_at <= ?;
+--
@@ -0,0 +1,49 @@
+-- name: AddNoteReaction :exec
+INSERT INTO note_reactions (note_id, user_id, emoji, created_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (note_id, user_id, emoji) DO NOTHING;
+--
+
+-- name: RemoveNoteReaction :exec
+DELETE FROM note_reactions WHERE note_id = ? AND user_id = ? AND emoji = ?;
+--
+
+-- name: GetNoteReactionCounts :many
+SELECT emoji, COUNT(*) AS count FROM note_reactions
+WHERE note_id = ?
+GROUP BY emoji
+ORDER BY MIN(created_at), emoji;
+--
+
+-- name: GetNoteReactionCountsForUser :many
+SELECT note_reactions.note_id, note_reactions.emoji, COUNT(*) AS count FROM note_reactions
+JOIN notes ON notes.id = note_reactions.note_id
+WHERE notes.user_id = ?
+GROUP BY note_reactions.note_id, note_reactions.emoji
+ORDER BY note_reactions.note_id, MIN(note_reactions.created_at), note_reactions.emoji;
+--
+
+-- name: AddCommentReaction :exec
+INSERT INTO comment_reactions (comment_id, user_id, emoji, created_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (comment_id, user_id, emoji) DO NOTHING;
+--
+
+-- name: RemoveCommentReaction :exec
+DELETE FROM comment_reactions WHERE comment_id = ? AND user_id = ? AND emoji = ?;
+--
+
+-- name: GetCommentReactionCounts :many
+SELECT emoji, COUNT(*) AS count FROM comment_reactions
+WHERE comment_id = ?
+GROUP BY emoji
+ORDER BY MIN(created_at), emoji;
+--
+
+-- name: GetCommentReactionCountsForNote :many
+SELECT comment_reactions.comment_id, comment_reactions.emoji, COUNT(*) AS count FROM comment_reactions
+JOIN note_comments ON note_comments.id = comment_reactions.comment_id
+WHERE note_comments.note_id = ?
+GROUP BY comment_reactions.comment_id, comment_reactions.emoji
+ORDER BY comment_reactions.comment_id, MIN(comment_reactions.created_at), comment_reactions.emoji;
+--
@@ -0,0 +1,18 @@
+-- name: GetSitemapURLs :many
+-- Public profiles and the published notes their authors haven't asked to
+-- keep out of search engines, as site paths.
+SELECT CAST(path AS TEXT) AS path, CAST(lastmod AS TEXT) AS lastmod FROM (
+    SELECT '/u/' || username AS path, updated_at AS lastmod FROM users
+    WHERE profile_public AND username IS NOT NULL
+    UNION ALL
+    SELECT '/site/' || user_id || '/' || id, updated_at FROM notes
+    WHERE published_at IS NOT NULL AND NOT noindex AND deleted_at IS NULL
+)
+ORDER BY path
+LIMIT ? OFFSET ?;
+--
+
+-- name: CountSitemapURLs :one
+SELECT (SELECT COUNT(*) FROM users WHERE profile_public AND username IS NOT NULL)
+    + (SELECT COUNT(*) FROM notes WHERE published_at IS NOT NULL AND NOT noindex AND deleted_at IS NULL);
+--
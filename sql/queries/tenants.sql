@@ -0,0 +1,19 @@
+-- name: GetTenantLocation :one
+SELECT database_url, region FROM tenants WHERE id = ?;
+--
+
+-- name: ListTenants :many
+SELECT * FROM tenants ORDER BY id;
+--
+
+-- name: UpsertTenant :one
+INSERT INTO tenants (id, database_url, region, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE
+SET database_url = excluded.database_url, region = excluded.region, updated_at = excluded.updated_at
+RETURNING *;
+--
+
+-- name: DeleteTenant :execrows
+DELETE FROM tenants WHERE id = ?;
+--
@@ -0,0 +1,34 @@
+-- name: AddTenantAPICalls :exec
+INSERT INTO tenant_usage (tenant_id, day, api_calls)
+VALUES (?, ?, ?)
+ON CONFLICT (tenant_id, day) DO UPDATE
+SET api_calls = api_calls + excluded.api_calls;
+--
+
+-- name: SetTenantStorage :exec
+INSERT INTO tenant_usage (tenant_id, day, notes, storage_bytes)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (tenant_id, day) DO UPDATE
+SET notes = excluded.notes, storage_bytes = excluded.storage_bytes;
+--
+
+-- name: GetTenantUsage :many
+SELECT
+    tenant_id,
+    CAST(SUM(api_calls) AS INTEGER) AS api_calls,
+    CAST(COALESCE(MAX(notes), 0) AS INTEGER) AS notes,
+    CAST(COALESCE(MAX(storage_bytes), 0) AS INTEGER) AS storage_bytes
+FROM tenant_usage
+WHERE day >= sqlc.arg(period_start) AND day < sqlc.arg(period_end)
+GROUP BY tenant_id
+ORDER BY tenant_id;
+--
+
+-- name: GetStorageUsage :one
+SELECT
+    (SELECT COUNT(*) FROM notes) AS notes,
+    CAST(
+        (SELECT COALESCE(SUM(LENGTH(CAST(note AS BLOB)) + LENGTH(CAST(title AS BLOB))), 0) FROM notes)
+        + (SELECT COALESCE(SUM(LENGTH(CAST(body AS BLOB))), 0) FROM note_comments)
+    AS INTEGER) AS storage_bytes;
+--
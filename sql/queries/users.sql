@@ -12,3 +12,40 @@ VALUES (
 -- name: GetUser :one
 SELECT * FROM users WHERE api_key = ?;
 --
+
+-- name: GetUserByID :one
+SELECT * FROM users WHERE id = ?;
+--
+
+-- name: GetUserByAPIKey :one
+SELECT users.* FROM users
+JOIN api_keys ON api_keys.user_id = users.id
+WHERE api_keys.api_key = ?
+AND (api_keys.expires_at IS NULL OR api_keys.expires_at > sqlc.arg(now));
+--
+
+-- name: UpdateUserAPIKey :exec
+UPDATE users SET api_key = ?, updated_at = ? WHERE id = ?;
+--
+
+-- name: GetUserByUsername :one
+SELECT * FROM users WHERE username = ?;
+--
+
+-- name: SetUserProfilePublic :exec
+UPDATE users SET profile_public = ?, updated_at = ? WHERE id = ?;
+--
+
+-- name: UpdateUsername :exec
+UPDATE users SET username = ?, username_changed_at = ?, updated_at = ? WHERE id = ?;
+--
+
+-- name: ListUsers :many
+SELECT users.id, users.created_at, users.name, users.username,
+    (SELECT COUNT(*) FROM notes WHERE notes.user_id = users.id) AS notes,
+    (SELECT COUNT(*) FROM api_keys WHERE api_keys.user_id = users.id
+        AND (api_keys.expires_at IS NULL OR api_keys.expires_at > sqlc.arg(now))) AS active_keys
+FROM users
+ORDER BY users.created_at DESC, users.id
+LIMIT ? OFFSET ?;
+--
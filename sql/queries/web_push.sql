@@ -0,0 +1,32 @@
+-- name: CreateVAPIDKey :exec
+INSERT INTO vapid_keys (id, created_at, private_key)
+VALUES (1, ?, ?)
+ON CONFLICT(id) DO NOTHING;
+--
+
+-- name: GetVAPIDKey :one
+SELECT private_key FROM vapid_keys WHERE id = 1;
+--
+
+-- name: UpsertWebPushSubscription :exec
+INSERT INTO web_push_subscriptions (id, created_at, user_id, endpoint, p256dh, auth)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(endpoint) DO UPDATE SET user_id = excluded.user_id, p256dh = excluded.p256dh, auth = excluded.auth;
+--
+
+-- name: GetWebPushSubscriptionByEndpoint :one
+SELECT * FROM web_push_subscriptions WHERE endpoint = ?;
+--
+
+-- name: GetWebPushSubscriptionsForUser :many
+SELECT * FROM web_push_subscriptions WHERE user_id = ?
+ORDER BY created_at, id;
+--
+
+-- name: DeleteWebPushSubscription :execrows
+DELETE FROM web_push_subscriptions WHERE id = ? AND user_id = ?;
+--
+
+-- name: DeleteWebPushSubscriptionByEndpoint :exec
+DELETE FROM web_push_subscriptions WHERE endpoint = ?;
+--
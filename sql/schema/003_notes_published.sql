@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE notes ADD COLUMN published_at TEXT;
+
+-- +goose Down
+ALTER TABLE notes DROP COLUMN published_at;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    api_key TEXT UNIQUE NOT NULL,
+    expires_at TEXT,
+    superseded_by TEXT REFERENCES api_keys(id)
+);
+
+INSERT INTO api_keys (id, created_at, user_id, api_key)
+SELECT lower(hex(randomblob(16))), created_at, id, api_key FROM users;
+
+-- +goose Down
+DROP TABLE api_keys;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE note_embeddings (
+    note_id TEXT PRIMARY KEY REFERENCES notes(id) ON DELETE CASCADE,
+    model TEXT NOT NULL,
+    embedding BLOB NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE note_embeddings;
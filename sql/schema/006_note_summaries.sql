@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE note_summaries (
+    note_id TEXT PRIMARY KEY REFERENCES notes(id) ON DELETE CASCADE,
+    content_hash TEXT NOT NULL,
+    model TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE note_summaries;
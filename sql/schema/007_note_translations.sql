@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE note_translations (
+    note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    translation TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (note_id, language)
+);
+
+-- +goose Down
+DROP TABLE note_translations;
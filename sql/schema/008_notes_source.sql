@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE notes ADD COLUMN source_url TEXT;
+ALTER TABLE notes ADD COLUMN source_title TEXT;
+
+-- +goose Down
+ALTER TABLE notes DROP COLUMN source_title;
+ALTER TABLE notes DROP COLUMN source_url;
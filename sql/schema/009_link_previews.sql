@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE note_links (
+    note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (note_id, url)
+);
+
+CREATE TABLE link_previews (
+    url TEXT PRIMARY KEY,
+    title TEXT,
+    description TEXT,
+    image_url TEXT,
+    site_name TEXT,
+    fetched_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE link_previews;
+DROP TABLE note_links;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE link_statuses (
+    url TEXT PRIMARY KEY,
+    alive BOOLEAN NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    checked_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE link_statuses;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE notes ADD COLUMN expires_at TEXT;
+ALTER TABLE notes ADD COLUMN expiry_warned_at TEXT;
+
+-- +goose Down
+ALTER TABLE notes DROP COLUMN expiry_warned_at;
+ALTER TABLE notes DROP COLUMN expires_at;
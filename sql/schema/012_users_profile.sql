@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN username TEXT;
+ALTER TABLE users ADD COLUMN profile_public BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE UNIQUE INDEX users_username_key ON users(username);
+
+-- +goose Down
+DROP INDEX users_username_key;
+ALTER TABLE users DROP COLUMN profile_public;
+ALTER TABLE users DROP COLUMN username;
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN username_changed_at TEXT;
+UPDATE users SET username = lower(username) WHERE username IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN username_changed_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE note_comments (
+    id TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL,
+    note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL
+);
+
+CREATE INDEX note_comments_note_id_idx ON note_comments(note_id, created_at);
+
+-- +goose Down
+DROP TABLE note_comments;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE note_reactions (
+    note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (note_id, user_id, emoji)
+);
+
+CREATE TABLE comment_reactions (
+    comment_id TEXT NOT NULL REFERENCES note_comments(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (comment_id, user_id, emoji)
+);
+
+-- +goose Down
+DROP TABLE comment_reactions;
+DROP TABLE note_reactions;
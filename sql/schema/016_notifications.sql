@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE notifications (
+    id TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    note_id TEXT REFERENCES notes(id) ON DELETE CASCADE,
+    comment_id TEXT REFERENCES note_comments(id) ON DELETE CASCADE,
+    read_at TEXT
+);
+
+CREATE INDEX notifications_user_id_idx ON notifications(user_id, created_at);
+
+CREATE TABLE notification_preferences (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, channel)
+);
+
+-- +goose Down
+DROP TABLE notification_preferences;
+DROP TABLE notifications;
@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE notification_preferences_new (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event_type, channel)
+);
+
+-- Per-channel settings applied to every type of notification.
+INSERT INTO notification_preferences_new (user_id, event_type, channel, enabled)
+SELECT user_id, types.event_type, channel, enabled FROM notification_preferences
+CROSS JOIN (SELECT 'comment.created' AS event_type UNION ALL SELECT 'note.expiring') AS types;
+
+DROP TABLE notification_preferences;
+ALTER TABLE notification_preferences_new RENAME TO notification_preferences;
+
+-- +goose Down
+CREATE TABLE notification_preferences_old (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, channel)
+);
+
+-- A channel stays on if any type of notification was left on.
+INSERT INTO notification_preferences_old (user_id, channel, enabled)
+SELECT user_id, channel, MAX(enabled) FROM notification_preferences
+GROUP BY user_id, channel;
+
+DROP TABLE notification_preferences;
+ALTER TABLE notification_preferences_old RENAME TO notification_preferences;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE notification_quiet_hours (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    timezone TEXT NOT NULL
+);
+
+ALTER TABLE notifications ADD COLUMN deliver_at TEXT;
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN deliver_at;
+DROP TABLE notification_quiet_hours;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE device_tokens (
+    id TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    token TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX device_tokens_user_id_idx ON device_tokens(user_id);
+
+-- +goose Down
+DROP TABLE device_tokens;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE web_push_subscriptions (
+    id TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endpoint TEXT NOT NULL UNIQUE,
+    p256dh TEXT NOT NULL,
+    auth TEXT NOT NULL
+);
+
+CREATE INDEX web_push_subscriptions_user_id_idx ON web_push_subscriptions(user_id);
+
+-- The generated VAPID key, shared by every instance so subscriptions keep
+-- working across restarts. Unused when WEB_PUSH_VAPID_PRIVATE_KEY is set.
+CREATE TABLE vapid_keys (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    created_at TEXT NOT NULL,
+    private_key TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE vapid_keys;
+DROP TABLE web_push_subscriptions;
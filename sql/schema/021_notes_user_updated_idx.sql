@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX notes_user_id_updated_at_idx ON notes(user_id, updated_at);
+
+-- +goose Down
+DROP INDEX notes_user_id_updated_at_idx;
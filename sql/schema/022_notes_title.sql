@@ -0,0 +1,17 @@
+-- +goose Up
+-- Titles are derived from the first line of the note when it's saved, and
+-- stored so title lookups can use an index. NOCASE lets LIKE prefix
+-- matches use it too.
+ALTER TABLE notes ADD COLUMN title TEXT NOT NULL DEFAULT '' COLLATE NOCASE;
+
+-- Existing notes get the first non-blank line, cut to 80 characters.
+UPDATE notes SET title = COALESCE(NULLIF(substr(trim(substr(
+    ltrim(note, char(9, 10, 13, 32)), 1,
+    instr(ltrim(note, char(9, 10, 13, 32)) || char(10), char(10)) - 1
+), char(9, 13, 32)), 1, 80), ''), 'Untitled');
+
+CREATE INDEX notes_user_id_title_idx ON notes(user_id, title);
+
+-- +goose Down
+DROP INDEX notes_user_id_title_idx;
+ALTER TABLE notes DROP COLUMN title;
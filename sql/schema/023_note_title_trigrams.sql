@@ -0,0 +1,14 @@
+-- +goose Up
+-- Trigrams of note titles for fuzzy title suggestions. Only filled in when
+-- FUZZY_TITLE_SEARCH is on, since it takes far more space than the titles.
+CREATE TABLE note_title_trigrams (
+    note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    trigram TEXT NOT NULL,
+    PRIMARY KEY (note_id, trigram)
+);
+
+CREATE INDEX note_title_trigrams_user_id_trigram_idx ON note_title_trigrams(user_id, trigram);
+
+-- +goose Down
+DROP TABLE note_title_trigrams;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Runs of POST /admin/rebuild. last_note_id is saved after every batch so
+-- an interrupted rebuild picks up where it stopped.
+CREATE TABLE index_rebuilds (
+    id TEXT PRIMARY KEY,
+    derived TEXT NOT NULL,
+    last_note_id TEXT NOT NULL DEFAULT '',
+    notes_done INTEGER NOT NULL DEFAULT 0,
+    started_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    finished_at TEXT
+);
+
+-- +goose Down
+DROP TABLE index_rebuilds;
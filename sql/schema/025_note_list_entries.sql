@@ -0,0 +1,50 @@
+-- +goose Up
+-- The fields lists of notes show, without the note bodies, so listing the
+-- notes of a large account reads a few compact pages instead of every
+-- note. Triggers keep it in step with notes on every write, including
+-- deletes, whether or not foreign keys are enforced.
+CREATE TABLE note_list_entries (
+    note_id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    title TEXT NOT NULL COLLATE NOCASE,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    published_at TEXT
+);
+
+CREATE INDEX note_list_entries_user_id_title_idx ON note_list_entries(user_id, title, note_id);
+CREATE INDEX note_list_entries_user_id_published_at_idx ON note_list_entries(user_id, published_at);
+
+-- Existing notes.
+INSERT INTO note_list_entries (note_id, user_id, title, created_at, updated_at, published_at)
+SELECT id, user_id, title, created_at, updated_at, published_at FROM notes;
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_list_entry_insert AFTER INSERT ON notes
+BEGIN
+    INSERT INTO note_list_entries (note_id, user_id, title, created_at, updated_at, published_at)
+    VALUES (NEW.id, NEW.user_id, NEW.title, NEW.created_at, NEW.updated_at, NEW.published_at);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_list_entry_update AFTER UPDATE OF title, updated_at, published_at ON notes
+BEGIN
+    UPDATE note_list_entries
+    SET title = NEW.title, updated_at = NEW.updated_at, published_at = NEW.published_at
+    WHERE note_id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_list_entry_delete AFTER DELETE ON notes
+BEGIN
+    DELETE FROM note_list_entries WHERE note_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER notes_list_entry_delete;
+DROP TRIGGER notes_list_entry_update;
+DROP TRIGGER notes_list_entry_insert;
+DROP TABLE note_list_entries;
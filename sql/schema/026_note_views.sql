@@ -0,0 +1,11 @@
+-- +goose Up
+-- How often each published note's page has been viewed. Views are counted
+-- in memory and added here in batches.
+CREATE TABLE note_views (
+    note_id TEXT PRIMARY KEY REFERENCES notes(id) ON DELETE CASCADE,
+    views INTEGER NOT NULL DEFAULT 0,
+    last_viewed_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE note_views;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Published notes the author wants kept out of search engines. They're left
+-- out of the sitemap and their pages ask crawlers not to index them.
+ALTER TABLE notes ADD COLUMN noindex BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE notes DROP COLUMN noindex;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Pages of GET /v1/notes sorted by updated_at, with the ID breaking ties.
+DROP INDEX notes_user_id_updated_at_idx;
+CREATE INDEX notes_user_id_updated_at_idx ON notes(user_id, updated_at, id);
+
+-- +goose Down
+DROP INDEX notes_user_id_updated_at_idx;
+CREATE INDEX notes_user_id_updated_at_idx ON notes(user_id, updated_at);
@@ -0,0 +1,47 @@
+-- +goose Up
+-- Full-text index of note titles and bodies for GET /v1/notes/search. Its
+-- rowids are the notes' own, so triggers can keep it in step without
+-- scanning it; searches also match the note ID, so rowids renumbered by a
+-- VACUUM can't return the wrong note, and a "search" rebuild repairs it.
+CREATE VIRTUAL TABLE notes_fts USING fts5(
+    note_id UNINDEXED,
+    title,
+    note,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+-- Rank by BM25 with a match in the title worth ten in the body.
+INSERT INTO notes_fts (notes_fts, rank) VALUES ('rank', 'bm25(0.0, 10.0, 1.0)');
+
+-- Existing notes.
+INSERT INTO notes_fts (rowid, note_id, title, note)
+SELECT rowid, id, title, note FROM notes;
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_fts_insert AFTER INSERT ON notes
+BEGIN
+    INSERT INTO notes_fts (rowid, note_id, title, note)
+    VALUES (NEW.rowid, NEW.id, NEW.title, NEW.note);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_fts_update AFTER UPDATE OF title, note ON notes
+BEGIN
+    INSERT OR REPLACE INTO notes_fts (rowid, note_id, title, note)
+    VALUES (NEW.rowid, NEW.id, NEW.title, NEW.note);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_fts_delete AFTER DELETE ON notes
+BEGIN
+    DELETE FROM notes_fts WHERE rowid = OLD.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER notes_fts_delete;
+DROP TRIGGER notes_fts_update;
+DROP TRIGGER notes_fts_insert;
+DROP TABLE notes_fts;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- The database of each tenant of a multi-tenant deployment. Only the
+-- default database's copy is used; tenant databases get the table too since
+-- they're migrated alike, but leave it empty.
+CREATE TABLE tenants (
+    id TEXT PRIMARY KEY,
+    database_url TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE tenants;
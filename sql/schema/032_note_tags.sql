@@ -0,0 +1,14 @@
+-- +goose Up
+-- Tags users put on their notes, normalized to lowercase. The user ID is
+-- repeated so a user's tags can be listed and counted without the notes.
+CREATE TABLE note_tags (
+    note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (note_id, tag)
+);
+
+CREATE INDEX note_tags_user_id_tag_idx ON note_tags(user_id, tag);
+
+-- +goose Down
+DROP TABLE note_tags;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- What each tenant used per day (UTC), for billing: API calls are added up
+-- as they're flushed, while notes and storage_bytes are the latest
+-- measurement that day, NULL until one is taken. The default database is
+-- tenant ''. Like tenants, only the default database's copy is used.
+CREATE TABLE tenant_usage (
+    tenant_id TEXT NOT NULL,
+    day TEXT NOT NULL,
+    api_calls INTEGER NOT NULL DEFAULT 0,
+    notes INTEGER,
+    storage_bytes INTEGER,
+    PRIMARY KEY (tenant_id, day)
+);
+
+-- +goose Down
+DROP TABLE tenant_usage;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Notebooks organize a user's notes, and can be nested in one another like
+-- folders. A note is in at most one notebook.
+CREATE TABLE notebooks (
+    id TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id TEXT REFERENCES notebooks(id) ON DELETE CASCADE,
+    name TEXT NOT NULL
+);
+
+-- Names are unique among the notebooks in the same notebook, or at the top.
+CREATE UNIQUE INDEX notebooks_user_parent_name_idx ON notebooks(user_id, COALESCE(parent_id, ''), name);
+
+ALTER TABLE notes ADD COLUMN notebook_id TEXT REFERENCES notebooks(id) ON DELETE SET NULL;
+
+CREATE INDEX notes_notebook_id_idx ON notes(notebook_id);
+
+-- +goose Down
+DROP INDEX notes_notebook_id_idx;
+ALTER TABLE notes DROP COLUMN notebook_id;
+DROP TABLE notebooks;
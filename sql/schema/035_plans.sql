@@ -0,0 +1,25 @@
+-- +goose Up
+-- What each plan allows. A NULL limit is unlimited. Plans are only
+-- enforced with ENFORCE_PLANS; edit these rows to change their limits.
+CREATE TABLE plans (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    max_notes INTEGER,
+    max_attachment_bytes INTEGER,
+    max_collaborators INTEGER
+);
+
+INSERT INTO plans (id, name, max_notes, max_attachment_bytes, max_collaborators) VALUES
+    ('free', 'Free', 100, 5242880, 0),
+    ('pro', 'Pro', NULL, 104857600, 10);
+
+-- Users without a row here are on the free plan.
+CREATE TABLE user_plans (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    plan_id TEXT NOT NULL REFERENCES plans(id),
+    updated_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE user_plans;
+DROP TABLE plans;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Deleting a note moves it to the trash, from which it can be restored or
+-- deleted for good. Trashed notes are left out of everything else, so
+-- their list entries are removed while they're in the trash.
+ALTER TABLE notes ADD COLUMN deleted_at TEXT;
+
+CREATE INDEX notes_user_deleted_at_idx ON notes(user_id, deleted_at);
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_list_entry_trash AFTER UPDATE OF deleted_at ON notes
+WHEN OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL
+BEGIN
+    DELETE FROM note_list_entries WHERE note_id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_list_entry_restore AFTER UPDATE OF deleted_at ON notes
+WHEN OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL
+BEGIN
+    INSERT INTO note_list_entries (note_id, user_id, title, created_at, updated_at, published_at)
+    VALUES (NEW.id, NEW.user_id, NEW.title, NEW.created_at, NEW.updated_at, NEW.published_at);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER notes_list_entry_restore;
+DROP TRIGGER notes_list_entry_trash;
+DROP INDEX notes_user_deleted_at_idx;
+ALTER TABLE notes DROP COLUMN deleted_at;
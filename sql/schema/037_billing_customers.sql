@@ -0,0 +1,15 @@
+-- +goose Up
+-- The Stripe customer each paying user is, and the state of their
+-- subscription as of the latest webhook event applied. Events can arrive
+-- out of order, so older ones than last_event_at (Unix seconds) are
+-- ignored.
+CREATE TABLE billing_customers (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    stripe_customer_id TEXT NOT NULL UNIQUE,
+    subscription_status TEXT,
+    last_event_at INTEGER NOT NULL DEFAULT 0,
+    updated_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE billing_customers;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Archived notes are left out of the note list unless it's asked for them
+-- with ?archived=true, but are otherwise kept like any other note.
+ALTER TABLE notes ADD COLUMN archived_at TEXT;
+
+CREATE INDEX notes_user_archived_at_idx ON notes(user_id, archived_at);
+
+-- +goose Down
+DROP INDEX notes_user_archived_at_idx;
+ALTER TABLE notes DROP COLUMN archived_at;
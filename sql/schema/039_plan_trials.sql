@@ -0,0 +1,15 @@
+-- +goose Up
+-- A trial puts a user on a plan until trial_ends_at, when they go back to
+-- free. Their notes are kept, but the account is read-only from
+-- read_only_since until they're put on a plan again.
+ALTER TABLE user_plans ADD COLUMN trial_ends_at TEXT;
+ALTER TABLE user_plans ADD COLUMN trial_warned_at TEXT;
+ALTER TABLE user_plans ADD COLUMN read_only_since TEXT;
+
+CREATE INDEX user_plans_trial_ends_at_idx ON user_plans(trial_ends_at);
+
+-- +goose Down
+DROP INDEX user_plans_trial_ends_at_idx;
+ALTER TABLE user_plans DROP COLUMN read_only_since;
+ALTER TABLE user_plans DROP COLUMN trial_warned_at;
+ALTER TABLE user_plans DROP COLUMN trial_ends_at;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- The data residency region a tenant picked, if any. Its database is in
+-- that region, which is recorded with its security events.
+ALTER TABLE tenants ADD COLUMN region TEXT;
+
+-- +goose Down
+ALTER TABLE tenants DROP COLUMN region;
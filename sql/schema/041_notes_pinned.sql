@@ -0,0 +1,9 @@
+-- +goose Up
+-- Pinned notes come first in the default, newest first, note list.
+ALTER TABLE notes ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX notes_user_id_pinned_created_at_idx ON notes(user_id, pinned, created_at, id);
+
+-- +goose Down
+DROP INDEX notes_user_id_pinned_created_at_idx;
+ALTER TABLE notes DROP COLUMN pinned;
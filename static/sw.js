@@ -0,0 +1,20 @@
+// Service worker for Web Push: shows notifications sent by the server even
+// when no Notely tab is open.
+self.addEventListener('push', event => {
+    const message = event.data ? event.data.json() : {};
+    event.waitUntil(self.registration.showNotification(message.title || 'Notely', {
+        body: message.body,
+        data: message.data,
+        tag: message.data && message.data.notification_id,
+    }));
+});
+
+self.addEventListener('notificationclick', event => {
+    event.notification.close();
+    event.waitUntil(self.clients.matchAll({ type: 'window' }).then(windows => {
+        if (windows.length > 0) {
+            return windows[0].focus();
+        }
+        return self.clients.openWindow('/');
+    }));
+});
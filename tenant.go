@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/tenancy"
+)
+
+// openDatabase connects to the database at url, the default one or a
+// tenant's, returning it along with a DBTX that logs slow calls and counts
+// failed ones as configured.
+func (cfg *apiConfig) openDatabase(url string) (*sql.DB, database.DBTX, error) {
+	db, err := sql.Open("libsql", url)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Log database calls slower than SLOW_QUERY_THRESHOLD, with a sample of their query plans.
+	var dbtx database.DBTX = db
+	if threshold := durationFromEnv("SLOW_QUERY_THRESHOLD", defaultSlowQueryThreshold); threshold > 0 {
+		dbtx = database.NewSlowQueryLog(db, threshold, durationFromEnv("SLOW_QUERY_PLAN_INTERVAL", defaultSlowQueryPlanInterval))
+	}
+	// Count failed database calls towards the database error alert.
+	if cfg.Alerts != nil {
+		dbtx = database.NewErrorHook(dbtx, func(error) { cfg.Alerts.RecordDBError() })
+	}
+	return db, dbtx, nil
+}
+
+// lookupTenant returns the database URL and region of tenant id from the
+// tenants table.
+func (cfg *apiConfig) lookupTenant(ctx context.Context, id string) (tenancy.Location, error) {
+	row, err := cfg.defaultDB.GetTenantLocation(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return tenancy.Location{}, tenancy.ErrUnknownTenant
+	}
+	if err != nil {
+		return tenancy.Location{}, err
+	}
+	return tenancy.Location{DatabaseURL: row.DatabaseUrl, Region: row.Region.String}, nil
+}
+
+// middlewareTenant runs requests for a tenant, named by the subdomain of
+// TENANT_DOMAIN they were sent to or their X-Tenant header, against the
+// tenant's database. Other requests use the default database.
+func (cfg *apiConfig) middlewareTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := tenancy.FromRequest(r, cfg.TenantDomain)
+		if id == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		conn, err := cfg.Tenants.Get(r.Context(), id)
+		if errors.Is(err, tenancy.ErrUnknownTenant) {
+			respondWithError(w, http.StatusNotFound, "Unknown tenant", err)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't connect to tenant database", err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenancy.WithConn(r.Context(), conn)))
+	})
+}
+
+// conn returns the connection ctx's statements run on: its tenant's
+// database, or the default one. Transactions must be started on it.
+func (cfg *apiConfig) conn(ctx context.Context) *sql.DB {
+	if conn := tenancy.FromContext(ctx); conn != nil {
+		return conn.DB
+	}
+	return cfg.Conn
+}
+
+// tenantID returns the tenant ctx is for, or "" for the default database.
+func tenantID(ctx context.Context) string {
+	if conn := tenancy.FromContext(ctx); conn != nil {
+		return conn.ID
+	}
+	return ""
+}
+
+// tenantUserKey identifies userID in caches shared by all tenants. User IDs
+// are only unique within a tenant's database: a tenant restored from
+// another's backup has the same ones.
+func tenantUserKey(ctx context.Context, userID string) string {
+	return tenantID(ctx) + "/" + userID
+}
+
+// tenantContext returns ctx for tenant id, e.g. to finish work queued by
+// one of its requests. An empty id is the default database.
+func (cfg *apiConfig) tenantContext(ctx context.Context, id string) (context.Context, error) {
+	if id == "" {
+		return ctx, nil
+	}
+	conn, err := cfg.Tenants.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tenancy.WithConn(ctx, conn), nil
+}
+
+// forEachTenant calls fn for the default database and then, if tenants
+// have databases of their own, for each of them, for background jobs that
+// must run everywhere. A tenant whose database can't be reached is skipped
+// until the next run.
+func (cfg *apiConfig) forEachTenant(ctx context.Context, fn func(ctx context.Context)) {
+	fn(ctx)
+	if cfg.Tenants == nil {
+		return
+	}
+	tenants, err := cfg.defaultDB.ListTenants(ctx)
+	if err != nil {
+		log.Printf("Couldn't list tenants: %v", err)
+		return
+	}
+	for _, tenant := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		tenantCtx, err := cfg.tenantContext(ctx, tenant.ID)
+		if err != nil {
+			log.Printf("Couldn't connect to database of tenant %s: %v", tenant.ID, err)
+			continue
+		}
+		fn(tenantCtx)
+	}
+}
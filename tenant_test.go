@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/tenancy"
+)
+
+func TestTenantUserKey(t *testing.T) {
+	ctx := context.Background()
+	acme := tenancy.WithConn(ctx, &tenancy.Conn{ID: "acme"})
+	globex := tenancy.WithConn(ctx, &tenancy.Conn{ID: "globex"})
+
+	// A tenant restored from another's backup has the same user IDs.
+	keys := map[string]bool{}
+	for _, ctx := range []context.Context{ctx, acme, globex} {
+		keys[tenantUserKey(ctx, "user")] = true
+	}
+	if len(keys) != 3 {
+		t.Errorf("tenantUserKey gave %d distinct keys for 3 tenants: %v", len(keys), keys)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/events"
+)
+
+// runTrialExpiry ends trials, in every tenant's database, every interval
+// until ctx is done: their users go back to the free plan, and their
+// accounts become read-only rather than losing the notes past its limits.
+// When a trial is within warning of ending, a trial.ending event is
+// published and the user notified, once per trial; 0 disables warnings.
+func (cfg *apiConfig) runTrialExpiry(ctx context.Context, interval, warning time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cfg.forEachTenant(ctx, func(ctx context.Context) {
+			if warning > 0 {
+				cfg.warnEndingTrials(ctx, warning)
+			}
+			cfg.expireTrials(ctx)
+		})
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cfg *apiConfig) warnEndingTrials(ctx context.Context, warning time.Duration) {
+	now := cfg.Clock.Now().UTC()
+	trials, err := cfg.DB.GetTrialsEndingBefore(ctx, sql.NullString{String: now.Add(warning).Format(time.RFC3339), Valid: true})
+	if err != nil {
+		log.Printf("Couldn't get ending trials: %v", err)
+		return
+	}
+	for _, trial := range trials {
+		cfg.publish(ctx, events.Event{Type: events.TypeTrialEnding, UserID: trial.UserID})
+		cfg.notify(ctx, trial.UserID, events.TypeTrialEnding, "", "")
+		err := cfg.DB.MarkTrialWarned(ctx, database.MarkTrialWarnedParams{
+			TrialWarnedAt: sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+			UserID:        trial.UserID,
+		})
+		if err != nil {
+			log.Printf("Couldn't mark trial warning for user %s: %v", trial.UserID, err)
+		}
+	}
+}
+
+func (cfg *apiConfig) expireTrials(ctx context.Context) {
+	now := sql.NullString{String: cfg.Clock.Now().UTC().Format(time.RFC3339), Valid: true}
+	trials, err := cfg.DB.GetExpiredTrials(ctx, now)
+	if err != nil {
+		log.Printf("Couldn't get expired trials: %v", err)
+		return
+	}
+	expired := 0
+	for _, trial := range trials {
+		// The user may have upgraded since the trials were read, which
+		// leaves them without a trial to end.
+		n, err := cfg.DB.ExpireTrial(ctx, database.ExpireTrialParams{
+			ReadOnlySince: now,
+			UpdatedAt:     now.String,
+			UserID:        trial.UserID,
+			TrialEndsAt:   now,
+		})
+		if err != nil {
+			log.Printf("Couldn't end trial of user %s: %v", trial.UserID, err)
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		expired++
+		cfg.publish(ctx, events.Event{Type: events.TypeTrialEnded, UserID: trial.UserID})
+		cfg.notify(ctx, trial.UserID, events.TypeTrialEnded, "", "")
+	}
+	if expired > 0 {
+		log.Printf("Ended %d trials", expired)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/trigram"
+)
+
+// titleTrigramBatchSize is how many notes runTitleTrigramBackfill indexes per query.
+const titleTrigramBatchSize = 500
+
+// indexNoteTitle stores the trigrams of a note's title for fuzzy title
+// suggestions, if they're enabled. Like embeddings they're derived data, so
+// failures are only logged.
+func (cfg *apiConfig) indexNoteTitle(ctx context.Context, noteID, userID, title string) {
+	if !cfg.FuzzyTitleSearch {
+		return
+	}
+	for _, t := range trigram.Trigrams(title) {
+		err := cfg.DB.InsertNoteTitleTrigram(ctx, database.InsertNoteTitleTrigramParams{
+			NoteID:  noteID,
+			UserID:  userID,
+			Trigram: t,
+		})
+		if err != nil {
+			log.Printf("Couldn't index title of note %s: %v", noteID, err)
+			return
+		}
+	}
+}
+
+// runTitleTrigramBackfill brings the trigram table in line with
+// FUZZY_TITLE_SEARCH at startup: it indexes notes written while fuzzy search
+// was off, or empties the table to give the space back once it's turned off.
+func (cfg *apiConfig) runTitleTrigramBackfill(ctx context.Context) {
+	if !cfg.FuzzyTitleSearch {
+		if err := cfg.DB.DeleteAllNoteTitleTrigrams(ctx); err != nil {
+			log.Printf("Couldn't clear title trigrams: %v", err)
+		}
+		return
+	}
+
+	indexed, after := 0, ""
+	for ctx.Err() == nil {
+		notes, err := cfg.DB.GetNotesWithoutTitleTrigrams(ctx, database.GetNotesWithoutTitleTrigramsParams{
+			ID:    after,
+			Limit: titleTrigramBatchSize,
+		})
+		if err != nil {
+			log.Printf("Couldn't get notes to index titles of: %v", err)
+			return
+		}
+		for _, note := range notes {
+			cfg.indexNoteTitle(ctx, note.ID, note.UserID, note.Title)
+		}
+		indexed += len(notes)
+		if len(notes) < titleTrigramBatchSize {
+			break
+		}
+		// Titles without letters or digits have no trigrams and would be
+		// picked up again, so page by ID rather than rely on the filter.
+		after = notes[len(notes)-1].ID
+	}
+	if indexed > 0 {
+		log.Printf("Indexed titles of %d notes for fuzzy search", indexed)
+	}
+}
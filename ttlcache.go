@@ -0,0 +1,80 @@
+package main
+
+import (
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+)
+
+// ttlMap holds up to size entries, each for ttl after it was put. It's the
+// storage behind the auth and palette caches, which do their own locking.
+//
+// When the map is full, put first drops expired entries and then, if that
+// wasn't enough, all of them. dropped, if set, is called for every entry
+// removed that way, so indexes kept next to the map stay in step.
+type ttlMap[K comparable, V any] struct {
+	clock   clock.Clock
+	ttl     time.Duration
+	size    int
+	dropped func(K, V)
+	entries map[K]ttlEntry[V]
+}
+
+type ttlEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func newTTLMap[K comparable, V any](ttl time.Duration, size int, clock clock.Clock, dropped func(K, V)) *ttlMap[K, V] {
+	return &ttlMap[K, V]{
+		clock:   clock,
+		ttl:     ttl,
+		size:    size,
+		dropped: dropped,
+		entries: make(map[K]ttlEntry[V]),
+	}
+}
+
+// get returns the value put for key, unless it has expired.
+func (m *ttlMap[K, V]) get(key K) (V, bool) {
+	entry, ok := m.entries[key]
+	if !ok || m.clock.Now().After(entry.expires) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (m *ttlMap[K, V]) put(key K, value V) {
+	if _, ok := m.entries[key]; !ok && len(m.entries) >= m.size {
+		m.evictExpired()
+		if len(m.entries) >= m.size {
+			// Still full of live entries; starting over is cheaper than tracking
+			// recency for entries that expire within seconds anyway.
+			m.evict(func(ttlEntry[V]) bool { return true })
+		}
+	}
+	m.entries[key] = ttlEntry[V]{value: value, expires: m.clock.Now().Add(m.ttl)}
+}
+
+// delete drops key without calling dropped; the caller keeps its own
+// indexes in step.
+func (m *ttlMap[K, V]) delete(key K) {
+	delete(m.entries, key)
+}
+
+func (m *ttlMap[K, V]) evictExpired() {
+	now := m.clock.Now()
+	m.evict(func(entry ttlEntry[V]) bool { return now.After(entry.expires) })
+}
+
+func (m *ttlMap[K, V]) evict(match func(ttlEntry[V]) bool) {
+	for key, entry := range m.entries {
+		if match(entry) {
+			delete(m.entries, key)
+			if m.dropped != nil {
+				m.dropped(key, entry.value)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/clock"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/tenancy"
+)
+
+func TestTTLMap(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	var dropped []string
+	m := newTTLMap(time.Minute, 2, fake, func(key string, _ int) { dropped = append(dropped, key) })
+
+	m.put("a", 1)
+	fake.Advance(30 * time.Second)
+	m.put("b", 2)
+	if v, ok := m.get("a"); !ok || v != 1 {
+		t.Errorf("get(a) = %d, %v; want 1, true", v, ok)
+	}
+
+	// Full, but a has expired.
+	fake.Advance(31 * time.Second)
+	if _, ok := m.get("a"); ok {
+		t.Error("get(a) found an expired entry")
+	}
+	m.put("c", 3)
+	if !slices.Equal(dropped, []string{"a"}) {
+		t.Errorf("dropped %v, want [a]", dropped)
+	}
+
+	// Full of live entries.
+	dropped = nil
+	m.put("d", 4)
+	slices.Sort(dropped)
+	if !slices.Equal(dropped, []string{"b", "c"}) {
+		t.Errorf("dropped %v, want [b c]", dropped)
+	}
+	if v, ok := m.get("d"); !ok || v != 4 {
+		t.Errorf("get(d) = %d, %v; want 4, true", v, ok)
+	}
+
+	// Replacing an entry doesn't make room, and delete doesn't report drops.
+	dropped = nil
+	m.put("d", 5)
+	m.delete("d")
+	if len(dropped) != 0 || len(m.entries) != 0 {
+		t.Errorf("dropped %v with %d entries left, want neither", dropped, len(m.entries))
+	}
+}
+
+func TestAuthCacheForget(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	c := newAuthCache(time.Minute, 1, fake)
+	acme := tenancy.WithConn(context.Background(), &tenancy.Conn{ID: "acme"})
+	globex := tenancy.WithConn(context.Background(), &tenancy.Conn{ID: "globex"})
+
+	// The same user ID in two tenants, as after restoring a backup.
+	_, gen, _ := c.get(acme, "acme-key")
+	c.put(acme, "acme-key", database.User{ID: "user", ApiKey: "acme-key"}, gen)
+	c.forget(globex, "user")
+	if _, _, ok := c.get(acme, "acme-key"); !ok {
+		t.Error("forgetting the user in globex dropped them in acme")
+	}
+	c.forget(acme, "user")
+	if _, _, ok := c.get(acme, "acme-key"); ok {
+		t.Error("forgetting the user in acme kept their key")
+	}
+
+	// Making room drops the index entry too.
+	_, gen, _ = c.get(acme, "acme-key")
+	c.put(acme, "acme-key", database.User{ID: "user", ApiKey: "acme-key"}, gen)
+	c.put(globex, "globex-key", database.User{ID: "other", ApiKey: "globex-key"}, gen)
+	if _, ok := c.byUser[tenantUserKey(acme, "user")]; ok || len(c.byUser) != 1 {
+		t.Errorf("byUser = %v, want only globex's user", c.byUser)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/database"
+	"github.com/DanielSiebert-dev/learn-cicd-starter/internal/metering"
+)
+
+// middlewareUsage counts each API request towards its tenant's usage.
+func (cfg *apiConfig) middlewareUsage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg.Usage.Record(tenantID(r.Context()))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// runUsageFlushes writes counted API calls to the database every interval
+// until ctx is done. Calls counted after that are written by the final
+// flushUsage on shutdown.
+func (cfg *apiConfig) runUsageFlushes(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := cfg.flushUsage(ctx); err != nil {
+			log.Printf("Couldn't flush API call counts: %v", err)
+		}
+	}
+}
+
+// flushUsage adds the counted API calls to the default database. Counts
+// that couldn't be written are kept for the next flush.
+func (cfg *apiConfig) flushUsage(ctx context.Context) error {
+	var failed []metering.Calls
+	var errs []error
+	for _, calls := range cfg.Usage.Take() {
+		err := cfg.defaultDB.AddTenantAPICalls(ctx, database.AddTenantAPICallsParams{
+			TenantID: calls.Tenant,
+			Day:      calls.Day,
+			ApiCalls: calls.Calls,
+		})
+		if err != nil {
+			failed = append(failed, calls)
+			errs = append(errs, err)
+		}
+	}
+	cfg.Usage.Restore(failed)
+	return errors.Join(errs...)
+}
+
+// runUsageMeasurements records how many notes, and how many bytes of notes
+// and comments, each tenant stores every interval until ctx is done. A
+// period is billed for the most measured on any of its days.
+func (cfg *apiConfig) runUsageMeasurements(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cfg.forEachTenant(ctx, cfg.measureStorage)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cfg *apiConfig) measureStorage(ctx context.Context) {
+	usage, err := cfg.DB.GetStorageUsage(ctx)
+	if err != nil {
+		log.Printf("Couldn't measure storage of tenant %q: %v", tenantID(ctx), err)
+		return
+	}
+	err = cfg.defaultDB.SetTenantStorage(ctx, database.SetTenantStorageParams{
+		TenantID:     tenantID(ctx),
+		Day:          metering.Today(cfg.Clock.Now()),
+		Notes:        sql.NullInt64{Int64: usage.Notes, Valid: true},
+		StorageBytes: sql.NullInt64{Int64: usage.StorageBytes, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Couldn't save storage of tenant %q: %v", tenantID(ctx), err)
+	}
+}
+
+// handlerAdminUsageGet exports each tenant's usage in the billing period
+// ?period= (YYYY-MM, the current month by default) as JSON, or as CSV with
+// ?format=csv, for an external billing system. The current period is
+// still growing, and lags the last flush of API calls.
+func (cfg *apiConfig) handlerAdminUsageGet(w http.ResponseWriter, r *http.Request) error {
+	period, err := metering.ParsePeriod(r.URL.Query().Get("period"), cfg.Clock.Now())
+	if err != nil {
+		return errValidation(err.Error(), nil)
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		return errValidation("format must be json or csv", nil)
+	}
+
+	rows, err := cfg.defaultDB.GetTenantUsage(r.Context(), database.GetTenantUsageParams{
+		PeriodStart: period.Start,
+		PeriodEnd:   period.End,
+	})
+	if err != nil {
+		return errInternal("Couldn't get usage", err)
+	}
+	usage := make([]TenantUsage, len(rows))
+	for i, row := range rows {
+		usage[i] = TenantUsage{
+			Tenant:       row.TenantID,
+			Period:       period.Name,
+			APICalls:     row.ApiCalls,
+			Notes:        row.Notes,
+			StorageBytes: row.StorageBytes,
+		}
+	}
+
+	if format != "csv" {
+		respondWithJSONList(w, http.StatusOK, usage)
+		return nil
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="usage-`+period.Name+`.csv"`)
+	out := csv.NewWriter(w)
+	out.Write([]string{"tenant", "period", "api_calls", "notes", "storage_bytes"})
+	for _, u := range usage {
+		out.Write([]string{
+			u.Tenant,
+			u.Period,
+			strconv.FormatInt(u.APICalls, 10),
+			strconv.FormatInt(u.Notes, 10),
+			strconv.FormatInt(u.StorageBytes, 10),
+		})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		log.Printf("Couldn't write usage export: %v", err)
+	}
+	return nil
+}